@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// anomalyWarmup is the number of minutes of history needed before deviations are reported
+	anomalyWarmup = 5
+	// anomalyWeight is the weight given to the latest minute in the rolling baseline
+	anomalyWeight = 0.2
+	// anomalyMaxCatchUp bounds the number of silent minutes closed at once after a long pause
+	anomalyMaxCatchUp = 60
+)
+
+var errAnomalyFactor = errors.New("rate anomaly factor must be greater than 1")
+
+// RateAnomaly is a minute in which the number of entries at Level deviated from the rolling
+// baseline by more than the factor given to WithRateAnomalyAlert
+type RateAnomaly struct {
+	Level    string
+	Minute   time.Time
+	Count    int
+	Baseline float64
+}
+
+func (a RateAnomaly) String() string {
+	return fmt.Sprintf("%d %s entries in the minute from %s against a baseline of %.1f per minute",
+		a.Count, a.Level, a.Minute.Format(time.RFC3339), a.Baseline)
+}
+
+type levelRate struct {
+	minute   time.Time
+	count    int
+	baseline float64
+	minutes  int
+	tripped  bool
+}
+
+type rateAnomaly struct {
+	mu     sync.Mutex
+	factor float64
+	alert  func(a RateAnomaly)
+	levels map[string]*levelRate
+}
+
+// observe counts an entry at level and closes the minutes that have ended for every level,
+// returning the anomalies found in them
+func (r *rateAnomaly) observe(level string, now time.Time) []RateAnomaly {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	minute := now.Truncate(time.Minute)
+	var anomalies []RateAnomaly
+	for name, lr := range r.levels {
+		anomalies = append(anomalies, r.close(name, lr, minute)...)
+	}
+	level = strings.ToUpper(level)
+	lr, ok := r.levels[level]
+	if !ok {
+		lr = &levelRate{minute: minute}
+		r.levels[level] = lr
+	}
+	lr.count++
+	return anomalies
+}
+
+// close compares each minute of lr before minute with its baseline, then folds it in
+func (r *rateAnomaly) close(level string, lr *levelRate, minute time.Time) []RateAnomaly {
+	var anomalies []RateAnomaly
+	for i := 0; lr.minute.Before(minute); i++ {
+		if i == anomalyMaxCatchUp {
+			lr.minute = minute
+			break
+		}
+		count := float64(lr.count)
+		deviates := lr.minutes >= anomalyWarmup && (count > math.Max(lr.baseline, 1)*r.factor ||
+			(lr.baseline >= r.factor && count < lr.baseline/r.factor))
+		if deviates && !lr.tripped {
+			anomalies = append(anomalies, RateAnomaly{Level: level, Minute: lr.minute, Count: lr.count, Baseline: lr.baseline})
+		}
+		lr.tripped = deviates
+		if lr.minutes == 0 {
+			lr.baseline = count
+		} else {
+			lr.baseline += anomalyWeight * (count - lr.baseline)
+		}
+		lr.minutes++
+		lr.minute = lr.minute.Add(time.Minute)
+		lr.count = 0
+	}
+	return anomalies
+}
+
+// reportAnomalies passes anomalies to the alert, or writes each as a warning without one
+func (l *Log) reportAnomalies(anomalies []RateAnomaly) {
+	for _, a := range anomalies {
+		if l.anomaly.alert != nil {
+			l.anomaly.alert(a)
+		} else {
+			l.writeEntry(l.newEntry(WARNING, "log rate anomaly: "+a.String()))
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateAnomalyAlert(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	var anomalies []RateAnomaly
+	c := NewCapture(WithNow(func() time.Time { return now }), WithRateAnomalyAlert(3, func(a RateAnomaly) {
+		anomalies = append(anomalies, a)
+	}))
+	for minute := 0; minute < 8; minute++ {
+		count := 10
+		if minute == 6 {
+			count = 50
+		}
+		for i := 0; i < count; i++ {
+			c.Info("request")
+		}
+		now = now.Add(time.Minute)
+	}
+	c.Info("request")
+	if len(anomalies) != 1 {
+		t.Fatalf("expected a single anomaly, got %v", anomalies)
+	}
+	a := anomalies[0]
+	if a.Level != INFO || a.Count != 50 || a.Baseline < 9 || a.Baseline > 11 || !a.Minute.Equal(time.Date(2024, 1, 2, 0, 6, 0, 0, time.UTC)) {
+		t.Errorf("unexpected anomaly %v", a)
+	}
+	now = now.Add(10 * time.Minute)
+	c.Info("request")
+	if len(anomalies) != 2 || anomalies[1].Count != 1 {
+		t.Errorf("expected the drop to be a single anomaly, got %v", anomalies)
+	}
+}
+
+func TestRateAnomalyWarning(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	c := NewCapture(WithNow(func() time.Time { return now }), WithRateAnomalyAlert(2, nil))
+	for minute := 0; minute < 7; minute++ {
+		count := 1
+		if minute == 6 {
+			count = 20
+		}
+		for i := 0; i < count; i++ {
+			c.Error("failed")
+		}
+		now = now.Add(time.Minute)
+	}
+	c.Error("failed")
+	if !c.HasEntry(WARNING, "log rate anomaly: 20 ERROR entries") {
+		t.Errorf("expected an anomaly warning, got %v", c.Entries())
+	}
+	if _, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithRateAnomalyAlert(1, nil)); err != errAnomalyFactor {
+		t.Errorf("expected a factor error, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"crypto/rand"
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+)
+
+var errIPPrefix = errors.New("IP anonymization prefix out of range")
+
+var (
+	ipv4Candidate = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	// IPv6 addresses are matched loosely, with the character before them so that they do not
+	// start inside a word, and checked by parsing
+	ipv6Candidate = regexp.MustCompile(`(?i)(?:^|[^0-9a-z:])(?:[0-9a-f]{0,4}:){2,7}[0-9a-f.]*`)
+)
+
+// IPAnonymization says how WithIPAnonymization anonymizes IP addresses
+type IPAnonymization struct {
+	// IPv4Prefix is the number of leading bits of IPv4 addresses kept, the rest being zeroed
+	// (default 24, so 192.0.2.42 becomes 192.0.2.0)
+	IPv4Prefix int
+	// IPv6Prefix is the number of leading bits of IPv6 addresses kept (default 48)
+	IPv6Prefix int
+	// Hash replaces the addresses with a keyed hash of the truncated address instead, as
+	// WithHashedFields does; see WithHashKey
+	Hash bool
+}
+
+// anonymizer replaces the IP addresses in text as set by an IPAnonymization
+type anonymizer struct {
+	IPAnonymization
+	l *Log
+}
+
+func (a anonymizer) replaceIPv4(match string) string {
+	ip := net.ParseIP(match)
+	if ip == nil || ip.To4() == nil {
+		return match
+	}
+	return a.anonymize(ip)
+}
+
+func (a anonymizer) replaceIPv6(match string) string {
+	start := strings.IndexFunc(match, func(r rune) bool {
+		return r == ':' || strings.ContainsRune("0123456789abcdefABCDEF", r)
+	})
+	ip := net.ParseIP(strings.TrimRight(match[start:], "."))
+	if ip == nil {
+		return match
+	}
+	end := start + len(strings.TrimRight(match[start:], "."))
+	return match[:start] + a.anonymize(ip) + match[end:]
+}
+
+func (a anonymizer) anonymize(ip net.IP) string {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4.Mask(net.CIDRMask(a.IPv4Prefix, 8*net.IPv4len))
+	} else {
+		ip = ip.Mask(net.CIDRMask(a.IPv6Prefix, 8*net.IPv6len))
+	}
+	if a.Hash {
+		return hashValue(a.l.hashKey, ip.String())
+	}
+	return ip.String()
+}
+
+// ipRedactions returns the redactions anonymizing IPv6 and IPv4 addresses for the log
+func (l *Log) ipRedactions(opts IPAnonymization) []redaction {
+	a := anonymizer{IPAnonymization: opts, l: l}
+	return []redaction{
+		{pattern: ipv6Candidate, replace: a.replaceIPv6},
+		{pattern: ipv4Candidate, replace: a.replaceIPv4},
+	}
+}
+
+// validate applies the defaults and checks the prefix lengths
+func (opts *IPAnonymization) validate() error {
+	if opts.IPv4Prefix == 0 {
+		opts.IPv4Prefix = 24
+	}
+	if opts.IPv6Prefix == 0 {
+		opts.IPv6Prefix = 48
+	}
+	if opts.IPv4Prefix < 0 || opts.IPv4Prefix > 32 || opts.IPv6Prefix < 0 || opts.IPv6Prefix > 128 {
+		return errIPPrefix
+	}
+	return nil
+}
+
+// newHashKey generates the random key used when WithHashKey is not
+func newHashKey() []byte {
+	key := make([]byte, hashKeySize)
+	rand.Read(key)
+	return key
+}
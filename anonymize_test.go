@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIPAnonymization(t *testing.T) {
+	c := NewCapture(WithIPAnonymization(IPAnonymization{}))
+	c.WithFields(Fields{"client": "203.0.113.195"}).Info("GET / from 192.0.2.42:51234 via [2001:db8:85a3::8a2e:370:7334]:443 at 10:04:05")
+	e := c.Entries()[0]
+	if e.Message != "GET / from 192.0.2.0:51234 via [2001:db8:85a3::]:443 at 10:04:05" {
+		t.Errorf("unexpected message %s", e.Message)
+	}
+	if v, _ := e.Field("client"); v != "203.0.113.0" {
+		t.Errorf("expected the field to be anonymized, got %v", v)
+	}
+
+	c = NewCapture(WithIPAnonymization(IPAnonymization{IPv4Prefix: 16, Hash: true}), WithHashKey([]byte("key")))
+	c.Info("from 192.0.2.42 and 192.0.7.1, not 999.1.1.1 or Foo::Bar")
+	message := c.Entries()[0].Message
+	hash := hashValue([]byte("key"), "192.0.0.0")
+	if message != "from "+hash+" and "+hash+", not 999.1.1.1 or Foo::Bar" {
+		t.Errorf("unexpected message %s", message)
+	}
+	if strings.Contains(message, "192.0") {
+		t.Errorf("expected no address to be left, got %s", message)
+	}
+
+	if _, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithIPAnonymization(IPAnonymization{IPv6Prefix: 129})); err != errIPPrefix {
+		t.Errorf("expected an invalid prefix to be refused, got %v", err)
+	}
+}
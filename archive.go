@@ -0,0 +1,152 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Archiver stores rotated log files offsite; see WithArchive
+type Archiver interface {
+	Archive(name string, r io.Reader, size int64) error
+}
+
+type archive struct {
+	archiver    Archiver
+	deleteAfter bool
+}
+
+// archiveFile passes a rotated file to the archiver, removing it afterwards if configured
+func (l *Log) archiveFile(name string) error {
+	file, err := l.getFS().OpenFile(name, os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	err = l.archive.archiver.Archive(filepath.Base(name), file, info.Size())
+	file.Close()
+	if err != nil {
+		return err
+	}
+	if l.archive.deleteAfter {
+		return l.getFS().Remove(name)
+	}
+	return nil
+}
+
+// S3Archiver uploads files to a bucket on Amazon S3 or an S3-compatible store such as MinIO,
+// using path-style URLs and AWS Signature Version 4. Objects are named Prefix followed by the
+// file name, e.g. a Prefix of "logs/app/" gives "logs/app/app.log.20240102T150405"
+type S3Archiver struct {
+	Endpoint  string // e.g. https://s3.eu-west-1.amazonaws.com
+	Region    string
+	Bucket    string
+	Prefix    string
+	AccessKey string
+	SecretKey string
+	Client    *http.Client // http.DefaultClient if nil; see NewHTTPClient for TLS and proxies
+}
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4Service    = "s3"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// Archive uploads the file with a single PUT request
+func (a *S3Archiver) Archive(name string, r io.Reader, size int64) error {
+	u, err := url.Parse(strings.TrimSuffix(a.Endpoint, "/"))
+	if err != nil {
+		return err
+	}
+	key := a.Bucket + "/" + a.Prefix + name
+	u.RawPath = u.EscapedPath() + "/" + uriEncode(key)
+	u.Path += "/" + key
+	req, err := http.NewRequest(http.MethodPut, u.String(), r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	a.sign(req, time.Now().UTC())
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("s3 upload of %s failed: %s: %s", key, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// sign adds the Signature Version 4 headers to a request. The payload is not hashed, so
+// files can be streamed
+func (a *S3Archiver) sign(req *http.Request, t time.Time) {
+	stamp, date := t.Format("20060102T150405Z"), t.Format("20060102")
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	signed := "host;x-amz-content-sha256;x-amz-date"
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + unsignedPayload,
+		"x-amz-date:" + stamp,
+		"",
+		signed,
+		unsignedPayload,
+	}, "\n")
+	scope := date + "/" + a.Region + "/" + sigV4Service + "/aws4_request"
+	hash := sha256.Sum256([]byte(canonical))
+	toSign := sigV4Algorithm + "\n" + stamp + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+	signature := hmacSHA256(signingKey(a.SecretKey, date, a.Region, sigV4Service), toSign)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, a.AccessKey, scope, signed, hex.EncodeToString(signature)))
+}
+
+func signingKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode percent-encodes a path as Signature Version 4 requires, leaving slashes intact
+func uriEncode(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestS3Archive(t *testing.T) {
+	var path, auth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		path, auth, body = r.URL.EscapedPath(), r.Header.Get("Authorization"), string(b)
+		if r.Method != http.MethodPut || r.Header.Get("X-Amz-Content-Sha256") != unsignedPayload {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+	fs := NewMemFS()
+	archiver := &S3Archiver{Endpoint: srv.URL, Region: "eu-west-1", Bucket: "logs", Prefix: "app one/", AccessKey: "AKID", SecretKey: "secret"}
+	al, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithArchive(archiver, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	al.Info("archived")
+	rotated, err := al.Rotate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := rotated[strings.LastIndexByte(rotated, '/')+1:]
+	if path != "/logs/app%20one/"+name {
+		t.Errorf("unexpected object path %s", path)
+	}
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/eu-west-1/s3/aws4_request") {
+		t.Errorf("unexpected authorization header %s", auth)
+	}
+	if !strings.Contains(body, "archived") {
+		t.Errorf("expected the rotated file to be uploaded, got %q", body)
+	}
+	if _, err = fs.Stat(rotated); err == nil {
+		t.Errorf("expected %s to be removed after upload", rotated)
+	}
+}
+
+func TestS3ArchiveFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+	}))
+	defer srv.Close()
+	fs := NewMemFS()
+	al, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs),
+		WithArchive(&S3Archiver{Endpoint: srv.URL, Bucket: "logs"}, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var handled error
+	al.SetErrorHandler(func(err error) { handled = err })
+	rotated, err := al.Rotate()
+	if err == nil || !errors.Is(handled, err) {
+		t.Errorf("expected the failed upload to be returned and handled, got %v and %v", err, handled)
+	}
+	if _, err = fs.Stat(rotated); err != nil {
+		t.Errorf("expected %s to be kept after a failed upload", rotated)
+	}
+}
+
+func TestSigningKey(t *testing.T) {
+	// from the AWS Signature Version 4 documentation
+	key := signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	if got := hex.EncodeToString(key); got != "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d" {
+		t.Errorf("unexpected signing key %s", got)
+	}
+}
@@ -0,0 +1,177 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DropPolicy determines which entry is dropped when the async queue is full
+type DropPolicy int
+
+const (
+	// DropNewest drops the entry being written
+	DropNewest DropPolicy = iota
+	// DropOldest drops the oldest queued entry to make room
+	DropOldest
+)
+
+// droppedReportInterval is how often the number of dropped entries is written to the log
+const droppedReportInterval = 10 * time.Second
+
+// drainPollInterval is how often Drain checks whether the queue is empty
+const drainPollInterval = 5 * time.Millisecond
+
+type asyncItem struct {
+	e      Entry
+	msg    []byte
+	queued int64 // when the entry was queued, in Unix nanoseconds
+}
+
+type asyncQueue struct {
+	l        *Log
+	ch       chan asyncItem
+	policy   DropPolicy
+	pending  atomic.Int64
+	mu       sync.Mutex
+	times    []int64 // when each entry in ch was queued, oldest first
+	inflight int64   // when the entry being written was queued, or zero
+	dropped  atomic.Uint64
+	reported uint64
+	stop     chan struct{}
+}
+
+func newAsyncQueue(l *Log, size int, policy DropPolicy) *asyncQueue {
+	if size < 1 {
+		size = 1
+	}
+	q := &asyncQueue{
+		l:      l,
+		ch:     make(chan asyncItem, size),
+		policy: policy,
+		stop:   make(chan struct{}),
+	}
+	return q
+}
+
+// enqueue adds an entry to the queue without blocking
+func (q *asyncQueue) enqueue(e Entry, msg []byte) {
+	item := asyncItem{e: e, msg: msg, queued: time.Now().UnixNano()}
+	q.pending.Add(1)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.send(item) {
+		return
+	}
+	if q.policy == DropOldest {
+		select {
+		case <-q.ch:
+			q.times = q.times[1:]
+			q.drop()
+		default:
+		}
+		if q.send(item) {
+			return
+		}
+	}
+	q.drop()
+}
+
+// send queues the item if there is room. It is called with the lock held, so that times
+// stays in the order of ch
+func (q *asyncQueue) send(item asyncItem) bool {
+	select {
+	case q.ch <- item:
+		q.times = append(q.times, item.queued)
+		return true
+	default:
+		return false
+	}
+}
+
+func (q *asyncQueue) drop() {
+	q.pending.Add(-1)
+	q.dropped.Add(1)
+}
+
+func (q *asyncQueue) run() {
+	ticker := time.NewTicker(droppedReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case item := <-q.ch:
+			q.mu.Lock()
+			if len(q.times) > 0 {
+				q.times = q.times[1:]
+			}
+			q.inflight = item.queued
+			q.mu.Unlock()
+			q.l.deliver(item.e, item.msg, nil)
+			q.mu.Lock()
+			q.inflight = 0
+			q.mu.Unlock()
+			q.pending.Add(-1)
+		case <-ticker.C:
+			q.reportDropped()
+		case <-q.stop:
+			return
+		}
+	}
+}
+
+func (q *asyncQueue) reportDropped() {
+	dropped := q.dropped.Load()
+	if dropped == q.reported {
+		return
+	}
+	e := q.l.newEntry(WARNING, fmt.Sprintf("dropped %d entries because the write queue was full", dropped-q.reported))
+	q.reported = dropped
+	// written in place through a copy without the queue, which this goroutine drains
+	direct := *q.l
+	direct.async = nil
+	if _, err := direct.writeEntry(e); err != nil {
+		q.l.handleError(err)
+	}
+}
+
+// Drain waits for queued entries to be written until ctx is done, then stops the background goroutine
+func (q *asyncQueue) Drain(ctx context.Context) (flushed, dropped int, err error) {
+	start := int(q.pending.Load())
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for q.pending.Load() > 0 && err == nil {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	close(q.stop)
+	remaining := int(q.pending.Load())
+	return start - remaining, remaining, err
+}
+
+// stats returns the backlog of the queue
+func (q *asyncQueue) stats() QueueStats {
+	stats := QueueStats{Depth: q.pending.Load(), Dropped: q.dropped.Load()}
+	q.mu.Lock()
+	oldest := q.inflight
+	if oldest == 0 && len(q.times) > 0 {
+		oldest = q.times[0]
+	}
+	q.mu.Unlock()
+	if oldest != 0 {
+		stats.OldestAge = time.Since(time.Unix(0, oldest))
+	}
+	return stats
+}
+
+// Dropped returns the number of entries dropped because the async queue was full; see WithAsync
+func (l *Log) Dropped() uint64 {
+	if l.async == nil {
+		return 0
+	}
+	return l.async.dropped.Load()
+}
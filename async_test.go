@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+)
+
+type slowSink struct {
+	delay   time.Duration
+	entries []Entry
+}
+
+func (s *slowSink) WriteEntry(e Entry) error {
+	time.Sleep(s.delay)
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+func TestAsync(t *testing.T) {
+	sink := &slowSink{delay: 20 * time.Millisecond}
+	al := NewTestLog(t, WithSink(sink), WithAsync(2, DropOldest))
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		al.Info(fmt.Sprintf("entry %d", i))
+	}
+	if time.Since(start) > 50*time.Millisecond {
+		t.Errorf("expected writes not to block, took %s", time.Since(start))
+	}
+	result, err := al.Shutdown(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if al.Dropped() == 0 {
+		t.Error("expected entries to be dropped when the queue was full")
+	}
+	if result.Dropped != 0 || result.Flushed == 0 {
+		t.Errorf("expected queued entries to be flushed on shutdown, got %+v", result)
+	}
+	last := sink.entries[len(sink.entries)-1]
+	if last.Message != "entry 9" {
+		t.Errorf("expected the newest entry to be kept when dropping oldest, got '%s'", last.Message)
+	}
+}
+
+func TestAsyncDroppedWarning(t *testing.T) {
+	sink := &slowSink{delay: 20 * time.Millisecond}
+	al := NewTestLog(t, WithSink(sink), WithSequence(), WithAsync(2, DropNewest))
+	for i := 0; i < 10; i++ {
+		al.Info(fmt.Sprintf("entry %d", i))
+	}
+	if _, err := al.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// reported every ten seconds by the background goroutine, which has now stopped
+	al.async.reportDropped()
+	for _, e := range sink.entries {
+		if e.Level == WARNING {
+			// the warning goes through the write path like any other entry
+			if e.Seq == 0 {
+				t.Error("expected the dropped entries warning to be numbered")
+			}
+			return
+		}
+	}
+	t.Error("expected a warning of the dropped entries")
+}
+
+func TestAsyncNotStartedOnError(t *testing.T) {
+	before := runtime.NumGoroutine()
+	// opening a directory for writing fails before the log is set up
+	if _, err := NewLog(t.TempDir(), "TEST", LEVEL_INFO, LEVEL_NONE, WithQuarantine(), WithAsync(10, DropNewest)); err == nil {
+		t.Fatal("expected the log to fail")
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("expected no goroutine to be left behind, got %d more", after-before)
+	}
+}
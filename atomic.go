@@ -0,0 +1,34 @@
+package logging
+
+import "os"
+
+// pipeBuf is the largest write to a pipe that is not interleaved with other writers on Linux.
+// POSIX only guarantees 512 bytes, as on macOS
+const pipeBuf = 4096
+
+// writeGroups joins records, each ending in a newline, into the buffers to write with one
+// write each. O_APPEND places each write to a regular file at its end, so on a local
+// filesystem the records are written at once. A pipe only keeps writes of up to pipeBuf
+// bytes whole, so records are grouped up to that size and a larger record is written on its
+// own, and may be interleaved with other writers'. Neither holds on a network filesystem,
+// where O_APPEND is emulated by the client; use WithFileLock there. A write cut short is
+// not completed; see appendWhole. It is called with the write lock held
+func (l *Log) writeGroups(records [][]byte) [][]byte {
+	pipe := false
+	if _, err := l.outputFile(); err == nil {
+		pipe = l.writer.info.Mode()&os.ModeNamedPipe != 0
+	}
+	groups := make([][]byte, 0, 1)
+	var group []byte
+	for _, record := range records {
+		if pipe && len(group) > 0 && len(group)+len(record) > pipeBuf {
+			groups = append(groups, group)
+			group = nil
+		}
+		group = append(group, record...)
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+	return groups
+}
@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// shortFS is a MemFS whose files write only half of the next write and fail
+type shortFS struct {
+	*MemFS
+	short bool
+}
+
+func (f *shortFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	file, err := f.MemFS.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &shortFile{File: file, fs: f}, nil
+}
+
+type shortFile struct {
+	File
+	fs *shortFS
+}
+
+func (f *shortFile) Write(b []byte) (int, error) {
+	if !f.fs.short {
+		return f.File.Write(b)
+	}
+	f.fs.short = false
+	n, _ := f.File.Write(b[:len(b)/2])
+	return n, errors.New("short write")
+}
+
+func TestShortWriteFails(t *testing.T) {
+	fs := &shortFS{MemFS: NewMemFS()}
+	sl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithRetry(RetryPolicy{Attempts: 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs.short = true
+	if _, err = sl.Info("cut short"); !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("expected the short write to fail rather than be completed, got %v", err)
+	}
+	if _, err = sl.Info("written whole"); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := sl.readFile("/logs/app.log")
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	if len(lines) != 3 || strings.Contains(lines[1], "cut short") || !strings.HasSuffix(lines[2], "[TEST.INFO] written whole") {
+		t.Errorf("expected the part written to end its own line and the next record to follow whole, got %q", string(b))
+	}
+}
+
+func TestWriteGroupsPipe(t *testing.T) {
+	fs := &countingFS{MemFS: NewMemFS()}
+	f, _ := fs.MemFS.OpenFile("/logs/app.pipe", os.O_CREATE|os.O_WRONLY, os.ModeNamedPipe|0644)
+	f.Close()
+	pl, err := NewLog("/logs/app.pipe", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := make([]Entry, 0)
+	for i := 0; i < 5; i++ {
+		entries = append(entries, Entry{Level: INFO, Message: strings.Repeat("x", 1500)})
+	}
+	entries = append(entries, Entry{Level: INFO, Message: strings.Repeat("y", 5000)})
+	fs.writes = 0
+	if err = pl.WriteBatch(entries); err != nil {
+		t.Fatal(err)
+	}
+	// two records fit in each write up to pipeBuf; the large record is written alone
+	if fs.writes != 4 {
+		t.Errorf("expected 4 writes to the pipe, got %d", fs.writes)
+	}
+}
@@ -0,0 +1,137 @@
+package logging
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+type attrKind int
+
+const (
+	kindAny attrKind = iota
+	kindString
+	kindInt
+	kindFloat
+	kindBool
+	kindDuration
+)
+
+// Attr is a typed key/value pair attached to an entry. Unlike Fields, attributes of
+// primitive types are stored without boxing
+type Attr struct {
+	Key  string
+	kind attrKind
+	str  string
+	num  int64
+	any  interface{}
+}
+
+func String(key, value string) Attr {
+	return Attr{Key: key, kind: kindString, str: value}
+}
+
+func Int(key string, value int) Attr {
+	return Attr{Key: key, kind: kindInt, num: int64(value)}
+}
+
+func Int64(key string, value int64) Attr {
+	return Attr{Key: key, kind: kindInt, num: value}
+}
+
+func Float64(key string, value float64) Attr {
+	return Attr{Key: key, kind: kindFloat, num: int64(math.Float64bits(value))}
+}
+
+func Bool(key string, value bool) Attr {
+	a := Attr{Key: key, kind: kindBool}
+	if value {
+		a.num = 1
+	}
+	return a
+}
+
+func Duration(key string, value time.Duration) Attr {
+	return Attr{Key: key, kind: kindDuration, num: int64(value)}
+}
+
+// Err returns an attribute holding the error under the key "error"
+func Err(err error) Attr {
+	return Any("error", err)
+}
+
+// Any returns an attribute holding an arbitrary value
+func Any(key string, value interface{}) Attr {
+	return Attr{Key: key, kind: kindAny, any: value}
+}
+
+// Value returns the attribute's value
+func (a Attr) Value() interface{} {
+	switch a.kind {
+	case kindString:
+		return a.str
+	case kindInt:
+		return a.num
+	case kindFloat:
+		return math.Float64frombits(uint64(a.num))
+	case kindBool:
+		return a.num == 1
+	case kindDuration:
+		return time.Duration(a.num)
+	}
+	return a.any
+}
+
+func (a Attr) appendValue(dst []byte) []byte {
+	switch a.kind {
+	case kindString:
+		return appendFieldString(dst, a.str)
+	case kindInt:
+		return strconv.AppendInt(dst, a.num, 10)
+	case kindFloat:
+		return strconv.AppendFloat(dst, math.Float64frombits(uint64(a.num)), 'g', -1, 64)
+	case kindBool:
+		return strconv.AppendBool(dst, a.num == 1)
+	case kindDuration:
+		return append(dst, time.Duration(a.num).String()...)
+	}
+	if err, ok := a.any.(error); ok {
+		return appendFieldString(dst, err.Error())
+	}
+	return appendFieldValue(dst, a.any)
+}
+
+// With returns a child log that attaches the given attributes to every entry
+func (l *Log) With(attrs ...Attr) *Log {
+	child := *l
+	child.attrs = append(append(make([]Attr, 0, len(l.attrs)+len(attrs)), l.attrs...), attrs...)
+	return &child
+}
+
+// WriteAttrs writes a message with attributes added to those of the log
+func (l *Log) WriteAttrs(message, level string, attrs ...Attr) (string, error) {
+	if len(attrs) == 0 {
+		return l.Write(message, level)
+	}
+	return l.With(attrs...).Write(message, level)
+}
+
+func (l *Log) ErrorAttrs(message string, attrs ...Attr) (string, error) {
+	return l.WriteAttrs(message, ERROR, attrs...)
+}
+
+func (l *Log) SuccessAttrs(message string, attrs ...Attr) (string, error) {
+	return l.WriteAttrs(message, SUCCESS, attrs...)
+}
+
+func (l *Log) WarningAttrs(message string, attrs ...Attr) (string, error) {
+	return l.WriteAttrs(message, WARNING, attrs...)
+}
+
+func (l *Log) DebugAttrs(message string, attrs ...Attr) (string, error) {
+	return l.WriteAttrs(message, DEBUG, attrs...)
+}
+
+func (l *Log) InfoAttrs(message string, attrs ...Attr) (string, error) {
+	return l.WriteAttrs(message, INFO, attrs...)
+}
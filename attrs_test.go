@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAttrs(t *testing.T) {
+	c := NewCapture(WithMaskedFields("token"))
+	result, _ := c.With(String("user", "jane doe")).InfoAttrs(
+		"request served",
+		Int("status", 200),
+		Float64("ratio", 0.5),
+		Bool("cached", true),
+		Duration("took", 1500*time.Millisecond),
+		Err(errors.New("partial failure")),
+		String("token", "secret"),
+	)
+	expected := `request served user="jane doe" status=200 ratio=0.5 cached=true took=1.5s error="partial failure" token=****`
+	if !strings.HasSuffix(result, expected) {
+		t.Errorf("expected result to end with '%s', got '%s'", expected, result)
+	}
+	e := c.Entries()[0]
+	if v, ok := e.Field("status"); !ok || v != int64(200) {
+		t.Errorf("expected status attribute to be 200, got %v", v)
+	}
+	if !FieldEquals("cached", true)(e) {
+		t.Error("expected FieldEquals to match attributes")
+	}
+}
+
+func TestAttrAllocations(t *testing.T) {
+	al := NewTestLog(t)
+	al.sinks = nil
+	allocs := testing.AllocsPerRun(100, func() {
+		al.InfoAttrs("attrs", Int("count", 3), String("user", "jane"))
+	})
+	// 3 today; the bound leaves headroom for the runtime and toolchain, catching attributes
+	// that are boxed or formatted through fmt. BenchmarkInfoAttrs reports the exact count
+	if allocs > 8 {
+		t.Errorf("expected at most 8 allocations for an entry with attributes, got %.1f", allocs)
+	}
+}
+
+func BenchmarkInfoAttrs(b *testing.B) {
+	bl, err := NewLog(b.TempDir()+"/bench.log", "BENCH", LEVEL_INFO, LEVEL_NONE)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		bl.InfoAttrs("benchmark message", Int("count", i), String("user", "jane"))
+	}
+}
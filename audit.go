@@ -0,0 +1,164 @@
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var chainSuffix = newRecordSuffix("chain")
+
+// recordSuffix is a hash added to the end of a record: a key=value pair on text records and
+// the last member of the object on JSON records, so that they remain valid JSON
+type recordSuffix struct {
+	key        string
+	text, json *regexp.Regexp
+}
+
+func newRecordSuffix(key string) recordSuffix {
+	return recordSuffix{
+		key:  key,
+		text: regexp.MustCompile(` ` + key + `=([0-9a-f]{64})$`),
+		json: regexp.MustCompile(`,"` + key + `":"([0-9a-f]{64})"\}$`),
+	}
+}
+
+// append adds the value to the record
+func (s recordSuffix) append(msg []byte, value string) []byte {
+	if n := len(msg); n > 1 && msg[0] == '{' && msg[n-1] == '}' {
+		// copied rather than appended in place, as msg may still be in use without the value
+		out := append(make([]byte, 0, n+len(s.key)+len(value)+6), msg[:n-1]...)
+		return append(out, `,"`+s.key+`":"`+value+`"}`...)
+	}
+	return append(msg, ` `+s.key+`=`+value...)
+}
+
+// split returns the record as it was before the value was added, and the value
+func (s recordSuffix) split(record string) (string, string, bool) {
+	if m := s.json.FindStringSubmatchIndex(record); m != nil {
+		return record[:m[0]] + "}", record[m[2]:m[3]], true
+	}
+	if m := s.text.FindStringSubmatchIndex(record); m != nil {
+		return record[:m[0]], record[m[2]:m[3]], true
+	}
+	return record, "", false
+}
+
+type hashChain struct {
+	loaded bool
+	prev   string
+	file   File // the file the chain was resumed from
+}
+
+// syncChain makes the chain continue from the last record of the file about to be written to:
+// after a file switch, such as a rotation, it restarts from the new file, and with WithFileLock
+// it is reloaded with the file locked, so that records appended by other processes are chained.
+// The returned function releases the lock. It is called with the write lock held
+func (l *Log) syncChain() (func(), error) {
+	if l.chain == nil {
+		return func() {}, nil
+	}
+	file, err := l.outputFile()
+	if err != nil {
+		return nil, err
+	}
+	if file != l.chain.file {
+		l.chain.file, l.chain.loaded = file, false
+	}
+	if !l.lockFile {
+		return func() {}, nil
+	}
+	unlock, err := lockFile(file)
+	if err != nil {
+		return nil, err
+	}
+	l.chain.loaded = false
+	l.writer.locked = true
+	return func() {
+		l.writer.locked = false
+		unlock()
+	}, nil
+}
+
+// seal appends the chained hash to the record. The chain is resumed from the last record
+// of the file whenever syncChain finds it may have changed, decrypting it if the log is
+// encrypted, so the chain carries on across restarts
+func (c *hashChain) seal(l *Log, msg []byte) ([]byte, error) {
+	if c == nil {
+		return msg, nil
+	}
+	if !c.loaded {
+		last, err := lastLine(l.getFS(), l.path)
+		if err != nil {
+			return nil, err
+		}
+		if l.cipher != nil && strings.HasPrefix(last, encryptedPrefix) {
+			if last, err = l.cipher.decrypt(last); err != nil {
+				return nil, err
+			}
+		}
+		c.prev = ""
+		if _, prev, ok := chainSuffix.split(last); ok {
+			c.prev = prev
+		}
+		c.loaded = true
+	}
+	c.prev = chainHash(c.prev, string(msg))
+	return chainSuffix.append(msg, c.prev), nil
+}
+
+func chainHash(prev, record string) string {
+	sum := sha256.Sum256([]byte(prev + record))
+	return hex.EncodeToString(sum[:])
+}
+
+// splitRecords splits file content into records, keeping the lines of multi-line messages together
+func splitRecords(content string) []string {
+	result := make([]string, 0)
+	node := make([]string, 0)
+	for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if isRecordStart(line) && len(node) > 0 {
+			result = append(result, strings.Join(node, "\n"))
+			node = make([]string, 0)
+		}
+		node = append(node, line)
+	}
+	if len(node) > 0 && node[0] != "" {
+		result = append(result, strings.Join(node, "\n"))
+	}
+	return result
+}
+
+// lastLine returns the last non-empty line of a file, or an empty string if it does not exist
+func lastLine(fs FS, path string) (string, error) {
+	file, err := fs.OpenFile(path, os.O_RDONLY, 0)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	size := stat.Size()
+	for read := int64(4096); ; read *= 2 {
+		if read > size {
+			read = size
+		}
+		b := make([]byte, read)
+		if _, err = file.ReadAt(b, size-read); err != nil && err != io.EOF {
+			return "", err
+		}
+		trimmed := strings.TrimRight(string(b), "\r\n")
+		if i := strings.LastIndex(trimmed, "\n"); i >= 0 || read == size {
+			return trimmed[i+1:], nil
+		}
+	}
+}
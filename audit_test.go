@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHashChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	al, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithHashChain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	al.Info("user created")
+	al.Warning("permission changed\nfrom read\nto write")
+	// a second log on the same file must resume the chain
+	al, err = NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithHashChain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	al.Info("user deleted")
+	if err = Verify(path); err != nil {
+		t.Fatalf("expected chain to verify, got %s", err.Error())
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.Replace(string(b), "to write", "to admin", 1)
+	if err = os.WriteFile(path, []byte(tampered), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = Verify(path)
+	if err == nil || !strings.Contains(err.Error(), "record 3") {
+		t.Errorf("expected verification to fail at record 3, got %v", err)
+	}
+}
+
+func TestHashChainFileSwitch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	al, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithHashChain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	al.Info("before rotation")
+	rotated, err := al.Rotate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	al.Info("after rotation")
+	for _, name := range []string{rotated, path} {
+		if err = Verify(name); err != nil {
+			t.Errorf("expected %s to verify, got %v", name, err)
+		}
+	}
+}
+
+func TestHashChainSharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logs := make([]*Log, 2)
+	for i := range logs {
+		var err error
+		// separate logs stand in for separate processes sharing the file
+		if logs[i], err = NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithHashChain(), WithFileLock()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		logs[0].Info("first")
+		logs[1].Info("second")
+	}
+	if err := Verify(path); err != nil {
+		t.Errorf("expected the records of both logs to be chained, got %v", err)
+	}
+}
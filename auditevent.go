@@ -0,0 +1,19 @@
+package logging
+
+import "fmt"
+
+// AUDIT is the level of audit records. It is not a diagnostic level, so audit records are
+// written whatever the log level
+const AUDIT = "AUDIT"
+
+// Audit writes an audit record of an actor performing an action on a resource, with the
+// details as fields. Audit records bypass sampling and deduplication, so every event is kept
+func (l *Log) Audit(actor, action, resource string, details map[string]interface{}) (string, error) {
+	if l.isClosed() {
+		return "", ErrClosed
+	}
+	attrs := []Attr{String("actor", actor), String("action", action), String("resource", resource)}
+	attrs = append(attrs, fieldAttrs(details)...)
+	e := l.With(attrs...).newEntry(AUDIT, fmt.Sprintf("%s %s %s", actor, action, resource))
+	return l.writeEntry(e)
+}
@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAudit(t *testing.T) {
+	c := NewCapture(WithDedup(time.Minute))
+	c.SetLevel(LevelError)
+	for i := 0; i < 2; i++ {
+		if _, err := c.Audit("alice", "delete", "invoice/42", map[string]interface{}{"reason": "duplicate"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries := c.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected both audit records to be written regardless of level and dedup, got %v", entries)
+	}
+	e := entries[0]
+	if e.Level != AUDIT || e.Message != "alice delete invoice/42" {
+		t.Errorf("expected an audit record, got %s", e)
+	}
+	for key, value := range map[string]string{"actor": "alice", "action": "delete", "resource": "invoice/42", "reason": "duplicate"} {
+		if v, _ := e.Field(key); v != value {
+			t.Errorf("expected %s to be %s, got %v", key, value, v)
+		}
+	}
+}
+
+func TestAuditClosed(t *testing.T) {
+	al, err := NewLog(filepath.Join(t.TempDir(), "audit.log"), "TEST", LEVEL_INFO, LEVEL_NONE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	al.Close()
+	if _, err := al.Audit("bob", "login", "console", nil); err != ErrClosed {
+		t.Errorf("expected auditing a closed log to fail, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type authUserKey struct{}
+
+// AuthUserFromContext returns the name of the user authenticated by TokenAuth or BasicAuth
+func AuthUserFromContext(ctx context.Context) (string, bool) {
+	user, ok := ctx.Value(authUserKey{}).(string)
+	return user, ok
+}
+
+// secretsEqual compares secrets in constant time, hashing them first so that their lengths
+// are not revealed either
+func secretsEqual(a, b string) bool {
+	ha, hb := sha256.Sum256([]byte(a)), sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1
+}
+
+// TokenAuth returns middleware that only lets requests through with an
+// "Authorization: Bearer <token>" header holding one of the tokens, keyed by the name of the
+// user holding it. The name is stashed in the request context; see AuthUserFromContext
+func TokenAuth(next http.Handler, tokens map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+			token := strings.TrimPrefix(header, "Bearer ")
+			for user, t := range tokens {
+				if secretsEqual(token, t) {
+					next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authUserKey{}, user)))
+					return
+				}
+			}
+		}
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	})
+}
+
+// BasicAuth returns middleware that only lets requests through with HTTP basic credentials
+// matching users, a map of user names to passwords. The user name is stashed in the request
+// context; see AuthUserFromContext
+func BasicAuth(next http.Handler, realm string, users map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if expected, known := users[user]; ok && known && secretsEqual(password, expected) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authUserKey{}, user)))
+			return
+		}
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", realm))
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	})
+}
+
+// AllowIPs returns middleware that only lets through requests from the given addresses or
+// CIDR networks, e.g. "10.0.0.0/8" or "127.0.0.1". The remote address of the connection is
+// used; forwarding headers are not trusted
+func AllowIPs(next http.Handler, allowed ...string) (http.Handler, error) {
+	networks := make([]*net.IPNet, 0, len(allowed))
+	for _, a := range allowed {
+		if !strings.Contains(a, "/") {
+			ip := net.ParseIP(a)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address '%s'", a)
+			}
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(a)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			for _, network := range networks {
+				if network.Contains(ip) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+		}
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	}), nil
+}
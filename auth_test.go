@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func authTestHandler(user *string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*user, _ = AuthUserFromContext(r.Context())
+	})
+}
+
+func TestTokenAuth(t *testing.T) {
+	var user string
+	handler := TokenAuth(authTestHandler(&user), map[string]string{"support": "s3cret"})
+	for token, expected := range map[string]int{"": http.StatusUnauthorized, "Bearer wrong": http.StatusUnauthorized, "Bearer s3cret": http.StatusOK} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != expected {
+			t.Errorf("expected %d for %q, got %d", expected, token, rec.Code)
+		}
+	}
+	if user != "support" {
+		t.Errorf("expected the token holder in the context, got '%s'", user)
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	var user string
+	handler := BasicAuth(authTestHandler(&user), "logs", map[string]string{"ops": "hunter2"})
+	req := httptest.NewRequest("GET", "/", nil)
+	req.SetBasicAuth("ops", "wrong")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") != `Basic realm="logs"` {
+		t.Errorf("expected a challenge, got %d and '%s'", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+	req.SetBasicAuth("ops", "hunter2")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || user != "ops" {
+		t.Errorf("expected ops to be let through, got %d and '%s'", rec.Code, user)
+	}
+}
+
+func TestAllowIPs(t *testing.T) {
+	var user string
+	handler, err := AllowIPs(authTestHandler(&user), "10.0.0.0/8", "192.168.1.5", "::1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for addr, expected := range map[string]int{"10.1.2.3:5000": http.StatusOK, "192.168.1.5:80": http.StatusOK, "[::1]:80": http.StatusOK, "192.168.1.6:80": http.StatusForbidden} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != expected {
+			t.Errorf("expected %d from %s, got %d", expected, addr, rec.Code)
+		}
+	}
+	if _, err = AllowIPs(handler, "not-an-ip"); err == nil {
+		t.Error("expected an invalid address to be rejected")
+	}
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// bannerAttrs describes the process and the log's configuration for the startup entry
+func (l *Log) bannerAttrs() []Attr {
+	attrs := []Attr{
+		String("env", l.env),
+		String("log_level", l.Level().String()),
+		String("report_level", l.ReportLevel().String()),
+	}
+	if host, err := os.Hostname(); err == nil {
+		attrs = append(attrs, String("hostname", host))
+	}
+	return append(attrs,
+		Int("pid", os.Getpid()),
+		String("go_version", runtime.Version()),
+		String("config_hash", l.configHash()),
+	)
+}
+
+// configHash returns a short hash of the log's configuration, which changes whenever the
+// path, levels, sinks or write policies do
+func (l *Log) configHash() string {
+	config := []string{
+		"path=" + l.path,
+		"env=" + l.env,
+		fmt.Sprintf("level=%d", l.Level()),
+		fmt.Sprintf("report=%d", l.reportLevel),
+		fmt.Sprintf("mode=%o", l.fileMode),
+		fmt.Sprintf("precision=%d", l.precision),
+		fmt.Sprintf("sanitize=%t", l.sanitize),
+		fmt.Sprintf("chain=%t", l.chain != nil),
+		fmt.Sprintf("encrypt=%t", l.cipher != nil),
+		fmt.Sprintf("sign=%t", len(l.signingKey) > 0),
+		fmt.Sprintf("async=%t", l.async != nil),
+		fmt.Sprintf("lock=%t", l.lockFile),
+		fmt.Sprintf("redactions=%d", len(l.redactions)),
+	}
+	for _, s := range l.sinks {
+		config = append(config, fmt.Sprintf("sink=%T", s))
+	}
+	masks := make([]string, 0, len(l.masks))
+	for key, mode := range l.masks {
+		masks = append(masks, fmt.Sprintf("mask=%s:%d", key, mode))
+	}
+	sort.Strings(masks)
+	sum := sha256.Sum256([]byte(strings.Join(append(config, masks...), "\n")))
+	return hex.EncodeToString(sum[:6])
+}
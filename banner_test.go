@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestStartupBanner(t *testing.T) {
+	var sink captureSink
+	bl := NewTestLog(t, WithSink(&sink), WithStartupBanner())
+	e := sink.entries[0]
+	if e.Message != "initialising log" {
+		t.Fatalf("expected the startup entry first, got %s", e)
+	}
+	expected := map[string]interface{}{
+		"env":          "TEST",
+		"log_level":    INFO,
+		"report_level": NONE,
+		"pid":          int64(os.Getpid()),
+		"go_version":   runtime.Version(),
+		"config_hash":  bl.configHash(),
+	}
+	for key, value := range expected {
+		if v, _ := e.Field(key); v != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, v)
+		}
+	}
+	plain, _ := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE)
+	sanitized, _ := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithSanitize())
+	if plain.configHash() == sanitized.configHash() {
+		t.Error("expected a different configuration to hash differently")
+	}
+	bl.Info("later")
+	if _, ok := sink.entries[1].Field("config_hash"); ok {
+		t.Error("expected only the startup entry to carry the banner")
+	}
+}
@@ -0,0 +1,79 @@
+package logging
+
+// WriteBatch writes several entries to the file with a single write. Entries keep their time,
+// converted to UTC, or are stamped with the current time, and the log's environment and fields
+// are applied as they are by Write. Sampling and duplicate suppression do not apply to batches.
+// Entries with a time must be in order, as for WriteAt
+func (l *Log) WriteBatch(entries []Entry) (err error) {
+	if l.isClosed() {
+		return ErrClosed
+	}
+	prepared := make([]Entry, 0, len(entries))
+	msgs := make([][]byte, 0, len(entries))
+	for _, e := range entries {
+		e, msg, ok := l.prepare(l.complete(e), nil)
+		if !ok {
+			continue
+		}
+		if l.async != nil {
+			l.async.enqueue(e, msg)
+			continue
+		}
+		if sErr := l.writeSinks(e, nil); sErr != nil {
+			l.handleError(sErr)
+			if err == nil {
+				err = sErr
+			}
+		}
+		prepared = append(prepared, e)
+		msgs = append(msgs, msg)
+	}
+	if len(msgs) == 0 {
+		return err
+	}
+	written, wErr := l.writeRecords(msgs)
+	if wErr != nil {
+		l.handleError(wErr)
+		if l.fallback == nil {
+			return wErr
+		}
+		for _, e := range prepared {
+			if fErr := l.fallback.WriteEntry(e); fErr != nil {
+				return wErr
+			}
+		}
+		written = msgs
+	}
+	for i, e := range prepared {
+		l.runPostHooks(e, string(written[i]))
+	}
+	return err
+}
+
+// complete fills in the parts of an entry supplied by the log
+func (l *Log) complete(e Entry) Entry {
+	if e.Time.IsZero() {
+		e.Time = l.now()
+	}
+	e.Time = e.Time.UTC()
+	if e.Env == "" {
+		e.Env = l.env
+	}
+	if e.precision == 0 {
+		e.precision = l.precision
+	}
+	if scoped := l.scopedFields(); len(scoped) > 0 {
+		fields := make(Fields, len(scoped)+len(e.Fields))
+		for k, v := range scoped {
+			fields[k] = v
+		}
+		for k, v := range e.Fields {
+			fields[k] = v
+		}
+		e.Fields = fields
+	}
+	if len(l.attrs) > 0 {
+		e.Attrs = append(append([]Attr{}, l.attrs...), e.Attrs...)
+	}
+	return e
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type countingFS struct {
+	*MemFS
+	writes int
+}
+
+func (f *countingFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	file, err := f.MemFS.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &countingFile{File: file, fs: f}, nil
+}
+
+type countingFile struct {
+	File
+	fs *countingFS
+}
+
+func (f *countingFile) Write(b []byte) (int, error) {
+	f.fs.writes++
+	return f.File.Write(b)
+}
+
+func TestWriteBatch(t *testing.T) {
+	fs := &countingFS{MemFS: NewMemFS()}
+	bl, err := NewLog("/logs/batch.log", "TEST", LEVEL_WARNING, LEVEL_NONE, WithFS(fs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs.writes = 0
+	stamp := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	err = bl.WithFields(Fields{"job": "import"}).WriteBatch([]Entry{
+		{Level: ERROR, Message: "first"},
+		{Level: INFO, Message: "filtered by level"},
+		{Level: WARNING, Message: "second", Time: stamp},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs.writes != 1 {
+		t.Errorf("expected a single write for the batch, got %d", fs.writes)
+	}
+	result, err := bl.GetLog(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	joined := strings.Join(result, "\n")
+	if len(result) != 2 || !strings.Contains(joined, "[2020-01-01T00:00:00Z] [TEST.WARNING] second job=import") || !strings.Contains(joined, "first job=import") {
+		t.Errorf("expected both entries to be written with their fields, got %v", result)
+	}
+}
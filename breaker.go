@@ -0,0 +1,138 @@
+package logging
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by a CircuitBreakerSink while its circuit is open
+var ErrCircuitOpen = errors.New("circuit open")
+
+// BreakerState is the state of a CircuitBreakerSink's circuit
+type BreakerState int
+
+const (
+	// BreakerClosed passes entries to the sink
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects entries without calling the sink
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe entry through to test whether the sink has recovered
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+// BreakerPolicy configures a CircuitBreakerSink
+type BreakerPolicy struct {
+	// Failures is the number of consecutive failures that opens the circuit
+	Failures int
+	// Cooldown is how long the circuit stays open before a probe is let through
+	Cooldown time.Duration
+}
+
+// BreakerStats reports the state and history of a CircuitBreakerSink
+type BreakerStats struct {
+	State    BreakerState
+	Opened   uint64 // times the circuit has opened
+	Rejected uint64 // entries rejected while the circuit was open
+	Failures uint64 // failed writes to the sink
+}
+
+// CircuitBreakerSink wraps a sink, typically a remote one, so that once it has failed
+// Failures times in a row entries are rejected with ErrCircuitOpen instead of waiting on a
+// dead endpoint. After the cooldown a single entry probes the sink, closing the circuit if it
+// succeeds and opening it again if not. Wrap it in a SpillSink to keep rejected entries
+type CircuitBreakerSink struct {
+	mu       sync.Mutex
+	sink     Sink
+	policy   BreakerPolicy
+	failures int
+	openedAt time.Time
+	probing  bool
+	stats    BreakerStats
+}
+
+// NewCircuitBreakerSink returns a CircuitBreakerSink for sink
+func NewCircuitBreakerSink(sink Sink, policy BreakerPolicy) *CircuitBreakerSink {
+	if policy.Failures < 1 {
+		policy.Failures = 1
+	}
+	return &CircuitBreakerSink{sink: sink, policy: policy}
+}
+
+// WriteEntry writes the entry to the wrapped sink, or returns ErrCircuitOpen without calling
+// it while the circuit is open. A success closes the circuit; the circuit opens when the probe
+// fails or, while closed, when the failures in a row reach the policy's limit. Failures of
+// writes still in flight when it opened are counted but do not open it again
+func (s *CircuitBreakerSink) WriteEntry(e Entry) error {
+	allowed, probe := s.allow()
+	if !allowed {
+		return ErrCircuitOpen
+	}
+	err := s.sink.WriteEntry(e)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if probe {
+		s.probing = false
+	}
+	if err == nil {
+		s.failures, s.stats.State = 0, BreakerClosed
+		return nil
+	}
+	s.failures++
+	s.stats.Failures++
+	if probe || (s.stats.State == BreakerClosed && s.failures >= s.policy.Failures) {
+		s.stats.State, s.openedAt = BreakerOpen, time.Now()
+		s.stats.Opened++
+	}
+	return err
+}
+
+// allow reports whether an entry may be written and whether it is the probe of a half-open
+// circuit, moving an open circuit to half-open once the cooldown has passed
+func (s *CircuitBreakerSink) allow() (allowed, probe bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch s.stats.State {
+	case BreakerOpen:
+		if time.Since(s.openedAt) < s.policy.Cooldown {
+			break
+		}
+		s.stats.State = BreakerHalfOpen
+		fallthrough
+	case BreakerHalfOpen:
+		if !s.probing {
+			s.probing = true
+			return true, true
+		}
+	default:
+		return true, false
+	}
+	s.stats.Rejected++
+	return false, false
+}
+
+// Stats returns the state of the circuit and counts of its history
+func (s *CircuitBreakerSink) Stats() BreakerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// Close closes the wrapped sink if it is an io.Closer
+func (s *CircuitBreakerSink) Close() error {
+	if c, ok := s.sink.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerSink(t *testing.T) {
+	remote := &downSink{down: true}
+	s := NewCircuitBreakerSink(remote, BreakerPolicy{Failures: 2, Cooldown: 20 * time.Millisecond})
+	for i := 0; i < 2; i++ {
+		if err := s.WriteEntry(Entry{Message: "failing"}); err == nil || err == ErrCircuitOpen {
+			t.Fatalf("expected the sink's error, got %v", err)
+		}
+	}
+	if err := s.WriteEntry(Entry{Message: "rejected"}); err != ErrCircuitOpen {
+		t.Errorf("expected %v after 2 failures, got %v", ErrCircuitOpen, err)
+	}
+	time.Sleep(25 * time.Millisecond)
+	if err := s.WriteEntry(Entry{Message: "failed probe"}); err == nil || err == ErrCircuitOpen {
+		t.Errorf("expected a probe after the cooldown, got %v", err)
+	}
+	if err := s.WriteEntry(Entry{Message: "rejected"}); err != ErrCircuitOpen {
+		t.Errorf("expected a failed probe to open the circuit again, got %v", err)
+	}
+	remote.setDown(false)
+	time.Sleep(25 * time.Millisecond)
+	for _, m := range []string{"probe", "closed"} {
+		if err := s.WriteEntry(Entry{Message: m}); err != nil {
+			t.Errorf("expected the circuit to close after a successful probe, got %v", err)
+		}
+	}
+	stats := s.Stats()
+	if stats.State != BreakerClosed || stats.Opened != 2 || stats.Rejected != 2 || stats.Failures != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if remote.messages() != "probe,closed" {
+		t.Errorf("unexpected entries %q", remote.messages())
+	}
+}
+
+// gatedSink fails every write once the gate is opened
+type gatedSink struct {
+	gate chan struct{}
+}
+
+func (s *gatedSink) WriteEntry(e Entry) error {
+	<-s.gate
+	return errors.New("down")
+}
+
+func TestCircuitBreakerConcurrentFailures(t *testing.T) {
+	remote := &gatedSink{gate: make(chan struct{})}
+	s := NewCircuitBreakerSink(remote, BreakerPolicy{Failures: 1, Cooldown: time.Hour})
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.WriteEntry(Entry{Message: "in flight"})
+		}()
+	}
+	time.Sleep(10 * time.Millisecond) // let the writes reach the sink while the circuit is closed
+	close(remote.gate)
+	wg.Wait()
+	if stats := s.Stats(); stats.Opened != 1 || stats.Failures+stats.Rejected != 5 {
+		t.Errorf("expected the circuit to open once, got %+v", stats)
+	}
+}
@@ -0,0 +1,90 @@
+package logging
+
+import "time"
+
+// diskBudgetInterval is how often the disk budget is checked as entries are written
+const diskBudgetInterval = 10 * time.Second
+
+type diskBudget struct {
+	max     int64
+	checked time.Time
+	backups int64 // the size of the backups when the budget was last enforced
+}
+
+// due reports whether the budget should be checked now. It is called with the log's write lock held
+func (b *diskBudget) due(now time.Time) bool {
+	if b == nil || now.Sub(b.checked) < diskBudgetInterval {
+		return false
+	}
+	b.checked = now
+	return true
+}
+
+// enforceDiskBudget removes the oldest backups until the log file and its backups fit the
+// budget. It is called with the write lock held
+func (l *Log) enforceDiskBudget() error {
+	return l.reserveDiskBudget(0)
+}
+
+// reserveDiskBudget removes the oldest backups until the log file, its backups and n more
+// bytes fit the budget. It is called with the write lock held
+func (l *Log) reserveDiskBudget(n int64) error {
+	backups, err := l.backups()
+	if err != nil {
+		return err
+	}
+	var total int64
+	if info, err := l.getFS().Stat(l.path); err == nil {
+		total = info.Size()
+	}
+	sizes := make([]int64, len(backups))
+	for i, backup := range backups {
+		if info, err := l.getFS().Stat(backup); err == nil {
+			sizes[i] = info.Size()
+			total += sizes[i]
+		}
+	}
+	for i := 0; i < len(backups) && total+n > l.budget.max; i++ {
+		if err = l.getFS().Remove(backups[i]); err != nil {
+			return err
+		}
+		total -= sizes[i]
+	}
+	l.budget.backups = total
+	if info, err := l.getFS().Stat(l.path); err == nil {
+		l.budget.backups -= info.Size()
+	}
+	return nil
+}
+
+// rotateOverBudget rotates the log file before a write of n bytes that would take the log file
+// and its backups over the budget, then removes the oldest backups to make room, so that the
+// log file does not grow past the budget. The rotated file is compressed and archived, if
+// configured, once the write lock is released. It is called with the write lock held
+func (l *Log) rotateOverBudget(n int) {
+	if l.budget == nil || l.path == "" {
+		return
+	}
+	if l.budget.due(l.now()) {
+		if err := l.enforceDiskBudget(); err != nil {
+			l.handleError(err)
+		}
+	}
+	if _, err := l.outputFile(); err != nil {
+		return // left to the write to report
+	}
+	if l.writer.size == 0 || l.writer.size+int64(n)+l.budget.backups <= l.budget.max {
+		return
+	}
+	rotated, err := l.rotate()
+	if err == nil {
+		err = l.reserveDiskBudget(int64(n))
+	}
+	if err != nil {
+		l.handleError(err)
+		return
+	}
+	if rotated != "" && (l.compressor != nil || l.archive != nil) {
+		go l.finishRotation(rotated)
+	}
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiskBudget(t *testing.T) {
+	fs := NewMemFS()
+	for _, name := range []string{"/logs/app.log.1", "/logs/app.log.2", "/logs/app.log.3"} {
+		f, _ := fs.OpenFile(name, os.O_CREATE|os.O_WRONLY, 0644)
+		f.Write([]byte(strings.Repeat("x", 1000)))
+		time.Sleep(time.Millisecond)
+	}
+	bl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithDiskBudget(2500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := fs.Names()
+	if len(names) != 3 || names[0] != "/logs/app.log" || names[1] != "/logs/app.log.2" {
+		t.Errorf("expected the oldest backup to be removed on the first write, got %v", names)
+	}
+	// the write would go over the budget, so the log file is rotated and a backup removed
+	bl.Info(strings.Repeat("y", 1000))
+	if names = fs.Names(); len(names) != 3 || names[2] != "/logs/app.log.3" {
+		t.Errorf("expected the oldest backup to be removed after rotation, got %v", names)
+	}
+}
+
+func TestDiskBudgetKeepsQuarantine(t *testing.T) {
+	fs := NewMemFS()
+	f, _ := fs.OpenFile("/logs/app.log.partial", os.O_CREATE|os.O_WRONLY, 0644)
+	f.Write([]byte(strings.Repeat("x", 1000)))
+	if _, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithDiskBudget(10)); err != nil {
+		t.Fatal(err)
+	}
+	if names := fs.Names(); len(names) != 2 {
+		t.Errorf("expected the log and its quarantine file to be kept, got %v", names)
+	}
+}
+
+func TestDiskBudgetRotates(t *testing.T) {
+	fs := NewMemFS()
+	bl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithDiskBudget(3000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		bl.Info(strings.Repeat("y", 500))
+	}
+	var total int64
+	for _, name := range fs.Names() {
+		info, _ := fs.Stat(name)
+		total += info.Size()
+	}
+	// 10 entries of over 500 bytes only fit if the log file was rotated
+	if names := fs.Names(); total > 3000 {
+		t.Errorf("expected the log file to be rotated to stay within the budget, got %v of %d bytes", names, total)
+	}
+}
+
+func TestDiskBudgetRotationName(t *testing.T) {
+	fs := NewMemFS()
+	f, _ := fs.OpenFile("/logs/other.log", os.O_CREATE|os.O_WRONLY, 0644)
+	f.Write([]byte(strings.Repeat("x", 1000)))
+	_, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithRotationName("%Y%m%d.log", false), WithDiskBudget(10))
+	if err != errRotationPrefix {
+		t.Errorf("expected %v, got %v", errRotationPrefix, err)
+	}
+	if _, err = fs.Stat("/logs/other.log"); err != nil {
+		t.Errorf("expected an unrelated file to be kept, got %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package logging
+
+import "runtime/debug"
+
+// buildAttrs returns the module version and VCS revision of the binary as attributes
+func buildAttrs(info *debug.BuildInfo) []Attr {
+	attrs := make([]Attr, 0, 3)
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		attrs = append(attrs, String("build_version", v))
+	}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			attrs = append(attrs, String("build_revision", s.Value))
+		case "vcs.modified":
+			if s.Value == "true" {
+				attrs = append(attrs, Bool("build_modified", true))
+			}
+		}
+	}
+	return attrs
+}
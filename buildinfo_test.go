@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestBuildAttrs(t *testing.T) {
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/billing", Version: "v1.4.2"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "3f2a9c1"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+	e := Entry{Attrs: buildAttrs(info)}
+	expected := map[string]interface{}{
+		"build_version":  "v1.4.2",
+		"build_revision": "3f2a9c1",
+		"build_modified": true,
+	}
+	for key, value := range expected {
+		if v, _ := e.Field(key); v != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, v)
+		}
+	}
+	if attrs := buildAttrs(&debug.BuildInfo{Main: debug.Module{Version: "(devel)"}}); len(attrs) != 0 {
+		t.Errorf("expected no attributes for a development build, got %v", attrs)
+	}
+}
@@ -0,0 +1,29 @@
+package logging
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// packagePrefix prefixes the names of this package's functions, which are skipped when
+// looking for the caller of the log
+var packagePrefix = reflect.TypeOf(Log{}).PkgPath() + "."
+
+// caller returns the file and line, as "file.go:42", of the first function outside this
+// package on the stack, or "" if there is none
+func caller() string {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) || strings.HasSuffix(frame.File, "_test.go") {
+			return filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package logging
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+func TestCaller(t *testing.T) {
+	c := NewCapture(WithCaller())
+	_, _, line, _ := runtime.Caller(0)
+	c.Infof("from line %d", line+1)
+	c.Chain().Info("chained")
+	entries := c.Entries()
+	for _, e := range entries {
+		if v, _ := e.Field("caller"); v != fmt.Sprintf("caller_test.go:%d", line+1) && e.Message != "chained" {
+			t.Errorf("expected '%s' to have caller caller_test.go:%d, got %v", e.Message, line+1, v)
+		}
+	}
+	if v, _ := entries[1].Field("caller"); v != fmt.Sprintf("caller_test.go:%d", line+2) {
+		t.Errorf("expected the chained entry to have caller caller_test.go:%d, got %v", line+2, v)
+	}
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+)
+
+// Capture is a log that records entries in memory instead of writing to a file, for use in tests
+type Capture struct {
+	*Log
+	sink *captureSink
+}
+
+type captureSink struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (s *captureSink) WriteEntry(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+// NewCapture returns a capture log accepting every level and reporting nothing. It panics
+// if the options are invalid, as a test cannot go on without its log
+func NewCapture(opts ...Option) *Capture {
+	c := &Capture{sink: &captureSink{}}
+	opts = append([]Option{WithSink(c.sink)}, opts...)
+	var err error
+	if c.Log, err = NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, opts...); err != nil {
+		panic("logging: NewCapture: " + err.Error())
+	}
+	c.Reset()
+	return c
+}
+
+// Entries returns the captured entries in the order they were written
+func (c *Capture) Entries() []Entry {
+	c.sink.mu.Lock()
+	defer c.sink.mu.Unlock()
+	return append([]Entry{}, c.sink.entries...)
+}
+
+// HasEntry reports whether an entry at level (case insensitive) containing substring was captured
+func (c *Capture) HasEntry(level, substring string) bool {
+	for _, e := range c.Entries() {
+		if strings.EqualFold(e.Level, level) && strings.Contains(e.Message, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset discards all captured entries
+func (c *Capture) Reset() {
+	c.sink.mu.Lock()
+	defer c.sink.mu.Unlock()
+	c.sink.entries = nil
+}
@@ -0,0 +1,34 @@
+package logging
+
+import "testing"
+
+func TestCapture(t *testing.T) {
+	c := NewCapture()
+	if len(c.Entries()) != 0 {
+		t.Fatalf("expected a new capture to be empty, got %d entries", len(c.Entries()))
+	}
+	c.Errorf("payment %d failed", 42)
+	c.WithFields(Fields{"retry": true}).Warning("retrying payment")
+	if !c.HasEntry("error", "payment 42 failed") {
+		t.Error("expected the error entry to have been captured")
+	}
+	if c.HasEntry(INFO, "payment") {
+		t.Error("expected no info entry to have been captured")
+	}
+	entries := c.Entries()
+	if len(entries) != 2 || entries[1].Fields["retry"] != true {
+		t.Errorf("expected two entries with fields kept, got %v", entries)
+	}
+	if c.Path() != "" {
+		t.Errorf("expected capture not to have a file, got %s", c.Path())
+	}
+}
+
+func TestCaptureInvalidOption(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected an invalid option to panic")
+		}
+	}()
+	NewCapture(WithMaxMessageLength(-1))
+}
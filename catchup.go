@@ -0,0 +1,233 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// catchUpMarkInterval is how often a CatchUpSink delivering entries as they are written
+	// records the time reached in its mark file
+	catchUpMarkInterval = time.Second
+	// defaultCatchUpInterval is how often WithCatchUp retries a catch-up that failed
+	defaultCatchUpInterval = 30 * time.Second
+	// catchUpSettle is how long a catch-up leaves for entries written to the sinks to reach
+	// the log file; newer entries are left to the next catch-up if any of them failed
+	catchUpSettle = time.Second
+)
+
+// CatchUpSink wraps a sink, typically one shipping entries to a remote collector, recording
+// in a mark file the time of the last entry delivered. While it is behind, because the mark
+// was left by an earlier run or a write failed, the mark only advances as CatchUp sends the
+// entries after it from the log's files, so entries written while shipping was disabled or
+// broken are still delivered. Entries are still written as they come while it catches up,
+// so some may be delivered twice and out of order. So that entries are not missed when the
+// file records times less precisely, a catch-up starts at the second of the mark
+type CatchUpSink struct {
+	sink   Sink
+	mark   string
+	mu     sync.Mutex
+	last   time.Time // of the last entry delivered in order
+	saved  time.Time // when the mark was last written
+	behind bool
+	missed time.Time // of the last entry whose write failed
+}
+
+// NewCatchUpSink wraps sink, reading the time to catch up from in mark. Without a mark file,
+// as on the first run, shipping starts with the entries written from now on
+func NewCatchUpSink(sink Sink, mark string) (*CatchUpSink, error) {
+	s := &CatchUpSink{sink: sink, mark: mark}
+	b, err := os.ReadFile(mark)
+	if os.IsNotExist(err) {
+		s.last = time.Now()
+		return s, s.saveMark(s.last)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.last, err = time.Parse(time.RFC3339Nano, strings.TrimSpace(string(b))); err != nil {
+		return nil, err
+	}
+	s.behind = true
+	return s, nil
+}
+
+// WriteEntry writes the entry to the wrapped sink. Once it is caught up, the mark follows
+// the entries delivered; a failure leaves it behind until the next CatchUp
+func (s *CatchUpSink) WriteEntry(e Entry) error {
+	err := s.sink.WriteEntry(e)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.behind = true
+		if e.Time.After(s.missed) {
+			s.missed = e.Time
+		}
+		return err
+	}
+	if !s.behind && e.Time.After(s.last) {
+		s.advance(e.Time)
+	}
+	return nil
+}
+
+// Behind reports whether entries may have been missed since the mark
+func (s *CatchUpSink) Behind() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.behind
+}
+
+// Check checks the wrapped sink; see Checker
+func (s *CatchUpSink) Check() error {
+	return checkSink(s.sink)
+}
+
+// advance moves the mark to t, writing it to the mark file at most once per
+// catchUpMarkInterval. It is called with the lock held
+func (s *CatchUpSink) advance(t time.Time) error {
+	s.last = t
+	if time.Since(s.saved) < catchUpMarkInterval {
+		return nil
+	}
+	return s.saveMark(t)
+}
+
+func (s *CatchUpSink) saveMark(t time.Time) error {
+	tmp := s.mark + ".tmp"
+	if err := os.WriteFile(tmp, []byte(t.Format(time.RFC3339Nano)+"\n"), defaultFileMode); err != nil {
+		return err
+	}
+	s.saved = time.Now()
+	return os.Rename(tmp, s.mark)
+}
+
+// WithCatchUp adds a CatchUpSink, sending the entries it missed from the log's files once the
+// log is created, and again every interval (default 30 seconds) while it is behind
+func WithCatchUp(s *CatchUpSink, interval time.Duration) Option {
+	return func(l *Log) {
+		if interval <= 0 {
+			interval = defaultCatchUpInterval
+		}
+		l.sinks = append(l.sinks, s)
+		l.catchUps = append(l.catchUps, catchUp{sink: s, interval: interval})
+	}
+}
+
+type catchUp struct {
+	sink     *CatchUpSink
+	interval time.Duration
+}
+
+func (l *Log) watchCatchUp(c catchUp) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		if c.sink.Behind() {
+			if _, err := l.CatchUp(c.sink); err != nil {
+				l.handleError(err)
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// CatchUp sends the entries written after the sink's mark, up to a second ago, from the log's
+// rotated files and then the log file, oldest first, and returns how many were sent. The
+// sink is caught up unless a write of a newer entry failed meanwhile. It stops at the first
+// error of the sink, leaving the mark at the last entry sent
+func (l *Log) CatchUp(s *CatchUpSink) (sent int, err error) {
+	until := time.Now().Add(-catchUpSettle)
+	s.mu.Lock()
+	from := s.last
+	s.mu.Unlock()
+	if sent, err = l.catchUpFrom(s, from, until); err != nil {
+		return sent, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.missed.After(until) {
+		s.behind = false
+	}
+	if until.After(s.last) {
+		s.last = until
+	}
+	return sent, s.saveMark(s.last)
+}
+
+// catchUpFrom sends the entries in the log's files written from the second of from up to until
+func (l *Log) catchUpFrom(s *CatchUpSink, from, until time.Time) (sent int, err error) {
+	from = from.Truncate(time.Second)
+	if l.path == "" {
+		return 0, nil
+	}
+	backups, err := l.backups()
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range append(backups, l.path) {
+		if name != l.path && l.modTime(name).Before(from) {
+			continue // holds nothing newer than the mark
+		}
+		n, err := l.catchUpFile(s, name, from, until)
+		sent += n
+		if err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+func (l *Log) catchUpFile(s *CatchUpSink, name string, from, until time.Time) (sent int, err error) {
+	file, err := l.getFS().OpenFile(name, os.O_RDONLY, 0)
+	if os.IsNotExist(err) {
+		return 0, nil // rotated away meanwhile
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	r, _, err := decompress(name, file)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	scanner := newRecordScanner(r)
+	for {
+		record, err := scanner.next()
+		if err == io.EOF {
+			return sent, nil
+		}
+		if err != nil {
+			return sent, err
+		}
+		e, err := ParseEntry(record)
+		if err != nil {
+			l.handleError(fmt.Errorf("catch up: unreadable record in %s not sent: %w", name, err))
+			continue
+		}
+		if e.Time.Before(from) || e.Time.After(until) {
+			continue
+		}
+		if err = s.sink.WriteEntry(e); err != nil {
+			return sent, err
+		}
+		sent++
+		s.mu.Lock()
+		if e.Time.After(s.last) {
+			err = s.advance(e.Time)
+		}
+		s.mu.Unlock()
+		if err != nil {
+			return sent, err
+		}
+	}
+}
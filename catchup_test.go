@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCatchUp(t *testing.T) {
+	fs := NewMemFS()
+	mark := filepath.Join(t.TempDir(), "mark")
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	os.WriteFile(mark, []byte(start.Format(time.RFC3339Nano)), 0644)
+	now := start.Add(-time.Minute)
+	newLog := func(opts ...Option) *Log {
+		l, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, append(opts, WithFS(fs), WithNow(func() time.Time { return now }))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return l
+	}
+	cl := newLog()
+	cl.Info("shipped")
+	now = start.Add(time.Minute)
+	cl.Info("written while shipping was disabled")
+
+	remote := &downSink{}
+	sink, err := NewCatchUpSink(remote, mark)
+	if err != nil || !sink.Behind() {
+		t.Fatalf("expected the sink to be behind its mark, got %v", err)
+	}
+	cl = newLog(WithSink(sink))
+	now = start.Add(2 * time.Minute)
+	remote.setDown(true)
+	cl.Info("written while shipping was broken")
+	remote.setDown(false)
+	now = start.Add(3 * time.Minute)
+	cl.Info("written live")
+	if sent, err := cl.CatchUp(sink); err != nil || sent != 4 {
+		t.Fatalf("expected 4 entries to be caught up, got %d and %v", sent, err)
+	}
+	if sink.Behind() {
+		t.Error("expected the sink to have caught up")
+	}
+	got := remote.messages()
+	for _, message := range []string{"written while shipping was disabled", "written while shipping was broken", "written live"} {
+		if !strings.Contains(got, message) {
+			t.Errorf("expected %q to be delivered, got %s", message, got)
+		}
+	}
+	if strings.Contains(got, "shipped") {
+		t.Errorf("expected the entries before the mark to be skipped, got %s", got)
+	}
+
+	// the mark is kept for the next run
+	if sink, err = NewCatchUpSink(remote, mark); err != nil || !sink.last.After(now) {
+		t.Errorf("expected the mark to have advanced past %s, got %v", now, err)
+	}
+}
@@ -0,0 +1,170 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cefSeverities map syslog severities, from EMERGENCY to DEBUG, to CEF severities from 0 to 10
+var cefSeverities = [...]int{10, 9, 8, 7, 5, 3, 2, 1}
+
+// cefUnknownSeverity is the CEF severity of custom levels
+const cefUnknownSeverity = 5
+
+var (
+	cefHeaderEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+)
+
+// CEFEncoder formats entries in ArcSight's Common Event Format for SIEM ingestion:
+//
+//	CEF:0|Vendor|Product|Version|LEVEL|message|severity|rt=<ms> key=value
+//
+// The signature ID is the level and the name the first line of the message; the severity
+// is mapped from the level's syslog severity onto CEF's 0 to 10. Product defaults to the env.
+// The extensions hold the receipt time in milliseconds since the epoch, the whole message as
+// msg if it has several lines, then the fields and attributes, with any characters other than
+// letters, digits and underscores in their keys replaced by underscores
+type CEFEncoder struct {
+	Vendor, Product, Version string
+}
+
+func (c CEFEncoder) AppendEntry(dst []byte, e Entry) []byte {
+	product := c.Product
+	if product == "" {
+		product = e.Env
+	}
+	name, _, multiline := strings.Cut(e.Message, "\n")
+	dst = append(dst, "CEF:0|"...)
+	for _, header := range []string{c.Vendor, product, c.Version, e.Level, name} {
+		dst = append(dst, cefHeaderEscaper.Replace(header)...)
+		dst = append(dst, '|')
+	}
+	dst = strconv.AppendInt(dst, int64(cefSeverity(e.Level)), 10)
+	dst = append(dst, "|rt="...)
+	dst = strconv.AppendInt(dst, e.Time.UnixMilli(), 10)
+	if multiline {
+		dst = appendCEFExtension(dst, "msg", e.Message)
+	}
+	for _, k := range e.Fields.keys() {
+		dst = appendCEFExtension(dst, k, e.Fields[k])
+	}
+	for _, a := range e.Attrs {
+		dst = appendCEFExtension(dst, a.Key, a.Value())
+	}
+	if e.Seq > 0 {
+		dst = appendCEFExtension(dst, "seq", e.Seq)
+	}
+	return dst
+}
+
+func cefSeverity(level string) int {
+	if severity, ok := Severity(level); ok {
+		return cefSeverities[severity]
+	}
+	return cefUnknownSeverity
+}
+
+func appendCEFExtension(dst []byte, key string, v interface{}) []byte {
+	dst = append(dst, ' ')
+	for _, r := range key {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			dst = append(dst, byte(r))
+		} else {
+			dst = append(dst, '_')
+		}
+	}
+	dst = append(dst, '=')
+	var value string
+	switch v := v.(type) {
+	case string:
+		value = v
+	case error:
+		value = v.Error()
+	default:
+		value = fmt.Sprint(v)
+	}
+	return append(dst, cefExtensionEscaper.Replace(value)...)
+}
+
+var (
+	// cefForm starts a record written by CEFEncoder
+	cefForm               = regexp.MustCompile(`^CEF:\d+\|`)
+	cefHeaderUnescaper    = strings.NewReplacer(`\\`, `\`, `\|`, `|`)
+	cefExtensionUnescaper = strings.NewReplacer(`\\`, `\`, `\=`, `=`, `\r`, "\r", `\n`, "\n")
+)
+
+// parseCEFEntry parses a record written by CEFEncoder. The env is read from the product, so
+// it is the entry's env unless Product was set, the time from rt, the message from msg or
+// else the name, and the other extensions into Fields as strings
+func parseCEFEntry(record string) (Entry, error) {
+	// CEF:0, vendor, product, version, signature ID, name and severity
+	header := make([]string, 0, 7)
+	for len(header) < 7 {
+		i := unescapedIndex(record, '|')
+		if i < 0 {
+			return Entry{}, ErrMalformedRecord
+		}
+		header = append(header, cefHeaderUnescaper.Replace(record[:i]))
+		record = record[i+1:]
+	}
+	e := Entry{Env: header[2], Level: header[4], Message: header[5], precision: 3}
+	var keys, values []string
+	for record != "" {
+		eq := unescapedIndex(record, '=')
+		if eq <= 0 {
+			return Entry{}, ErrMalformedRecord
+		}
+		keys = append(keys, record[:eq])
+		record = record[eq+1:]
+		// the value runs up to the space before the next key
+		end := len(record)
+		if next := unescapedIndex(record, '='); next >= 0 {
+			end = strings.LastIndexByte(record[:next], ' ')
+			if end < 0 {
+				return Entry{}, ErrMalformedRecord
+			}
+		}
+		values = append(values, cefExtensionUnescaper.Replace(record[:end]))
+		record = strings.TrimPrefix(record[end:], " ")
+	}
+	for i, key := range keys {
+		switch key {
+		case "rt":
+			ms, err := strconv.ParseInt(values[i], 10, 64)
+			if err != nil {
+				return Entry{}, ErrMalformedRecord
+			}
+			e.Time = time.UnixMilli(ms).UTC()
+		case "msg":
+			e.Message = values[i]
+		case "seq":
+			e.Seq, _ = strconv.ParseUint(values[i], 10, 64)
+		default:
+			if e.Fields == nil {
+				e.Fields = Fields{}
+			}
+			e.Fields[key] = values[i]
+		}
+	}
+	if e.Time.IsZero() {
+		return Entry{}, ErrMalformedRecord
+	}
+	return e, nil
+}
+
+// unescapedIndex returns the index of the first c in s not escaped by a backslash, or -1
+func unescapedIndex(s string, c byte) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case c:
+			return i
+		}
+	}
+	return -1
+}
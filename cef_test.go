@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCEFEncoder(t *testing.T) {
+	e := Entry{
+		Time:    time.Date(2023, 3, 1, 10, 4, 5, 0, time.UTC),
+		Env:     "payments",
+		Level:   ERROR,
+		Message: "charge failed | retrying\nattempt 2",
+		Fields:  Fields{"amount": "10=USD"},
+		Attrs:   []Attr{Err(errors.New(`bad\card`)), String("card-type", "visa")},
+	}
+	line := string(CEFEncoder{Vendor: "Acme", Version: "1.2"}.AppendEntry(nil, e))
+	expected := `CEF:0|Acme|payments|1.2|ERROR|charge failed \| retrying|7|rt=1677665045000` +
+		` msg=charge failed | retrying\nattempt 2 amount=10\=USD error=bad\\card card_type=visa`
+	if line != expected {
+		t.Errorf("expected '%s', got '%s'", expected, line)
+	}
+	e.Level, e.Message, e.Fields, e.Attrs = "AUDIT", "login", nil, nil
+	if line = string(CEFEncoder{Vendor: "Acme", Product: "api", Version: "1.2"}.AppendEntry(nil, e)); line != "CEF:0|Acme|api|1.2|AUDIT|login|5|rt=1677665045000" {
+		t.Errorf("unexpected record for a custom level '%s'", line)
+	}
+}
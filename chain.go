@@ -0,0 +1,67 @@
+package logging
+
+import "fmt"
+
+// Chain is a fire-and-forget view of a log whose methods return the chain instead of the
+// written message and error, so calls can be chained. Write errors go to the error handler
+type Chain struct {
+	l *Log
+}
+
+// Chain returns a chainable view of the log
+func (l *Log) Chain() *Chain {
+	return &Chain{l: l}
+}
+
+// Log returns the underlying log
+func (c *Chain) Log() *Log {
+	return c.l
+}
+
+func (c *Chain) write(message, level string) *Chain {
+	// sink and file failures are passed to the error handler by the write itself
+	if _, err := c.l.Write(message, level); err == ErrClosed {
+		c.l.handleError(err)
+	}
+	return c
+}
+
+func (c *Chain) Error(message string) *Chain {
+	return c.write(message, ERROR)
+}
+
+func (c *Chain) Success(message string) *Chain {
+	return c.write(message, SUCCESS)
+}
+
+func (c *Chain) Warning(message string) *Chain {
+	return c.write(message, WARNING)
+}
+
+func (c *Chain) Debug(message string) *Chain {
+	return c.write(message, DEBUG)
+}
+
+func (c *Chain) Info(message string) *Chain {
+	return c.write(message, INFO)
+}
+
+func (c *Chain) Errorf(message string, vars ...interface{}) *Chain {
+	return c.Error(fmt.Sprintf(message, vars...))
+}
+
+func (c *Chain) Successf(message string, vars ...interface{}) *Chain {
+	return c.Success(fmt.Sprintf(message, vars...))
+}
+
+func (c *Chain) Warningf(message string, vars ...interface{}) *Chain {
+	return c.Warning(fmt.Sprintf(message, vars...))
+}
+
+func (c *Chain) Debugf(message string, vars ...interface{}) *Chain {
+	return c.Debug(fmt.Sprintf(message, vars...))
+}
+
+func (c *Chain) Infof(message string, vars ...interface{}) *Chain {
+	return c.Info(fmt.Sprintf(message, vars...))
+}
@@ -0,0 +1,24 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	c := NewCapture()
+	c.Chain().Info("starting").Warningf("retry %d", 2).Error("gave up")
+	entries := c.Entries()
+	if len(entries) != 3 || entries[1].Message != "retry 2" || entries[2].Level != ERROR {
+		t.Errorf("expected three chained entries, got %v", entries)
+	}
+	handled := make([]error, 0)
+	c.SetErrorHandler(func(err error) {
+		handled = append(handled, err)
+	})
+	c.Close()
+	c.Chain().Info("after close")
+	if len(handled) != 1 || !errors.Is(handled[0], ErrClosed) {
+		t.Errorf("expected the closed error to be handled, got %v", handled)
+	}
+}
@@ -0,0 +1,235 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	// circularMagic starts the header of a circular log file
+	circularMagic = "LOGRING1"
+	// circularHeaderSize is the size of the header: the magic, then the size of the data area,
+	// the offsets of the next write and of the oldest record, and whether the data has wrapped
+	circularHeaderSize = 40
+)
+
+var (
+	errCircularFormat = errors.New("not a circular log file")
+	errCircularRecord = errors.New("record is larger than the circular log file")
+)
+
+// CircularFileSink writes entries to a file of fixed size used as a ring buffer: once it is
+// full the oldest records are overwritten, so it never grows, for devices too small for
+// rotated files. Each entry is one line in the default text format, with line breaks
+// escaped. Read the records back in order with ReadCircularFile
+type CircularFileSink struct {
+	mu      sync.Mutex
+	file    *os.File
+	size    int64 // of the data area
+	head    int64 // where the next record is written
+	tail    int64 // where the oldest record starts
+	wrapped bool
+}
+
+// NewCircularFileSink opens the circular log file at path, creating it with a data area of
+// size bytes if it does not exist. An existing file must have been created with the same size
+func NewCircularFileSink(path string, size int64) (*CircularFileSink, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid circular log size %d", size)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, defaultFileMode)
+	if err != nil {
+		return nil, err
+	}
+	s := &CircularFileSink{file: file, size: size}
+	info, err := file.Stat()
+	if err == nil && info.Size() == 0 {
+		err = s.create()
+	} else if err == nil {
+		err = s.load()
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// create writes the header and reserves the data area of a new file
+func (s *CircularFileSink) create() error {
+	header := make([]byte, circularHeaderSize)
+	copy(header, circularMagic)
+	binary.BigEndian.PutUint64(header[8:], uint64(s.size))
+	if _, err := s.file.WriteAt(header, 0); err != nil {
+		return err
+	}
+	return s.file.Truncate(circularHeaderSize + s.size)
+}
+
+// load reads the header of an existing file
+func (s *CircularFileSink) load() error {
+	header, err := readCircularHeader(s.file)
+	if err != nil {
+		return err
+	}
+	if header.size != s.size {
+		return fmt.Errorf("circular log has size %d, not %d", header.size, s.size)
+	}
+	s.head, s.tail, s.wrapped = header.head, header.tail, header.wrapped
+	return nil
+}
+
+func (s *CircularFileSink) WriteEntry(e Entry) error {
+	record := append([]byte(escapeLineBreaks(e.String())), '\n')
+	n := int64(len(record))
+	if n > s.size {
+		return errCircularRecord
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.head+n > s.size {
+		// pad the rest of the data area and continue from the start
+		if s.wrapped && s.tail >= s.head {
+			s.tail = 0
+		}
+		if _, err := s.file.WriteAt(make([]byte, s.size-s.head), circularHeaderSize+s.head); err != nil {
+			return err
+		}
+		s.head, s.wrapped = 0, true
+	}
+	if s.wrapped && s.tail >= s.head && s.tail < s.head+n {
+		tail, err := s.nextRecord(s.head + n)
+		if err != nil {
+			return err
+		}
+		s.tail = tail
+	}
+	if _, err := s.file.WriteAt(record, circularHeaderSize+s.head); err != nil {
+		return err
+	}
+	s.head += n
+	return s.writeHeader()
+}
+
+// nextRecord returns the start of the first old record starting at or after offset, or 0 if
+// there is none before the end of the data area. It is called before the records up to
+// offset are overwritten, with the lock held
+func (s *CircularFileSink) nextRecord(offset int64) (int64, error) {
+	pos := s.tail
+	buf := make([]byte, 4096)
+	for pos < offset {
+		if pos >= s.size {
+			return 0, nil
+		}
+		n, err := s.file.ReadAt(buf[:min64(int64(len(buf)), s.size-pos)], circularHeaderSize+pos)
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if n == 0 || buf[0] == 0 {
+			return 0, nil // padding runs to the end
+		}
+		i := bytes.IndexByte(buf[:n], '\n')
+		if i < 0 {
+			pos += int64(n)
+			continue
+		}
+		pos += int64(i) + 1
+	}
+	if pos >= s.size {
+		return 0, nil
+	}
+	return pos, nil
+}
+
+func (s *CircularFileSink) writeHeader() error {
+	var b [24]byte
+	binary.BigEndian.PutUint64(b[0:], uint64(s.head))
+	binary.BigEndian.PutUint64(b[8:], uint64(s.tail))
+	if s.wrapped {
+		b[23] = 1
+	}
+	_, err := s.file.WriteAt(b[:], 16)
+	return err
+}
+
+// Close syncs and closes the file
+func (s *CircularFileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.file.Sync()
+	if cErr := s.file.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
+
+type circularHeader struct {
+	size, head, tail int64
+	wrapped          bool
+}
+
+func readCircularHeader(r io.ReaderAt) (circularHeader, error) {
+	b := make([]byte, circularHeaderSize)
+	if _, err := r.ReadAt(b, 0); err != nil {
+		if err == io.EOF {
+			return circularHeader{}, errCircularFormat
+		}
+		return circularHeader{}, err
+	}
+	if string(b[:8]) != circularMagic {
+		return circularHeader{}, errCircularFormat
+	}
+	h := circularHeader{
+		size:    int64(binary.BigEndian.Uint64(b[8:])),
+		head:    int64(binary.BigEndian.Uint64(b[16:])),
+		tail:    int64(binary.BigEndian.Uint64(b[24:])),
+		wrapped: b[39] == 1,
+	}
+	if h.size <= 0 || h.head > h.size || h.tail > h.size {
+		return circularHeader{}, errCircularFormat
+	}
+	return h, nil
+}
+
+// ReadCircularFile returns the records of a circular log file written by CircularFileSink,
+// oldest first
+func ReadCircularFile(path string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	h, err := readCircularHeader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	data := b[circularHeaderSize:]
+	if int64(len(data)) != h.size {
+		return nil, errCircularFormat
+	}
+	var ordered []byte
+	switch {
+	case h.tail < h.head:
+		ordered = data[h.tail:h.head]
+	case h.wrapped:
+		ordered = append(bytes.TrimRight(data[h.tail:], "\x00"), data[:h.head]...)
+	}
+	records := make([]string, 0)
+	for _, line := range bytes.Split(bytes.TrimSuffix(ordered, []byte("\n")), []byte("\n")) {
+		if len(line) > 0 {
+			records = append(records, string(line))
+		}
+	}
+	return records, nil
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCircularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ring.log")
+	sink, err := NewCircularFileSink(path, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCapture(WithSink(sink), WithNow(func() time.Time { return time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC) }))
+	for i := 0; i < 20; i++ {
+		c.Infof("entry %02d", i)
+	}
+	c.Info("multi\nline")
+	c.Close()
+	if info, _ := os.Stat(path); info.Size() != circularHeaderSize+300 {
+		t.Errorf("expected the file to keep its size, got %d bytes", info.Size())
+	}
+	records, err := ReadCircularFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) < 3 || records[len(records)-1] != `[2024-01-02T00:00:00Z] [TEST.INFO] multi\nline` {
+		t.Fatalf("expected the newest records to be kept, got %q", records)
+	}
+	first := strings.TrimPrefix(records[0], "[2024-01-02T00:00:00Z] [TEST.INFO] entry ")
+	for i, record := range records[:len(records)-1] {
+		var n int
+		fmt.Sscanf(first, "%d", &n)
+		if expected := fmt.Sprintf("[2024-01-02T00:00:00Z] [TEST.INFO] entry %02d", n+i); record != expected {
+			t.Errorf("expected record %d to be '%s', got '%s'", i, expected, record)
+		}
+	}
+	if sink, err = NewCircularFileSink(path, 300); err != nil {
+		t.Fatalf("expected to reopen the file, got %v", err)
+	}
+	sink.WriteEntry(Entry{Time: time.Date(2024, 1, 2, 0, 0, 1, 0, time.UTC), Env: "TEST", Level: INFO, Message: "reopened"})
+	sink.Close()
+	reopened, _ := ReadCircularFile(path)
+	if reopened[len(reopened)-1] != "[2024-01-02T00:00:01Z] [TEST.INFO] reopened" || reopened[len(reopened)-2] != records[len(records)-1] {
+		t.Errorf("expected writing to continue after reopening, got %q", reopened)
+	}
+	if _, err = NewCircularFileSink(path, 200); err == nil {
+		t.Error("expected reopening with another size to fail")
+	}
+}
+
+func TestCircularFileKeepsNewestRecords(t *testing.T) {
+	dir := t.TempDir()
+	for size := int64(60); size < 400; size += 37 {
+		path := filepath.Join(dir, fmt.Sprintf("ring-%d.log", size))
+		sink, err := NewCircularFileSink(path, size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var written []string
+		for i := 0; i < 100; i++ {
+			e := Entry{Time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Level: INFO, Message: fmt.Sprintf("%d %s", i, strings.Repeat("x", i%13))}
+			sink.WriteEntry(e)
+			written = append(written, e.String())
+		}
+		sink.Close()
+		records, err := ReadCircularFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		kept := written[len(written)-len(records):]
+		if len(records) == 0 || strings.Join(records, "\n") != strings.Join(kept, "\n") {
+			t.Errorf("expected a %d byte ring to hold the newest records, got %q", size, records)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWithNow(t *testing.T) {
+	fixed := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("SAST", 2*60*60))
+	cl, err := NewLog(filepath.Join(t.TempDir(), "clock.log"), "TEST", LEVEL_INFO, LEVEL_NONE, WithNow(func() time.Time { return fixed }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, _ := cl.Info("fixed")
+	expected := "[2021-03-04T03:06:07Z] [TEST.INFO] fixed"
+	if result != expected {
+		t.Errorf("expected '%s', got '%s'", expected, result)
+	}
+}
+
+func TestUptime(t *testing.T) {
+	c := NewCapture(WithUptime(), WithNow(steppingClock(time.Second)))
+	c.Info("first")
+	c.Info("second")
+	entries := c.Entries()
+	first, _ := entries[0].Field("uptime")
+	second, _ := entries[1].Field("uptime")
+	elapsed := entries[1].Time.Sub(entries[0].Time)
+	if first.(time.Duration) <= 0 || second.(time.Duration)-first.(time.Duration) != elapsed {
+		t.Errorf("expected uptime to advance with the clock by %v, got %v and %v", elapsed, first, second)
+	}
+}
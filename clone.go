@@ -0,0 +1,31 @@
+package logging
+
+// Clone returns a new log configured like this one, including the fields, attributes, component
+// and levels of this log, with opts applied on top, for example to write a job's entries to a
+// file of its own with WithPath. The clone shares no state with the original: it has its own
+// file, queues, counters and hash chain. Sinks, shippers, catch-ups and the fallback are not
+// carried over, since closing the clone would close them, so give the clone its own with
+// WithSink, WithShipper and WithFallback
+func (l *Log) Clone(opts ...Option) (*Log, error) {
+	l.levels.mu.RLock()
+	level := l.levels.level
+	components := make(map[string]int, len(l.levels.components))
+	for component, lv := range l.levels.components {
+		components[component] = lv
+	}
+	l.levels.mu.RUnlock()
+	state := func(clone *Log) {
+		clone.fields = l.fields
+		clone.attrs = append([]Attr(nil), l.attrs...)
+		clone.component = l.component
+		clone.env = l.env
+		clone.levels.components = components
+		clone.sinks = nil
+		clone.shippers = nil
+		clone.catchUps = nil
+		clone.fallback = nil
+	}
+	all := make([]Option, 0, len(l.opts)+1+len(opts))
+	all = append(append(append(all, l.opts...), state), opts...)
+	return NewLog(l.path, l.env, level, l.reportLevel, all...)
+}
@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	dir := t.TempDir()
+	original, err := NewLog(filepath.Join(dir, "app.log"), "TEST", LEVEL_WARNING, LEVEL_NONE, WithEncoder(JSONEncoder{}), WithSequence())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer original.Close()
+	original.SetLevel(LevelError)
+	base := original.Named("jobs").WithFields(Fields{"job": 7})
+	clone, err := base.Clone(WithPath(filepath.Join(dir, "job-7.log")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, _ := clone.Error("failed")
+	// the clone numbers its own entries, and "initialising log" was below its level
+	for _, expected := range []string{`"job":7`, `"component":"jobs"`, `"seq":1`} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("expected the clone's entry to contain %s, got '%s'", expected, result)
+		}
+	}
+	if result, _ = clone.Warning("retrying"); result != "" {
+		t.Errorf("expected the clone to keep the changed level, got '%s'", result)
+	}
+	clone.Close()
+	if _, err = original.Error("still open"); err != nil {
+		t.Errorf("expected closing the clone to leave the original open, got %v", err)
+	}
+	b, _ := os.ReadFile(filepath.Join(dir, "app.log"))
+	if strings.Contains(string(b), "failed") {
+		t.Error("expected the clone's entries not to be written to the original's file")
+	}
+}
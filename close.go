@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrClosed is returned when writing to or closing a log that has been closed
+var ErrClosed = errors.New("log is closed")
+
+// ShutdownResult reports how many buffered entries were delivered or lost during Shutdown
+type ShutdownResult struct {
+	Flushed, Dropped int
+}
+
+// Drainer is implemented by sinks that buffer entries, so that Shutdown can deliver them
+// before the sink is closed. Drain should give up when ctx is done, counting what is left as dropped
+type Drainer interface {
+	Drain(ctx context.Context) (flushed, dropped int, err error)
+}
+
+func (l *Log) isClosed() bool {
+	return l.closed != nil && l.closed.Load()
+}
+
+// markClosed marks the log as closed, returning false if it already was
+func (l *Log) markClosed() bool {
+	return l.closed == nil || l.closed.CompareAndSwap(false, true)
+}
+
+// Close flushes pending entries, closes every sink implementing io.Closer and marks the log
+// as closed; subsequent writes return ErrClosed. Child logs created with WithFields share the
+// parent's lifecycle
+func (l *Log) Close() error {
+	_, err := l.Shutdown(context.Background())
+	return err
+}
+
+// Shutdown stops the log accepting writes, drains sinks implementing Drainer until ctx is done,
+// then closes the sinks and the file as Close does
+func (l *Log) Shutdown(ctx context.Context) (result ShutdownResult, err error) {
+	if !l.markClosed() {
+		return result, ErrClosed
+	}
+	if l.done != nil {
+		// stopped before the final flush, so that no loop writes while the log is closing
+		close(l.done)
+		l.loops.Wait()
+	}
+	err = l.flush()
+	if l.async != nil {
+		flushed, dropped, dErr := l.async.Drain(ctx)
+		result.Flushed += flushed
+		result.Dropped += dropped
+		if dErr != nil && err == nil {
+			err = dErr
+		}
+	}
+	for _, s := range l.sinks {
+		d, ok := s.(Drainer)
+		if !ok {
+			continue
+		}
+		flushed, dropped, dErr := d.Drain(ctx)
+		result.Flushed += flushed
+		result.Dropped += dropped
+		if dErr != nil && err == nil {
+			err = dErr
+		}
+	}
+	for _, s := range l.sinks {
+		if c, ok := s.(io.Closer); ok {
+			if cErr := c.Close(); cErr != nil && err == nil {
+				err = cErr
+			}
+		}
+	}
+	if l.mu != nil {
+		l.mu.Lock()
+		l.writer.close()
+		l.mu.Unlock()
+	}
+	if l.file != nil {
+		l.file.Close()
+	}
+	return result, err
+}
+
+// flush writes any entries held back for later, such as pending repeat counts, the number
+// of entries suppressed by sampling and aggregated metrics
+func (l *Log) flush() error {
+	if err := l.flushMetrics(); err != nil {
+		return err
+	}
+	if summary := l.sampler.report(l.now(), true); summary != "" {
+		if _, err := l.writeEntry(l.newEntry(WARNING, summary)); err != nil {
+			return err
+		}
+	}
+	if l.dedup == nil {
+		return nil
+	}
+	l.dedup.mu.Lock()
+	repeated := l.dedup.repeated()
+	l.dedup.mu.Unlock()
+	if repeated == nil {
+		return nil
+	}
+	_, err := l.writeEntry(l.newEntry(repeated.Level, repeated.Message))
+	return err
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+type closerSink struct {
+	closed bool
+}
+
+func (s *closerSink) WriteEntry(e Entry) error {
+	return nil
+}
+
+func (s *closerSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestClose(t *testing.T) {
+	sink := &closerSink{}
+	cl := NewTestLog(t, WithSink(sink), WithDedup(time.Hour))
+	cl.Info("repeated")
+	cl.Info("repeated")
+	if err := cl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !sink.closed {
+		t.Error("expected the sink to be closed")
+	}
+	result, err := cl.GetLog(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 || !strings.Contains(result[0], "message repeated 1 times: repeated") {
+		t.Errorf("expected the pending repeat count to be flushed on close, got %v", result)
+	}
+	if _, err = cl.WithFields(Fields{"a": 1}).Info("after close"); err != ErrClosed {
+		t.Errorf("expected writes after close to return ErrClosed, got %v", err)
+	}
+	if err = cl.Close(); err != ErrClosed {
+		t.Errorf("expected a second close to return ErrClosed, got %v", err)
+	}
+}
+
+type drainingSink struct {
+	pending int
+}
+
+func (s *drainingSink) WriteEntry(e Entry) error {
+	s.pending++
+	return nil
+}
+
+func (s *drainingSink) Drain(ctx context.Context) (int, int, error) {
+	if ctx.Err() != nil {
+		return 0, s.pending, ctx.Err()
+	}
+	flushed := s.pending
+	s.pending = 0
+	return flushed, 0, nil
+}
+
+func TestShutdown(t *testing.T) {
+	sink := &drainingSink{}
+	sl := NewTestLog(t, WithSink(sink))
+	sl.Info("buffered")
+	result, err := sl.Shutdown(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Flushed != 2 || result.Dropped != 0 {
+		t.Errorf("expected 2 flushed and 0 dropped entries, got %+v", result)
+	}
+	if _, err = sl.Info("late"); err != ErrClosed {
+		t.Errorf("expected writes after shutdown to return ErrClosed, got %v", err)
+	}
+
+	sink = &drainingSink{}
+	sl = NewTestLog(t, WithSink(sink))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err = sl.Shutdown(ctx)
+	if err != context.Canceled || result.Dropped != 1 {
+		t.Errorf("expected the expired deadline to drop the buffered entry, got %+v and %v", result, err)
+	}
+}
+
+func TestCloseStopsLoops(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ring := NewRingBuffer(10)
+	cl, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithSink(ring),
+		WithSampling(INFO, 2), WithBackpressureWarning(10, 0, time.Hour),
+		WithWatchdog(WatchdogPolicy{Interval: time.Hour}), WithMetricAggregation(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl.Count("jobs", 1)
+	start := time.Now()
+	if err = cl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// the loops return on Close rather than at their next tick
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("expected the background loops to stop, got %d more goroutines", after-before)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("expected Close to return promptly, took %s", time.Since(start))
+	}
+	if e := ring.Entries()[len(ring.Entries())-1]; e.Message != "jobs=1" {
+		t.Errorf("expected the aggregated metric to be written once on Close, got %s", e.Message)
+	}
+}
@@ -0,0 +1,38 @@
+package logging
+
+import "os/exec"
+
+// CmdCapture holds the writers logging a command's output
+type CmdCapture struct {
+	Stdout, Stderr *LevelWriter
+}
+
+// CaptureCmd logs each line of the command's stdout and stderr at the given levels. Indented
+// continuation lines, such as the frames of a stack trace, are kept in the entry of the line
+// they follow. Call Flush once the command has finished
+func (l *Log) CaptureCmd(cmd *exec.Cmd, stdoutLevel, stderrLevel string) *CmdCapture {
+	c := &CmdCapture{
+		Stdout: &LevelWriter{l: l, level: stdoutLevel, group: true},
+		Stderr: &LevelWriter{l: l, level: stderrLevel, group: true},
+	}
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+	return c
+}
+
+// Flush logs any output still held by the writers
+func (c *CmdCapture) Flush() error {
+	err := c.Stdout.Flush()
+	if sErr := c.Stderr.Flush(); err == nil {
+		err = sErr
+	}
+	return err
+}
+
+// RunCmd runs the command, logging stdout at INFO and stderr at ERROR
+func (l *Log) RunCmd(cmd *exec.Cmd) error {
+	c := l.CaptureCmd(cmd, INFO, ERROR)
+	err := cmd.Run()
+	c.Flush()
+	return err
+}
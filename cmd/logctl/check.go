@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	logging "github.com/blainemoser/Logging"
+)
+
+func runCheck(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	format := flags.String("format", "text", "log format: json, logfmt or text")
+	createDirs := flags.Bool("create-dirs", false, "missing directories of the log file are created")
+	rotation := flags.String("rotation", "", "rotated file name pattern, e.g. app-%Y%m%d.log")
+	budget := flags.Int64("budget", 0, "disk budget in bytes (default none)")
+	syslog := flags.String("syslog", "", "syslog server as network://address, e.g. udp://localhost:514")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected a single log file")
+	}
+	enc, ok := encoders[*format]
+	if !ok {
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+	opts := []logging.Option{logging.WithEncoder(enc)}
+	if *createDirs {
+		opts = append(opts, logging.WithCreateDirs(0o755))
+	}
+	if *rotation != "" {
+		opts = append(opts, logging.WithRotationName(*rotation, false))
+	}
+	if *budget != 0 {
+		opts = append(opts, logging.WithDiskBudget(*budget))
+	}
+	if *syslog != "" {
+		sink, err := syslogSink(*syslog)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, logging.WithSink(sink))
+	}
+	err := logging.ValidateConfig(flags.Arg(0), "", logging.LEVEL_DEBUG, logging.LEVEL_DEBUG, opts...)
+	var config *logging.ConfigError
+	if !errors.As(err, &config) {
+		if err == nil {
+			fmt.Fprintln(stdout, "ok")
+		}
+		return err
+	}
+	for _, problem := range config.Problems {
+		fmt.Fprintln(stdout, problem)
+	}
+	return fmt.Errorf("%d problems found", len(config.Problems))
+}
+
+// syslogSink returns a sink for the syslog server given as network://address
+func syslogSink(server string) (*logging.SyslogSink, error) {
+	network, addr, ok := strings.Cut(server, "://")
+	if !ok {
+		return nil, fmt.Errorf("syslog server '%s' is not network://address", server)
+	}
+	return logging.NewSyslogSink(network, addr, "logctl", logging.RFC5424), nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	if err := runCheck([]string{path}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "ok\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+	out.Reset()
+	if err := runCheck([]string{"-rotation", "app-%q.log", "-budget", "-1", path}); err == nil {
+		t.Fatal("expected problems to be reported")
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 2 {
+		t.Errorf("expected 2 problems, got %q", out.String())
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	logging "github.com/blainemoser/Logging"
+)
+
+func runCompact(args []string) error {
+	flags := flag.NewFlagSet("compact", flag.ContinueOnError)
+	spec := flags.String("spec", "drop=DEBUG:7d,dedup", "compaction spec, e.g. drop=DEBUG:7d,dedup,compress=gzip")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected a single log file")
+	}
+	c, err := logging.ParseCompaction(*spec)
+	if err != nil {
+		return err
+	}
+	result, err := logging.Compact(flags.Arg(0), c)
+	for _, name := range result.Files {
+		fmt.Fprintf(stdout, "compacted\t%s\n", name)
+	}
+	fmt.Fprintf(stdout, "dropped %d entries, collapsed %d repeats, saved %d bytes\n", result.Dropped, result.Repeated, result.Saved)
+	return err
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCompact(t *testing.T) {
+	dir := t.TempDir()
+	rotated := filepath.Join(dir, "app.log.1")
+	os.WriteFile(rotated, []byte("[2023-03-01T10:00:00Z] [api.DEBUG] cache miss\n[2023-03-01T10:00:01Z] [api.ERROR] charge failed\n"), 0644)
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	if err := runCompact([]string{filepath.Join(dir, "app.log")}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "compacted\t"+rotated+"\ndropped 1 entries") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+	if b, _ := os.ReadFile(rotated); string(b) != "[2023-03-01T10:00:01Z] [api.ERROR] charge failed\n" {
+		t.Errorf("expected only the error to be kept, got %q", b)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	logging "github.com/blainemoser/Logging"
+)
+
+func runCompress(args []string) error {
+	flags := flag.NewFlagSet("compress", flag.ContinueOnError)
+	spec := flags.String("retention", "compress=gzip", "retention spec, e.g. keep=10,age=30d,compress=gzip")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected a single log file")
+	}
+	retention, err := logging.ParseRetention(*spec)
+	if err != nil {
+		return err
+	}
+	result, err := logging.ApplyRetention(flags.Arg(0), retention)
+	for _, name := range result.Removed {
+		fmt.Fprintf(stdout, "removed\t%s\n", name)
+	}
+	for _, name := range result.Compressed {
+		fmt.Fprintf(stdout, "compressed\t%s\n", name)
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	logging "github.com/blainemoser/Logging"
+)
+
+var encoders = map[string]logging.Encoder{
+	"json":   logging.JSONEncoder{},
+	"logfmt": logging.LogfmtEncoder{},
+	"text":   logging.TextEncoder{},
+}
+
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	from := flags.String("from", "", "input format: text, json or docker (default any)")
+	to := flags.String("to", "json", "output format: json, logfmt, text or csv")
+	flags.StringVar(to, "format", "json", "same as -to")
+	output := flags.String("o", "", "output file (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 2 && *output == "" {
+		*output = flags.Arg(1)
+	} else if flags.NArg() != 1 {
+		return errors.New("expected a log file and optionally an output file")
+	}
+	enc, ok := encoders[*to]
+	if !ok && *to != "csv" {
+		return fmt.Errorf("unknown format '%s'", *to)
+	}
+	src, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	var dst io.Writer = stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		dst = file
+	}
+	if *to == "csv" {
+		_, err = logging.ConvertCSV(dst, src, *from)
+		return err
+	}
+	_, err = logging.ConvertFrom(dst, src, *from, enc)
+	return err
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunConvert(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "app.log")
+	out := filepath.Join(dir, "app.logfmt")
+	os.WriteFile(src, []byte("[2023-03-01T10:00:00Z] [api.INFO] started\n"), 0644)
+	if err := runConvert([]string{"-format", "logfmt", "-o", out, src}); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "time=2023-03-01T10:00:00Z env=api level=INFO msg=started\n" {
+		t.Errorf("expected the log converted to logfmt, got %s", content)
+	}
+	if err := runConvert([]string{"-format", "xml", src}); err == nil {
+		t.Error("expected an unknown format to fail")
+	}
+}
+
+func TestRunConvertFromTo(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "app.log")
+	out := filepath.Join(dir, "app.csv")
+	os.WriteFile(src, []byte("[2023-03-01T10:00:00Z] [api.INFO] started\n"), 0644)
+	if err := runConvert([]string{"--from", "text", "--to", "csv", src, out}); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(out)
+	if string(content) != "timestamp,env,level,message,attrs\n2023-03-01T10:00:00Z,api,INFO,started,\n" {
+		t.Errorf("expected the log converted to CSV, got %s", content)
+	}
+	if err := runConvert([]string{"--from", "json", src, out}); err == nil {
+		t.Error("expected a text log to be refused as JSON")
+	}
+}
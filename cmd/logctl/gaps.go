@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	logging "github.com/blainemoser/Logging"
+)
+
+func runGaps(args []string) error {
+	flags := flag.NewFlagSet("gaps", flag.ContinueOnError)
+	threshold := flags.Duration("threshold", 5*time.Minute, "report gaps longer than this")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected a single log file")
+	}
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gaps, err := logging.FindGaps(file, *threshold)
+	if err != nil {
+		return err
+	}
+	for _, gap := range gaps {
+		fmt.Fprintf(stdout, "%s\t%s\t%s\n", gap.From.Format(time.RFC3339Nano), gap.To.Format(time.RFC3339Nano), gap.Duration())
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Command logctl works with log files written by the logging package.
+//
+// Usage:
+//
+//	logctl check [-format name] [-create-dirs] [-rotation pattern] [-budget bytes] [-syslog network://address] file
+//	logctl compact [-spec spec] file
+//	logctl compress [-retention spec] file
+//	logctl convert [-from text|json|docker] [-to json|logfmt|text|csv] [-o output] file [output]
+//	logctl gaps [-threshold duration] file
+//	logctl replay [-from time] [-to time] [-format name] [-syslog network://address] file
+//	logctl rotate -pid pid [-signal name] | -url url [-token token]
+//	logctl serve [-addr address] [-token token] [-mask keys] [-redact regexp]... file
+//	logctl stats [-interval duration] [-largest count] file
+//	logctl top [-n count] [-from time] [-to time] file
+//	logctl verify file...
+//	logctl view [-mask keys] [-redact regexp]... file
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// stdout is where commands write their results, replaced in tests
+var stdout io.Writer = os.Stdout
+
+type command struct {
+	run   func(args []string) error
+	usage string
+}
+
+var commands = map[string]command{
+	"check":    {runCheck, "check [-format name] [-create-dirs] [-rotation pattern] [-budget bytes] [-syslog network://address] file"},
+	"compact":  {runCompact, "compact [-spec spec] file"},
+	"compress": {runCompress, "compress [-retention spec] file"},
+	"convert":  {runConvert, "convert [-from text|json|docker] [-to json|logfmt|text|csv] [-o output] file [output]"},
+	"gaps":     {runGaps, "gaps [-threshold duration] file"},
+	"replay":   {runReplay, "replay [-from time] [-to time] [-format name] [-syslog network://address] file"},
+	"rotate":   {runRotate, "rotate -pid pid [-signal name] | -url url [-token token]"},
+	"serve":    {runServe, "serve [-addr address] [-token token] [-mask keys] [-redact regexp]... file"},
+	"stats":    {runStats, "stats [-interval duration] [-largest count] file"},
+	"top":      {runTop, "top [-n count] [-from time] [-to time] file"},
+	"verify":   {runVerify, "verify file..."},
+	"view":     {runView, "view [-mask keys] [-redact regexp]... file"},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "logctl: unknown command '%s'\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "logctl %s: %s\n", os.Args[1], err.Error())
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(os.Stderr, "usage:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  logctl %s\n", commands[name].usage)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	logging "github.com/blainemoser/Logging"
+)
+
+func runReplay(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fromFlag := flags.String("from", "", "only replay entries written from this RFC 3339 time")
+	toFlag := flags.String("to", "", "only replay entries written before this RFC 3339 time")
+	format := flags.String("format", "json", "output format when not sent to syslog: json, logfmt or text")
+	syslog := flags.String("syslog", "", "syslog server to send the entries to as network://address")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected a single log file")
+	}
+	from, err := parseTime(*fromFlag)
+	if err != nil {
+		return err
+	}
+	to, err := parseTime(*toFlag)
+	if err != nil {
+		return err
+	}
+	var sink logging.Sink
+	if *syslog != "" {
+		s, err := syslogSink(*syslog)
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+		sink = s
+	} else {
+		enc, ok := encoders[*format]
+		if !ok {
+			return fmt.Errorf("unknown format '%s'", *format)
+		}
+		sink = logging.NewEncoderSink(stdout, enc)
+	}
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	replayed, err := logging.Replay(file, from, to, sink)
+	if *syslog != "" {
+		fmt.Fprintf(stdout, "replayed %d entries\n", replayed)
+	}
+	return err
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunReplay(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(src, []byte("[2023-03-01T10:00:00Z] [api.INFO] started\n[2023-03-01T11:00:00Z] [api.INFO] restarted\n"), 0644)
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	if err := runReplay([]string{"-from", "2023-03-01T10:30:00Z", "-format", "logfmt", src}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "time=2023-03-01T11:00:00Z env=api level=INFO msg=restarted\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
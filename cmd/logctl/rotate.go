@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+func runRotate(args []string) error {
+	flags := flag.NewFlagSet("rotate", flag.ContinueOnError)
+	pid := flags.Int("pid", 0, "process to signal, which handles it with RotateOnSignal")
+	sig := flags.String("signal", "USR2", "signal to send with -pid")
+	url := flags.String("url", "", "admin endpoint served by RotateHandler to POST to")
+	token := flags.String("token", "", "bearer token for -url")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 || (*pid == 0) == (*url == "") {
+		return errors.New("expected either -pid or -url")
+	}
+	if *url != "" {
+		return postRotate(*url, *token)
+	}
+	s, ok := signals[strings.TrimPrefix(strings.ToUpper(*sig), "SIG")]
+	if !ok {
+		return fmt.Errorf("unknown signal '%s'", *sig)
+	}
+	process, err := os.FindProcess(*pid)
+	if err != nil {
+		return err
+	}
+	return process.Signal(s)
+}
+
+func postRotate(url, token string) error {
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", res.Status, strings.TrimSpace(string(body)))
+	}
+	if rotated := strings.TrimSpace(string(body)); rotated != "" {
+		fmt.Fprintln(stdout, rotated)
+	}
+	return nil
+}
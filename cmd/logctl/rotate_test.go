@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunRotateURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer t0ken" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("/var/log/app.log.20240102T150405\n"))
+	}))
+	defer server.Close()
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	if err := runRotate([]string{"-url", server.URL, "-token", "t0ken"}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "/var/log/app.log.20240102T150405\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+	if err := runRotate([]string{"-url", server.URL}); err == nil {
+		t.Error("expected an error without the token")
+	}
+	if err := runRotate(nil); err == nil {
+		t.Error("expected an error without -pid or -url")
+	}
+}
+
+func TestRunCompress(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "app.log.20240101T000000")
+	recent := filepath.Join(dir, "app.log.20240102T000000")
+	os.WriteFile(old, []byte("old\n"), 0644)
+	os.WriteFile(recent, []byte("recent\n"), 0644)
+	os.Chtimes(old, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour))
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	if err := runCompress([]string{"-retention", "keep=1,compress=gzip", filepath.Join(dir, "app.log")}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "removed\t"+old+"\ncompressed\t"+recent+".gz\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
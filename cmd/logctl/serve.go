@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+
+	logging "github.com/blainemoser/Logging"
+)
+
+// listenAndServe serves the handler, replaced in tests
+var listenAndServe = http.ListenAndServe
+
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", "127.0.0.1:8080", "address to listen on")
+	token := flags.String("token", "", "bearer token required by every request, if set")
+	mask := flags.String("mask", "", "comma separated field keys whose values are masked")
+	var patterns patternsFlag
+	flags.Var(&patterns, "redact", "regular expression replaced with [REDACTED], may be repeated")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected a single log file")
+	}
+	var profile *logging.RedactionProfile
+	if *mask != "" || len(patterns) > 0 {
+		var fields []string
+		if *mask != "" {
+			fields = strings.Split(*mask, ",")
+		}
+		profile = logging.NewRedactionProfile(fields, patterns...)
+	}
+	handler := logging.FileHandler(flags.Arg(0), profile)
+	if *token != "" {
+		handler = logging.TokenAuth(handler, map[string]string{"logctl": *token})
+	}
+	fmt.Fprintf(stdout, "serving %s on http://%s/\n", flags.Arg(0), *addr)
+	return listenAndServe(*addr, handler)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestRunServe(t *testing.T) {
+	var handler http.Handler
+	listenAndServe = func(addr string, h http.Handler) error {
+		handler = h
+		return nil
+	}
+	defer func() { listenAndServe = http.ListenAndServe }()
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	if err := runServe([]string{"-addr", "127.0.0.1:9999", "-token", "t0ken", "app.log"}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "serving app.log on http://127.0.0.1:9999/\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected requests without the token to be refused, got %d", rec.Code)
+	}
+}
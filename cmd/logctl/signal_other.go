@@ -0,0 +1,8 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package main
+
+import "os"
+
+// signals is empty where processes cannot be sent user signals; use logctl rotate -url
+var signals = map[string]os.Signal{}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	logging "github.com/blainemoser/Logging"
+)
+
+// maxStatsMessage is the length messages of the largest entries are cut to
+const maxStatsMessage = 60
+
+func runStats(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	interval := flags.Duration("interval", time.Hour, "interval the write rate is counted over")
+	largest := flags.Int("largest", 5, "number of largest entries to list")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected a single log file")
+	}
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	s, err := logging.Summarize(file, *interval, *largest)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "entries\t%d\n", s.Entries)
+	fmt.Fprintf(stdout, "unparsed\t%d\n", s.Unparsed)
+	if s.Entries == 0 {
+		return nil
+	}
+	fmt.Fprintf(stdout, "first\t%s\n", s.First.Format(time.RFC3339Nano))
+	fmt.Fprintf(stdout, "last\t%s\n", s.Last.Format(time.RFC3339Nano))
+	fmt.Fprintf(stdout, "span\t%s\n", s.Span())
+	levels := make([]string, 0, len(s.Levels))
+	for level := range s.Levels {
+		levels = append(levels, level)
+	}
+	sort.Strings(levels)
+	for _, level := range levels {
+		fmt.Fprintf(stdout, "level\t%s\t%d\n", level, s.Levels[level])
+	}
+	for _, r := range s.Largest {
+		message := strings.ReplaceAll(r.Entry.Message, "\n", `\n`)
+		if len(message) > maxStatsMessage {
+			message = message[:maxStatsMessage] + "..."
+		}
+		fmt.Fprintf(stdout, "largest\t%d\t%s\t%s\t%s\n", r.Size, r.Entry.Time.Format(time.RFC3339Nano), r.Entry.Level, message)
+	}
+	for _, b := range s.Rate {
+		fmt.Fprintf(stdout, "rate\t%s\t%d\n", b.Start.Format(time.RFC3339Nano), b.Count)
+	}
+	return nil
+}
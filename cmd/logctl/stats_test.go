@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunStats(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(src, []byte("[2023-03-01T10:00:00Z] [api.INFO] started\n[2023-03-01T10:30:00Z] [api.ERROR] failed\n[2023-03-01T11:15:00Z] [api.INFO] restarted\n"), 0644)
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	if err := runStats([]string{"-largest", "1", src}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "entries\t3\nunparsed\t0\n" +
+		"first\t2023-03-01T10:00:00Z\nlast\t2023-03-01T11:15:00Z\nspan\t1h15m0s\n" +
+		"level\tERROR\t1\nlevel\tINFO\t2\n" +
+		"largest\t43\t2023-03-01T11:15:00Z\tINFO\trestarted\n" +
+		"rate\t2023-03-01T10:00:00Z\t2\nrate\t2023-03-01T11:00:00Z\t1\n"
+	if out.String() != expected {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	logging "github.com/blainemoser/Logging"
+)
+
+func runTop(args []string) error {
+	flags := flag.NewFlagSet("top", flag.ContinueOnError)
+	n := flags.Int("n", 10, "number of templates to report, 0 for all")
+	fromFlag := flags.String("from", "", "only count entries written from this RFC 3339 time")
+	toFlag := flags.String("to", "", "only count entries written before this RFC 3339 time")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected a single log file")
+	}
+	from, err := parseTime(*fromFlag)
+	if err != nil {
+		return err
+	}
+	to, err := parseTime(*toFlag)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	top, err := logging.TopMessages(file, from, to, *n)
+	if err != nil {
+		return err
+	}
+	for _, m := range top {
+		fmt.Fprintf(stdout, "%d\t%s\n", m.Count, m.Template)
+	}
+	return nil
+}
+
+// parseTime parses an RFC 3339 time flag, returning the zero time when it is empty
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunTop(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(src, []byte("[2023-03-01T10:00:00Z] [api.INFO] job 1 done\n[2023-03-01T10:01:00Z] [api.WARNING] slow disk\n[2023-03-01T10:02:00Z] [api.INFO] job 2 done\n"), 0644)
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	if err := runTop([]string{"-n", "1", src}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "2\tjob * done\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	logging "github.com/blainemoser/Logging"
+)
+
+func runVerify(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("expected at least one log file")
+	}
+	for _, path := range flags.Args() {
+		if err := logging.Verify(path); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		fmt.Fprintf(stdout, "%s: ok\n", path)
+	}
+	return nil
+}
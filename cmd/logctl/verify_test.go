@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunVerify(t *testing.T) {
+	dir := t.TempDir()
+	good, bad := filepath.Join(dir, "good.log"), filepath.Join(dir, "bad.log")
+	os.WriteFile(good, []byte("[2023-03-01T10:00:00Z] [api.INFO] started\n"), 0644)
+	os.WriteFile(bad, []byte("[2023-03-01T10:00:00Z] [api.INFO] started\n[2023-03-01T09:00:00Z] [api.INFO] earlier\n"), 0644)
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	if err := runVerify([]string{good}); err != nil || out.String() != good+": ok\n" {
+		t.Errorf("expected %s to verify, got %v and %q", good, err, out.String())
+	}
+	if err := runVerify([]string{bad}); err == nil || !strings.Contains(err.Error(), "record 2 at offset 42") {
+		t.Errorf("expected %s to fail at record 2, got %v", bad, err)
+	}
+}
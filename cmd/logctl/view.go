@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	logging "github.com/blainemoser/Logging"
+)
+
+// patternsFlag collects the regular expressions given by repeating a flag
+type patternsFlag []*regexp.Regexp
+
+func (p *patternsFlag) String() string {
+	return fmt.Sprint(*p)
+}
+
+func (p *patternsFlag) Set(value string) error {
+	pattern, err := regexp.Compile(value)
+	if err != nil {
+		return err
+	}
+	*p = append(*p, pattern)
+	return nil
+}
+
+func runView(args []string) error {
+	flags := flag.NewFlagSet("view", flag.ContinueOnError)
+	mask := flags.String("mask", "", "comma separated field keys whose values are masked")
+	var patterns patternsFlag
+	flags.Var(&patterns, "redact", "regular expression replaced with [REDACTED], may be repeated")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected a single log file")
+	}
+	var fields []string
+	if *mask != "" {
+		fields = strings.Split(*mask, ",")
+	}
+	profile := logging.NewRedactionProfile(fields, patterns...)
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		fmt.Fprintln(stdout, profile.Apply(scanner.Text()))
+	}
+	return scanner.Err()
+}
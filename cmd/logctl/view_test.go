@@ -0,0 +1,22 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunView(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(src, []byte("[2023-03-01T10:00:00Z] [api.INFO] login user=ann card=4111 ref=A-77\n"), 0644)
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = os.Stdout }()
+	if err := runView([]string{"-mask", "user,card", "-redact", `A-\d+`, src}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "[2023-03-01T10:00:00Z] [api.INFO] login user=**** card=**** ref=[REDACTED]\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestRunCmd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	c := NewCapture()
+	script := `echo starting; echo "panic: boom" >&2; printf '\tmain.go:12\n\tmain.go:30\n' >&2; echo done`
+	if err := c.RunCmd(exec.Command("sh", "-c", script)); err != nil {
+		t.Fatal(err)
+	}
+	if !c.HasEntry(INFO, "starting") || !c.HasEntry(INFO, "done") {
+		t.Errorf("expected stdout lines at info, got %v", c.Entries())
+	}
+	if !c.HasEntry(ERROR, "panic: boom\n\tmain.go:12\n\tmain.go:30") {
+		t.Errorf("expected the stack trace grouped into one error entry, got %v", c.Entries())
+	}
+	if len(c.Entries()) != 3 {
+		t.Errorf("expected three entries, got %d", len(c.Entries()))
+	}
+}
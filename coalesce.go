@@ -0,0 +1,68 @@
+package logging
+
+import "sync"
+
+// coalescer batches the records of goroutines writing at the same time into a single file
+// write, a group commit: the first writer becomes the leader and writes every record queued
+// by the time it takes the lock, while later writers wait for it and then for the next leader
+type coalescer struct {
+	mu      sync.Mutex
+	pending []*commit
+	leading bool
+}
+
+type commit struct {
+	msg     []byte
+	written []byte
+	err     error
+	lead    bool // set instead of a result when the writer should lead the next batch
+	done    chan struct{}
+}
+
+// writeCoalesced writes a record as writeFile does, in a batch with the records of any
+// concurrent writers
+func (l *Log) writeCoalesced(msg []byte) ([]byte, error) {
+	g := l.coalescer
+	c := &commit{msg: msg, done: make(chan struct{})}
+	g.mu.Lock()
+	g.pending = append(g.pending, c)
+	if g.leading {
+		g.mu.Unlock()
+		<-c.done
+		if !c.lead {
+			return c.written, c.err
+		}
+		g.mu.Lock()
+	}
+	g.leading = true
+	batch := g.pending
+	g.pending = nil
+	g.mu.Unlock()
+
+	msgs := make([][]byte, len(batch))
+	for i, c := range batch {
+		msgs[i] = c.msg
+	}
+	written, err := l.writeRecords(msgs)
+	for i, c := range batch {
+		if i < len(written) {
+			c.written = written[i]
+		}
+		c.err = err
+		if c != batch[0] {
+			close(c.done)
+		}
+	}
+
+	g.mu.Lock()
+	if len(g.pending) > 0 {
+		// hand over to a waiting writer rather than writing for others indefinitely
+		next := g.pending[0]
+		next.lead = true
+		close(next.done)
+	} else {
+		g.leading = false
+	}
+	g.mu.Unlock()
+	return batch[0].written, batch[0].err
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gatedFS counts the writes to its files and, once armed, holds the next write until release
+// is closed, so that writers pile up behind it
+type gatedFS struct {
+	FS
+	writes  atomic.Int64
+	armed   atomic.Bool
+	held    chan struct{} // closed once a write is held
+	release chan struct{}
+}
+
+type gatedFile struct {
+	File
+	fs *gatedFS
+}
+
+func (fs *gatedFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	f, err := fs.FS.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &gatedFile{File: f, fs: fs}, nil
+}
+
+func (f *gatedFile) Write(p []byte) (int, error) {
+	f.fs.writes.Add(1)
+	if f.fs.armed.CompareAndSwap(true, false) {
+		close(f.fs.held)
+		<-f.fs.release
+	}
+	return f.File.Write(p)
+}
+
+func TestWriteCoalescing(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "coalesce.log")
+	fs := &gatedFS{FS: osFS{}, held: make(chan struct{}), release: make(chan struct{})}
+	cl, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithWriteCoalescing(), WithHashChain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs.writes.Store(0)
+	fs.armed.Store(true)
+	var wg sync.WaitGroup
+	write := func(i int) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := cl.Info(fmt.Sprintf("entry %d", i))
+			if err != nil || !strings.Contains(result, fmt.Sprintf("entry %d chain=", i)) {
+				t.Errorf("expected entry %d to be written with its hash, got '%s', %v", i, result, err)
+			}
+		}()
+	}
+	// the first writer leads and is held in its write while the others queue behind it
+	write(0)
+	<-fs.held
+	for i := 1; i < 50; i++ {
+		write(i)
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		cl.coalescer.mu.Lock()
+		pending := len(cl.coalescer.pending)
+		cl.coalescer.mu.Unlock()
+		if pending == 49 {
+			break
+		}
+		if time.Now().After(deadline) {
+			close(fs.release)
+			t.Fatalf("expected 49 writers to queue, got %d", pending)
+		}
+	}
+	close(fs.release)
+	wg.Wait()
+	cl.Close()
+	if writes := fs.writes.Load(); writes != 2 {
+		t.Errorf("expected the queued records to share one write after the first, got %d writes", writes)
+	}
+	if err = Verify(path); err != nil {
+		t.Errorf("expected the coalesced records to be intact and chained, got %v", err)
+	}
+	b, _ := os.ReadFile(path)
+	if n := strings.Count(string(b), "\n"); n != 51 {
+		t.Errorf("expected 51 records, got %d", n)
+	}
+}
@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownCode is passed to the error handler when an entry is given a code that is not in
+// the log's catalog; see WithCodeCatalog
+var ErrUnknownCode = errors.New("unknown event code")
+
+// CodeCatalog maps stable event codes, such as "E1042", to descriptions, so runbooks can be
+// keyed off codes rather than messages that change
+type CodeCatalog map[string]string
+
+// Code returns a child log whose entries carry the event code as the attribute "code", e.g.
+// l.Code("E1042").Error("payment provider timed out"). If the log has a catalog and the code
+// is not in it, the error handler is called with ErrUnknownCode, but the code is still attached
+func (l *Log) Code(code string) *Log {
+	if l.codes != nil {
+		if _, ok := l.codes[code]; !ok {
+			l.handleError(fmt.Errorf("%w: %s", ErrUnknownCode, code))
+		}
+	}
+	return l.With(String("code", code))
+}
+
+// DescribeCode returns the description of a code in the log's catalog
+func (l *Log) DescribeCode(code string) (string, bool) {
+	description, ok := l.codes[code]
+	return description, ok
+}
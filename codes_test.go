@@ -0,0 +1,24 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCode(t *testing.T) {
+	cl := NewTestLog(t, WithRingBuffer(10), WithCodeCatalog(CodeCatalog{"E1042": "payment provider timed out"}))
+	var handled []error
+	cl.SetErrorHandler(func(err error) { handled = append(handled, err) })
+	cl.Code("E1042").Error("charge failed")
+	entries := cl.RecentEntries()
+	if code, _ := entries[len(entries)-1].Field("code"); code != "E1042" {
+		t.Errorf("expected the code to be attached, got %v", code)
+	}
+	if description, ok := cl.DescribeCode("E1042"); !ok || description != "payment provider timed out" {
+		t.Errorf("unexpected description %q", description)
+	}
+	cl.Code("E9999").Warning("unregistered")
+	if len(handled) != 1 || !errors.Is(handled[0], ErrUnknownCode) {
+		t.Errorf("expected the unknown code to be reported, got %v", handled)
+	}
+}
@@ -0,0 +1,152 @@
+package logging
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// combinedTimeLayout is the time format of the Apache/NCSA combined log format
+const combinedTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// CombinedLogEncoder formats the access entries written by Middleware in the Apache/NCSA
+// combined log format, read by tools such as GoAccess and AWStats:
+//
+//	127.0.0.1 - ann [10/Oct/2000:13:55:36 +0000] "GET /a.gif?x=1 HTTP/1.1" 200 2326 "http://ref/" "Mozilla/5.0"
+//
+// Entries without a method and status, which were not written by Middleware, are formatted
+// in the default text format
+type CombinedLogEncoder struct{}
+
+func (CombinedLogEncoder) AppendEntry(dst []byte, e Entry) []byte {
+	method, hasMethod := e.Field("method")
+	status, hasStatus := e.Field("status")
+	if !hasMethod || !hasStatus {
+		return appendEntry(dst, e)
+	}
+	host := combinedField(e, "remote_addr")
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	target := combinedField(e, "path")
+	if query, ok := e.Field("query"); ok {
+		target += "?" + fmt.Sprint(query)
+	}
+	dst = append(dst, host...)
+	dst = append(dst, " - "...)
+	dst = append(dst, combinedField(e, "user")...)
+	dst = append(dst, " ["...)
+	dst = e.Time.AppendFormat(dst, combinedTimeLayout)
+	dst = append(dst, "] "...)
+	dst = appendCombinedQuoted(dst, fmt.Sprintf("%v %s %s", method, target, combinedField(e, "proto")))
+	dst = append(dst, ' ')
+	dst = append(dst, fmt.Sprint(status)...)
+	dst = append(dst, ' ')
+	if bytes, ok := e.Field("bytes"); ok && fmt.Sprint(bytes) != "0" {
+		dst = append(dst, fmt.Sprint(bytes)...)
+	} else {
+		dst = append(dst, '-')
+	}
+	dst = append(dst, ' ')
+	dst = appendCombinedQuoted(dst, combinedField(e, "referer"))
+	dst = append(dst, ' ')
+	return appendCombinedQuoted(dst, combinedField(e, "user_agent"))
+}
+
+// combinedField returns the value of an access entry's attribute, or "-" if it has none
+func combinedField(e Entry, key string) string {
+	if s := attrString(e, key); s != "" {
+		return s
+	}
+	return "-"
+}
+
+// appendCombinedQuoted appends s in double quotes, escaping quotes, backslashes and control
+// characters as Apache does
+func appendCombinedQuoted(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			dst = append(dst, '\\', byte(r))
+		case r < 0x20 || r == 0x7f:
+			dst = append(dst, `\x`...)
+			dst = append(dst, strings.ToUpper(strconv.FormatInt(int64(r)|0x100, 16)[1:])...)
+		default:
+			dst = append(dst, string(r)...)
+		}
+	}
+	return append(dst, '"')
+}
+
+// combinedForm is a record written by CombinedLogEncoder for an access entry
+var combinedForm = regexp.MustCompile(`^(\S+) \S+ (\S+) \[(\d{2}/[A-Za-z]{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4})\] "((?:[^"\\]|\\.)*)" (\d{3}) (\S+) "((?:[^"\\]|\\.)*)" "((?:[^"\\]|\\.)*)"$`)
+
+// parseCombinedEntry parses an access record written by CombinedLogEncoder into an entry as
+// Middleware writes it: the message and level are made from the request and status, and the
+// request details are read into Fields, with the status as an int. The env is not recorded
+func parseCombinedEntry(record string) (Entry, error) {
+	m := combinedForm.FindStringSubmatch(record)
+	if m == nil {
+		return Entry{}, ErrMalformedRecord
+	}
+	t, err := time.Parse(combinedTimeLayout, m[3])
+	if err != nil {
+		return Entry{}, ErrMalformedRecord
+	}
+	status, _ := strconv.Atoi(m[5])
+	request := strings.SplitN(unquoteCombined(m[4]), " ", 3)
+	for len(request) < 3 {
+		request = append(request, "")
+	}
+	path, query, hasQuery := strings.Cut(request[1], "?")
+	fields := Fields{"method": request[0], "path": path, "status": status}
+	for key, value := range map[string]string{
+		"remote_addr": m[1],
+		"user":        m[2],
+		"proto":       request[2],
+		"bytes":       m[6],
+		"referer":     unquoteCombined(m[7]),
+		"user_agent":  unquoteCombined(m[8]),
+	} {
+		if value != "-" && value != "" {
+			fields[key] = value
+		}
+	}
+	if hasQuery {
+		fields["query"] = query
+	}
+	return Entry{
+		Time:    t.UTC(),
+		Level:   statusLevel(status),
+		Message: fmt.Sprintf("%s %s %d", request[0], path, status),
+		Fields:  fields,
+	}, nil
+}
+
+// unquoteCombined reverses appendCombinedQuoted, without the quotes
+func unquoteCombined(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if s[i] == 'x' && i+2 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCombinedLogEncoder(t *testing.T) {
+	now := time.Date(2000, 10, 10, 13, 55, 36, 0, time.UTC)
+	access, err := NewLog("/logs/access.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(NewMemFS()),
+		WithNow(func() time.Time { return now }), WithEncoder(CombinedLogEncoder{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := access.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	req := httptest.NewRequest("GET", "/a.gif?x=1", nil)
+	req.RemoteAddr = "127.0.0.1:5000"
+	req.SetBasicAuth("ann", "secret")
+	req.Header.Set("Referer", "http://ref/")
+	req.Header.Set("User-Agent", `Mozilla "5.0"`)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	expected := `127.0.0.1 - ann [10/Oct/2000:13:55:36 +0000] "GET /a.gif?x=1 HTTP/1.1" 200 5 "http://ref/" "Mozilla \"5.0\""` + "\n"
+	content, _ := access.readFile("/logs/access.log")
+	if !strings.HasSuffix(string(content), "\n"+expected) {
+		t.Errorf("expected %q, got %q", expected, content)
+	}
+	line := string(CombinedLogEncoder{}.AppendEntry(nil, Entry{Time: now, Env: "TEST", Level: INFO, Message: "started"}))
+	if line != "[2000-10-10T13:55:36Z] [TEST.INFO] started" {
+		t.Errorf("expected other entries in the text format, got '%s'", line)
+	}
+}
@@ -0,0 +1,295 @@
+package logging
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Compaction says how Compact rewrites rotated files to shrink long-term archives. Entries at
+// ERROR or more severe are always kept as they are, as are records that cannot be parsed.
+// Files written WithHashChain no longer verify once entries have been removed from them
+type Compaction struct {
+	// DropAfter maps levels to the age after which their entries are dropped, e.g.
+	// {DEBUG: 7 * 24 * time.Hour}
+	DropAfter map[string]time.Duration
+	// Dedup collapses each run of identical entries, apart from their times, into the first
+	// and an entry saying how many times it was repeated, as WithDedup does when writing
+	Dedup bool
+	// Compress recompresses the compacted files. Without it, files compressed with gzip or zstd
+	// stay compressed as they were and the rest are not compressed
+	Compress Compressor
+}
+
+// CompactionResult reports the work done by Compact
+type CompactionResult struct {
+	Files      []string // the compacted files, as named once compacted
+	Dropped    int      // entries dropped as older than DropAfter
+	Repeated   int      // entries collapsed by Dedup
+	Unreadable int      // records kept as they were because they could not be parsed
+	Saved      int64    // bytes, which is negative if the files grew
+}
+
+// ParseCompaction parses a compaction spec of comma separated settings: drop=LEVEL:AGE for each
+// level to drop, where AGE is a duration or a number of days such as 7d, dedup, and
+// compress=gzip or compress=zstd to recompress with gzip or zstd, e.g. "drop=DEBUG:7d,drop=INFO:90d,dedup,compress=gzip"
+func ParseCompaction(spec string) (Compaction, error) {
+	c := Compaction{DropAfter: make(map[string]time.Duration)}
+	for _, setting := range strings.Split(spec, ",") {
+		setting = strings.TrimSpace(setting)
+		if setting == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(setting, "=")
+		var err error
+		switch key {
+		case "drop":
+			level, age, ok := strings.Cut(value, ":")
+			if !ok {
+				err = fmt.Errorf("expected LEVEL:AGE")
+				break
+			}
+			c.DropAfter[strings.ToUpper(level)], err = parseAge(age)
+		case "dedup":
+			c.Dedup = true
+		case "compress":
+			var ok bool
+			if c.Compress, ok = compressors[value]; !ok {
+				err = fmt.Errorf("unsupported compression")
+			}
+		default:
+			err = fmt.Errorf("unknown setting")
+		}
+		if err != nil {
+			return Compaction{}, fmt.Errorf("invalid compaction setting '%s': %w", setting, err)
+		}
+	}
+	return c, nil
+}
+
+// Compact rewrites the rotated files of the log file at path as c says, oldest first, keeping
+// their modification times so retention still sees their age. Rotated files are those named
+// <path>.<suffix>
+func Compact(path string, c Compaction) (CompactionResult, error) {
+	fs := osFS{}
+	matches, err := fs.Glob(filepath.Join(filepath.Dir(path), filepath.Base(path)+".*"))
+	if err != nil {
+		return CompactionResult{}, err
+	}
+	backups := matches[:0]
+	for _, name := range matches {
+		if !strings.HasSuffix(name, quarantineSuffix) && !strings.HasSuffix(name, queueSuffix) {
+			backups = append(backups, name)
+		}
+	}
+	return compact(fs, backups, c, time.Now(), 0)
+}
+
+// Compact rewrites the log's rotated files as c says; see Compact
+func (l *Log) Compact(c Compaction) (CompactionResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	backups, err := l.backups()
+	if err != nil {
+		return CompactionResult{}, err
+	}
+	return compact(l.getFS(), backups, c, l.now(), l.fileMode)
+}
+
+func compact(fs FS, backups []string, c Compaction, now time.Time, mode os.FileMode) (CompactionResult, error) {
+	var result CompactionResult
+	modTimes := make(map[string]time.Time, len(backups))
+	for _, name := range backups {
+		if info, err := fs.Stat(name); err == nil {
+			modTimes[name] = info.ModTime()
+		}
+	}
+	// oldest first
+	sort.Slice(backups, func(i, j int) bool {
+		return modTimes[backups[i]].Before(modTimes[backups[j]])
+	})
+	for _, name := range backups {
+		compacted, err := compactFile(fs, name, c, now, mode, &result)
+		if err != nil {
+			return result, err
+		}
+		if ct, ok := fs.(chtimesFS); ok && !modTimes[name].IsZero() {
+			ct.Chtimes(compacted, modTimes[name], modTimes[name])
+		}
+		result.Files = append(result.Files, compacted)
+	}
+	return result, nil
+}
+
+// compactFile rewrites one file, replacing it with the compacted file, and returns the new name
+func compactFile(fs FS, name string, c Compaction, now time.Time, mode os.FileMode, result *CompactionResult) (string, error) {
+	src, err := fs.OpenFile(name, os.O_RDONLY, 0)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	r, target, err := decompress(name, src)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	compressor := c.Compress
+	if compressor == nil && target != name {
+		compressor = compressorFor(name)
+	}
+	if compressor != nil {
+		target += compressor.Extension()
+	}
+	// hidden, so that a file left by a failure is not taken for a rotated file
+	tmp := filepath.Join(filepath.Dir(name), "."+filepath.Base(target)+".compacting")
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+	dst, err := fs.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return "", err
+	}
+	var out io.Writer = dst
+	var zw io.WriteCloser
+	if compressor != nil {
+		if zw, err = compressor.NewWriter(dst); err != nil {
+			dst.Close()
+			fs.Remove(tmp)
+			return "", err
+		}
+		out = zw
+	}
+	buffered := bufio.NewWriter(out)
+	cp := &compactor{c: c, now: now, w: buffered, result: result}
+	err = cp.run(r)
+	if fErr := buffered.Flush(); err == nil {
+		err = fErr
+	}
+	if zw != nil {
+		if cErr := zw.Close(); err == nil {
+			err = cErr
+		}
+	}
+	if cErr := dst.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = fs.Rename(tmp, target)
+	}
+	if err != nil {
+		fs.Remove(tmp)
+		return "", err
+	}
+	before, after := sizeOf(fs, name), sizeOf(fs, target)
+	if target != name {
+		if err = fs.Remove(name); err != nil {
+			return target, err
+		}
+	}
+	result.Saved += before - after
+	return target, nil
+}
+
+// compactor filters the records of a file, collapsing runs of repeated entries
+type compactor struct {
+	c      Compaction
+	now    time.Time
+	w      *bufio.Writer
+	result *CompactionResult
+	// the run of repeated entries being collapsed
+	key    string
+	first  Entry
+	format string
+	count  int
+	last   time.Time
+}
+
+func (cp *compactor) run(r io.Reader) error {
+	scanner := newRecordScanner(r)
+	for {
+		record, err := scanner.next()
+		if err == io.EOF {
+			cp.endRun()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		cp.add(record)
+	}
+}
+
+func (cp *compactor) add(record string) {
+	e, err := ParseEntry(record)
+	if err != nil {
+		cp.result.Unreadable++
+		cp.endRun()
+		cp.write(record)
+		return
+	}
+	if severity, ok := Severity(e.Level); ok && severity <= syslogSeverities[ERROR] {
+		cp.endRun()
+		cp.write(record)
+		return
+	}
+	if age, ok := cp.c.DropAfter[e.Level]; ok && cp.now.Sub(e.Time) > age {
+		cp.result.Dropped++
+		return
+	}
+	if !cp.c.Dedup {
+		cp.write(record)
+		return
+	}
+	key := repeatKey(e)
+	if key == cp.key {
+		cp.count++
+		cp.last = e.Time
+		cp.result.Repeated++
+		return
+	}
+	cp.endRun()
+	cp.write(record)
+	cp.key, cp.first, cp.format = key, e, recordFormat(record)
+}
+
+// endRun writes an entry saying how many times the entry of the run was repeated, if it was
+func (cp *compactor) endRun() {
+	if cp.count > 0 {
+		e := cp.first
+		e.Time = cp.last
+		e.Message = fmt.Sprintf("message repeated %d times: %s", cp.count, e.Message)
+		var enc Encoder = TextEncoder{}
+		switch cp.format {
+		case "json":
+			enc = JSONEncoder{}
+		case "docker":
+			enc = DockerEncoder{}
+		}
+		cp.write(string(enc.AppendEntry(nil, e)))
+	}
+	cp.key, cp.count = "", 0
+}
+
+func (cp *compactor) write(record string) {
+	cp.w.WriteString(record)
+	cp.w.WriteByte('\n')
+}
+
+// repeatKey identifies an entry apart from its time and sequence number
+func repeatKey(e Entry) string {
+	e.Time, e.Seq = time.Time{}, 0
+	return e.String()
+}
+
+func sizeOf(fs FS, name string) int64 {
+	info, err := fs.Stat(name)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
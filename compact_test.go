@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCompact(t *testing.T) {
+	now := time.Date(2023, 3, 20, 0, 0, 0, 0, time.UTC)
+	fs := NewMemFS()
+	cl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithNow(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := "[2023-03-01T10:00:00Z] [api.DEBUG] cache miss\n" +
+		"[2023-03-01T10:00:01Z] [api.INFO] polling\n" +
+		"[2023-03-01T10:00:02Z] [api.INFO] polling\n" +
+		"[2023-03-01T10:00:03Z] [api.INFO] polling\n" +
+		"[2023-03-01T10:00:04Z] [api.ERROR] charge failed\n" +
+		"[2023-03-01T10:00:05Z] [api.ERROR] charge failed\n" +
+		"[2023-03-19T10:00:00Z] [api.DEBUG] recent\n"
+	file, _ := fs.OpenFile("/logs/app.log.1", os.O_CREATE|os.O_WRONLY, 0644)
+	file.Write([]byte(records))
+	file.Close()
+	modified := time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC)
+	fs.Chtimes("/logs/app.log.1", modified, modified)
+
+	c, err := ParseCompaction("drop=debug:7d,dedup,compress=gzip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := cl.Compact(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Files) != 1 || result.Files[0] != "/logs/app.log.1.gz" || result.Dropped != 1 || result.Repeated != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if info, _ := fs.Stat("/logs/app.log.1.gz"); !info.ModTime().Equal(modified) {
+		t.Errorf("expected the modification time to be kept, got %s", info.ModTime())
+	}
+	compacted, _ := fs.OpenFile("/logs/app.log.1.gz", os.O_RDONLY, 0)
+	gz, err := gzip.NewReader(compacted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(gz)
+	expected := "[2023-03-01T10:00:01Z] [api.INFO] polling\n" +
+		"[2023-03-01T10:00:03Z] [api.INFO] message repeated 2 times: polling\n" +
+		"[2023-03-01T10:00:04Z] [api.ERROR] charge failed\n" +
+		"[2023-03-01T10:00:05Z] [api.ERROR] charge failed\n" +
+		"[2023-03-19T10:00:00Z] [api.DEBUG] recent\n"
+	if !bytes.Equal(b, []byte(expected)) {
+		t.Errorf("unexpected compacted file\n%s", b)
+	}
+	if _, err = fs.Stat("/logs/app.log.1"); !os.IsNotExist(err) {
+		t.Error("expected the uncompressed file to be replaced")
+	}
+
+	// compacting again reads the gzipped file and keeps it gzipped
+	if result, err = cl.Compact(Compaction{Dedup: true}); err != nil || len(result.Files) != 1 || result.Files[0] != "/logs/app.log.1.gz" {
+		t.Errorf("expected the gzipped file to be compacted in place, got %+v and %v", result, err)
+	}
+}
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// levels holds the log level and the levels of named components, shared with child logs
+// so that they can be changed while the log is in use
+type levels struct {
+	mu         sync.RWMutex
+	level      int
+	components map[string]int
+}
+
+// lookup returns the level of the component or, failing that, of its closest parent, so
+// "http.client" falls back to "http" and then to the log level
+func (lv *levels) lookup(component string) int {
+	lv.mu.RLock()
+	defer lv.mu.RUnlock()
+	for component != "" {
+		if level, ok := lv.components[component]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(component, '.')
+		if i < 0 {
+			break
+		}
+		component = component[:i]
+	}
+	return lv.level
+}
+
+// ParseComponentLevels parses component levels in the form "sql=DEBUG, http=WARNING"
+func ParseComponentLevels(spec string) (map[string]Level, error) {
+	levels := make(map[string]Level)
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid component level '%s'", strings.TrimSpace(pair))
+		}
+		level, err := ParseLevel(value)
+		if err != nil {
+			return nil, err
+		}
+		levels[strings.TrimSpace(name)] = level
+	}
+	return levels, nil
+}
+
+// Named returns a child log for a component, adding it to entries as "component". Naming a
+// named log nests the components, so Named("http").Named("client") is "http.client". Entries
+// are filtered by the component's level, if one is set, instead of the log level
+func (l *Log) Named(name string) *Log {
+	child := *l
+	child.attrs = make([]Attr, 0, len(l.attrs)+1)
+	for _, a := range l.attrs {
+		if a.Key != "component" || l.component == "" {
+			child.attrs = append(child.attrs, a)
+		}
+	}
+	if l.component != "" {
+		name = l.component + "." + name
+	}
+	child.attrs = append(child.attrs, String("component", name))
+	child.component = name
+	return &child
+}
+
+// SetComponentLevel sets the log level of a component and its subcomponents, for this log
+// and every log sharing its configuration
+func (l *Log) SetComponentLevel(component string, level Level) {
+	l.levels.mu.Lock()
+	defer l.levels.mu.Unlock()
+	l.levels.components[component] = l.clampLevel(int(level))
+}
+
+// threshold returns the log level applying to the log's component
+func (l *Log) threshold() int {
+	return l.levels.lookup(l.component)
+}
@@ -0,0 +1,37 @@
+package logging
+
+import "testing"
+
+func TestComponentLevels(t *testing.T) {
+	levels, err := ParseComponentLevels("sql=DEBUG, http=error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCapture(WithComponentLevels(levels))
+	c.SetLevel(LevelWarning)
+	sql := c.Named("sql")
+	sql.Debug("select 1")
+	client := c.Named("http").Named("client")
+	client.Warning("slow upstream")
+	client.Error("upstream down")
+	c.Named("cache").Warning("eviction")
+	c.Debug("root debug")
+	entries := c.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected sql debug, http error and cache warning entries, got %v", entries)
+	}
+	if v, _ := entries[1].Field("component"); v != "http.client" {
+		t.Errorf("expected the nested component name, got %v", v)
+	}
+	c.SetComponentLevel("sql", LevelError)
+	sql.Debug("select 2")
+	if len(c.Entries()) != 3 {
+		t.Error("expected a changed component level to apply to existing named logs")
+	}
+	if _, err := ParseComponentLevels("sql"); err == nil {
+		t.Error("expected a component without a level to fail")
+	}
+	if _, err := ParseComponentLevels("sql=loud"); err == nil {
+		t.Error("expected an unknown level to fail")
+	}
+}
@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compressor compresses rotated log files; see WithCompression
+type Compressor interface {
+	// Extension is appended to the name of a compressed file, e.g. ".gz"
+	Extension() string
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+}
+
+// GzipCompressor compresses with gzip at Level, or gzip.DefaultCompression if Level is zero
+type GzipCompressor struct {
+	Level int
+}
+
+func (GzipCompressor) Extension() string {
+	return ".gz"
+}
+
+func (c GzipCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	if c.Level == 0 {
+		return gzip.NewWriter(w), nil
+	}
+	return gzip.NewWriterLevel(w, c.Level)
+}
+
+// ZstdCompressor compresses with zstd at Level, a zstd level from 1 to 22, or the default
+// level if Level is zero
+type ZstdCompressor struct {
+	Level int
+}
+
+func (ZstdCompressor) Extension() string {
+	return ".zst"
+}
+
+func (c ZstdCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	if c.Level == 0 {
+		return zstd.NewWriter(w)
+	}
+	return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(c.Level)))
+}
+
+// compressors are the compressors a compaction or retention spec can name
+var compressors = map[string]Compressor{"gzip": GzipCompressor{}, "zstd": ZstdCompressor{}}
+
+// compressorFor returns the compressor of a file compressed with gzip or zstd, by its name
+func compressorFor(name string) Compressor {
+	for _, c := range compressors {
+		if strings.HasSuffix(name, c.Extension()) {
+			return c
+		}
+	}
+	return nil
+}
+
+// decompress returns a reader of the contents of the file name read from r, decompressed if
+// the name has the extension of a GzipCompressor or ZstdCompressor, and the name without it
+func decompress(name string, r io.Reader) (io.ReadCloser, string, error) {
+	switch {
+	case strings.HasSuffix(name, GzipCompressor{}.Extension()):
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, "", err
+		}
+		return gz, strings.TrimSuffix(name, GzipCompressor{}.Extension()), nil
+	case strings.HasSuffix(name, ZstdCompressor{}.Extension()):
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, "", err
+		}
+		return zr.IOReadCloser(), strings.TrimSuffix(name, ZstdCompressor{}.Extension()), nil
+	}
+	return io.NopCloser(r), name, nil
+}
+
+// compressFile compresses a rotated file, replacing it with the compressed file, and
+// returns the new name
+func (l *Log) compressFile(name string) (string, error) {
+	return compressFile(l.getFS(), name, l.compressor, l.fileMode)
+}
+
+// compressFile replaces the file with one compressed by c, created with mode or the default
+func compressFile(fs FS, name string, c Compressor, mode os.FileMode) (string, error) {
+	src, err := fs.OpenFile(name, os.O_RDONLY, 0)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	compressed := name + c.Extension()
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+	dst, err := fs.OpenFile(compressed, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+	if err != nil {
+		return "", err
+	}
+	if err = compressTo(dst, src, c); err != nil {
+		fs.Remove(compressed)
+		return "", err
+	}
+	return compressed, fs.Remove(name)
+}
+
+func compressTo(dst File, src io.Reader, c Compressor) error {
+	w, err := c.NewWriter(dst)
+	if err != nil {
+		dst.Close()
+		return err
+	}
+	_, err = io.Copy(w, src)
+	if cErr := w.Close(); err == nil {
+		err = cErr
+	}
+	if cErr := dst.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestRotateGzip(t *testing.T) {
+	fs := NewMemFS()
+	cl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithCompression(GzipCompressor{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl.Info("compressed")
+	rotated, err := cl.Rotate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(rotated, ".gz") {
+		t.Fatalf("expected a .gz file, got %s", rotated)
+	}
+	if _, err = fs.Stat(strings.TrimSuffix(rotated, ".gz")); err == nil {
+		t.Error("expected the uncompressed file to be removed")
+	}
+	f, _ := fs.OpenFile(rotated, os.O_RDONLY, 0)
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(r)
+	if !strings.Contains(string(b), "compressed") {
+		t.Errorf("unexpected decompressed contents %q", string(b))
+	}
+}
+
+func TestRotateZstd(t *testing.T) {
+	fs := NewMemFS()
+	cl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithCompression(ZstdCompressor{Level: 3}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl.Info("compressed")
+	rotated, err := cl.Rotate()
+	if err != nil || !strings.HasSuffix(rotated, ".zst") {
+		t.Fatalf("expected a .zst file, got %s and %v", rotated, err)
+	}
+	f, _ := fs.OpenFile(rotated, os.O_RDONLY, 0)
+	r, err := zstd.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	if err != nil || !strings.Contains(string(b), "compressed") {
+		t.Errorf("unexpected decompressed contents %q and %v", string(b), err)
+	}
+	// compaction reads the zstd file and keeps it compressed with zstd
+	result, err := cl.Compact(Compaction{Dedup: true})
+	if err != nil || len(result.Files) != 1 || result.Files[0] != rotated {
+		t.Errorf("expected the zstd file to be compacted in place, got %+v and %v", result, err)
+	}
+}
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// consoleMethod returns the browser console method for entries at level
+func consoleMethod(level string) string {
+	severity, ok := Severity(level)
+	switch {
+	case !ok:
+		return "info"
+	case severity <= syslogSeverities[ERROR]:
+		return "error"
+	case severity == syslogSeverities[WARNING]:
+		return "warn"
+	case severity == syslogSeverities[DEBUG]:
+		return "debug"
+	}
+	return "info"
+}
+
+type splitSink struct {
+	mu          sync.Mutex
+	out, errOut io.Writer
+}
+
+// NewSplitConsoleSink returns a sink writing entries at WARNING and more severe to stderr and
+// everything else, including custom levels, to stdout, for shells and container runtimes that
+// treat the two streams differently
+func NewSplitConsoleSink() Sink {
+	return NewSplitSink(os.Stdout, os.Stderr)
+}
+
+// NewSplitSink returns a sink writing each entry as a line to errOut if it is at WARNING or
+// more severe, and to out otherwise
+func NewSplitSink(out, errOut io.Writer) Sink {
+	return &splitSink{out: out, errOut: errOut}
+}
+
+func (s *splitSink) WriteEntry(e Entry) error {
+	w := s.out
+	if severity, ok := Severity(e.Level); ok && severity <= syslogSeverities[WARNING] {
+		w = s.errOut
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := io.WriteString(w, e.String()+"\n")
+	return err
+}
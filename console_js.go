@@ -0,0 +1,23 @@
+//go:build js && wasm
+
+package logging
+
+import "syscall/js"
+
+type consoleSink struct {
+	console js.Value
+}
+
+// NewConsoleSink returns a sink writing entries to the browser console, with console.error
+// for ERROR and more severe levels, console.warn for WARNING, console.debug for DEBUG and
+// console.info otherwise. Use it with an empty path, so nothing is written to the filesystem:
+//
+//	l, err := NewLog("", "WEB", LEVEL_INFO, LEVEL_NONE, WithSink(NewConsoleSink()))
+func NewConsoleSink() Sink {
+	return consoleSink{console: js.Global().Get("console")}
+}
+
+func (s consoleSink) WriteEntry(e Entry) error {
+	s.console.Call(consoleMethod(e.Level), e.String())
+	return nil
+}
@@ -0,0 +1,31 @@
+//go:build js && wasm
+
+package logging
+
+import (
+	"strings"
+	"syscall/js"
+	"testing"
+)
+
+func TestConsoleSink(t *testing.T) {
+	var logged []string
+	console := js.Global().Get("Object").New()
+	for _, method := range []string{"error", "warn", "info", "debug"} {
+		method := method
+		fn := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			logged = append(logged, method+": "+args[0].String())
+			return nil
+		})
+		defer fn.Release()
+		console.Set(method, fn)
+	}
+	cl, err := NewLog("", "WEB", LEVEL_INFO, LEVEL_NONE, WithSink(consoleSink{console: console}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl.Warning("slow response")
+	if len(logged) != 2 || !strings.HasPrefix(logged[1], "warn: ") || !strings.Contains(logged[1], "slow response") {
+		t.Errorf("expected the warning on console.warn, got %v", logged)
+	}
+}
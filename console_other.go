@@ -0,0 +1,11 @@
+//go:build !js || !wasm
+
+package logging
+
+import "os"
+
+// NewConsoleSink returns a sink writing entries to stderr. In browsers, built for js/wasm,
+// it writes to the browser console instead
+func NewConsoleSink() Sink {
+	return NewWriterSink(os.Stderr)
+}
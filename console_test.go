@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConsoleMethod(t *testing.T) {
+	cases := map[string]string{
+		CRITICAL: "error",
+		ERROR:    "error",
+		WARNING:  "warn",
+		NOTICE:   "info",
+		SUCCESS:  "info",
+		DEBUG:    "debug",
+		"AUDIT":  "info",
+	}
+	for level, expected := range cases {
+		if method := consoleMethod(level); method != expected {
+			t.Errorf("expected %s entries to use console.%s, got console.%s", level, expected, method)
+		}
+	}
+}
+
+func TestSplitSink(t *testing.T) {
+	var out, errOut strings.Builder
+	c := NewCapture(WithSink(NewSplitSink(&out, &errOut)))
+	out.Reset()
+	c.Info("started")
+	c.Warning("slow")
+	c.Critical("down")
+	c.Write("checked", "AUDIT")
+	if strings.Count(out.String(), "\n") != 2 || !strings.Contains(out.String(), "started") || !strings.Contains(out.String(), "checked") {
+		t.Errorf("expected info and custom levels on stdout, got %q", out.String())
+	}
+	if strings.Count(errOut.String(), "\n") != 2 || !strings.Contains(errOut.String(), "slow") || !strings.Contains(errOut.String(), "down") {
+		t.Errorf("expected warnings and worse on stderr, got %q", errOut.String())
+	}
+}
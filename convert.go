@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// Convert rewrites the records of a log read from src in another format, such as JSONEncoder
+// or LogfmtEncoder, writing one record per line to dst. Timestamps and multi-line messages
+// are preserved; fields written in the text format stay part of the message
+func Convert(dst io.Writer, src io.Reader, enc Encoder) (converted int, err error) {
+	return ConvertFrom(dst, src, "", enc)
+}
+
+// ConvertFrom is Convert for a log whose records are all in the given format, "text", "json"
+// or "docker", failing with ErrMalformedRecord on a record in another one. An empty format
+// accepts any
+func ConvertFrom(dst io.Writer, src io.Reader, from string, enc Encoder) (converted int, err error) {
+	w := bufio.NewWriter(dst)
+	buf := make([]byte, 0, 256)
+	converted, err = convertRecords(src, from, func(e Entry) error {
+		buf = append(enc.AppendEntry(buf[:0], e), '\n')
+		_, err := w.Write(buf)
+		return err
+	})
+	if err != nil {
+		return converted, err
+	}
+	return converted, w.Flush()
+}
+
+// ConvertCSV rewrites the records of a log read from src in the given format as CSV, as
+// ExportEntriesCSV does, without holding the whole log in memory
+func ConvertCSV(dst io.Writer, src io.Reader, from string) (converted int, err error) {
+	cw := csv.NewWriter(dst)
+	if err = cw.Write(csvHeader); err != nil {
+		return 0, err
+	}
+	converted, err = convertRecords(src, from, func(e Entry) error {
+		row, err := csvRow(e)
+		if err != nil {
+			return err
+		}
+		return cw.Write(row)
+	})
+	if err != nil {
+		return converted, err
+	}
+	cw.Flush()
+	return converted, cw.Error()
+}
+
+// convertRecords parses each record read from src, checking it is in the format from, and
+// passes the entry to fn
+func convertRecords(src io.Reader, from string, fn func(e Entry) error) (converted int, err error) {
+	if from != "" && from != "text" && from != "json" && from != "docker" {
+		return 0, fmt.Errorf("unknown format '%s'", from)
+	}
+	records := newRecordScanner(src)
+	for {
+		record, err := records.next()
+		if err == io.EOF {
+			return converted, nil
+		}
+		if err != nil {
+			return converted, err
+		}
+		if from != "" && recordFormat(record) != from {
+			return converted, ErrMalformedRecord
+		}
+		e, err := ParseEntry(record)
+		if err != nil {
+			return converted, err
+		}
+		if err = fn(e); err != nil {
+			return converted, err
+		}
+		converted++
+	}
+}
+
+// recordFormat returns the format of a record: "json", "docker" or "text"
+func recordFormat(record string) string {
+	switch {
+	case jsonForm.MatchString(record):
+		return "json"
+	case dockerForm.MatchString(record):
+		return "docker"
+	default:
+		return "text"
+	}
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	src := "[2023-03-01T10:00:00.125Z] [api.INFO] started\n" +
+		"[2023-03-01T10:00:02Z] [api.ERROR] upstream failed\n\tmain.go:12 seq=4\n"
+	var out bytes.Buffer
+	n, err := Convert(&out, strings.NewReader(src), JSONEncoder{})
+	if err != nil || n != 2 {
+		t.Fatalf("expected two records to be converted, got %d (%v)", n, err)
+	}
+	expected := `{"time":"2023-03-01T10:00:00.125Z","schema_version":1,"env":"api","level":"INFO","message":"started"}` + "\n" +
+		`{"time":"2023-03-01T10:00:02Z","schema_version":1,"env":"api","level":"ERROR","message":"upstream failed\n\tmain.go:12","seq":4}` + "\n"
+	if out.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, out.String())
+	}
+	out.Reset()
+	if _, err := Convert(&out, strings.NewReader(src), LogfmtEncoder{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(out.String(), "time=2023-03-01T10:00:02Z env=api level=ERROR msg=\"upstream failed\\n\\tmain.go:12\" seq=4\n") {
+		t.Errorf("expected logfmt with the multi-line message quoted, got %s", out.String())
+	}
+	// logfmt is read back, so the records convert back to the original text
+	var back bytes.Buffer
+	if n, err := Convert(&back, &out, TextEncoder{}); n != 2 || err != nil || back.String() != src {
+		t.Errorf("expected the logfmt records to convert back, got %d (%v)\n%s", n, err, back.String())
+	}
+}
+
+func TestConvertFrom(t *testing.T) {
+	text := "[2024-01-02T00:00:00Z] [TEST.INFO] started\n[2024-01-02T00:00:01Z] [TEST.ERROR] =cmd()\n"
+	var out strings.Builder
+	n, err := ConvertCSV(&out, strings.NewReader(text), "text")
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 records converted, got %d and %v", n, err)
+	}
+	expected := "timestamp,env,level,message,attrs\n2024-01-02T00:00:00Z,TEST,INFO,started,\n2024-01-02T00:00:01Z,TEST,ERROR,'=cmd(),\n"
+	if out.String() != expected {
+		t.Errorf("unexpected CSV %q", out.String())
+	}
+	if _, err = ConvertFrom(io.Discard, strings.NewReader(text), "json", JSONEncoder{}); err != ErrMalformedRecord {
+		t.Errorf("expected text records to be refused as JSON, got %v", err)
+	}
+	if _, err = ConvertFrom(io.Discard, strings.NewReader(text), "yaml", JSONEncoder{}); err == nil {
+		t.Error("expected an unknown input format to be refused")
+	}
+}
@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"bufio"
+	"io"
+)
+
+// maxCopyLine is the longest line Copy logs as one entry; longer lines are split
+const maxCopyLine = 64 * 1024
+
+// Copy logs each line read from r at the given level until r is exhausted, for piping
+// command output or uploaded text into the log. A final line without a newline is logged,
+// and lines longer than 64KiB are split over several entries so memory stays bounded. It
+// returns the number of entries written and the first read or write error
+func (l *Log) Copy(level string, r io.Reader) (n int64, err error) {
+	br := bufio.NewReaderSize(r, maxCopyLine)
+	for {
+		line, _, rErr := br.ReadLine()
+		if len(line) > 0 {
+			if _, err = l.Write(string(line), level); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if rErr == io.EOF {
+			return n, nil
+		}
+		if rErr != nil {
+			return n, rErr
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCopy(t *testing.T) {
+	c := NewCapture()
+	long := strings.Repeat("x", maxCopyLine+10)
+	n, err := c.Copy(WARNING, strings.NewReader("first\r\n\nsecond\n"+long+"\npartial"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := c.Entries()
+	if n != 5 || len(entries) != 5 {
+		t.Fatalf("expected 5 entries, got %d written and %d captured", n, len(entries))
+	}
+	messages := []string{"first", "second", long[:maxCopyLine], long[maxCopyLine:], "partial"}
+	for i, e := range entries {
+		if e.Level != WARNING || e.Message != messages[i] {
+			t.Errorf("expected entry %d to be a warning of %d bytes, got %s with %d bytes", i, len(messages[i]), e.Level, len(e.Message))
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCRLF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crlf.log")
+	for i := 0; i < 2; i++ {
+		cl, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithCRLF(), WithHashChain())
+		if err != nil {
+			t.Fatal(err)
+		}
+		cl.Info("first\nsecond line")
+		cl.Close()
+	}
+	b, _ := os.ReadFile(path)
+	if bytes.Count(b, crlf) != 4 || !bytes.HasSuffix(b, crlf) {
+		t.Errorf("expected 4 records ending in CRLF, got %q", b)
+	}
+	if err := Verify(path); err != nil {
+		t.Errorf("expected the chain to verify across CRLF records, got %v", err)
+	}
+	cl, _ := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithCRLF())
+	defer cl.Close()
+	lines, _ := cl.GetLog(2)
+	if len(lines) != 2 || strings.ContainsRune(strings.Join(lines, ""), '\r') {
+		t.Errorf("expected records without carriage returns, got %q", lines)
+	}
+}
+
+func TestCopyTruncate(t *testing.T) {
+	fs := NewMemFS()
+	cl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl.Info("before rotation")
+	cl.writer.close()
+	if err = cl.copyTruncate("/logs/app.log.1"); err != nil {
+		t.Fatal(err)
+	}
+	rotated, _ := cl.readFile("/logs/app.log.1")
+	current, _ := cl.readFile("/logs/app.log")
+	if !bytes.Contains(rotated, []byte("before rotation")) || len(current) != 0 {
+		t.Errorf("expected the records to be copied and the file truncated, got %q and %q", rotated, current)
+	}
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+var csvHeader = []string{"timestamp", "env", "level", "message", "attrs"}
+
+// ExportCSV writes records, such as those returned by GetLog, as CSV; see ExportEntriesCSV
+func ExportCSV(w io.Writer, records []string) error {
+	entries := make([]Entry, 0, len(records))
+	for _, record := range records {
+		e, err := ParseEntry(record)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, e)
+	}
+	return ExportEntriesCSV(w, entries)
+}
+
+// ExportEntriesCSV writes entries as CSV with timestamp, env, level and message columns and an
+// attrs column holding the fields and attributes as a JSON object. Cells that a spreadsheet
+// would take for a formula are prefixed with a single quote
+func ExportEntriesCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		row, err := csvRow(e)
+		if err != nil {
+			return err
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvRow returns the cells of an entry in the order of csvHeader
+func csvRow(e Entry) ([]string, error) {
+	attrs, err := csvAttrs(e)
+	if err != nil {
+		return nil, err
+	}
+	row := []string{string(appendTime(nil, e.Time, e.precision)), e.Env, e.Level, e.Message, attrs}
+	for i, cell := range row {
+		row[i] = csvSafe(cell)
+	}
+	return row, nil
+}
+
+// csvAttrs returns the fields and attributes of an entry as a JSON object, or an empty string
+// if it has none
+func csvAttrs(e Entry) (string, error) {
+	if len(e.Fields) == 0 && len(e.Attrs) == 0 {
+		return "", nil
+	}
+	attrs := make(map[string]interface{}, len(e.Fields)+len(e.Attrs))
+	for k, v := range e.Fields {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		attrs[k] = v
+	}
+	for _, a := range e.Attrs {
+		attrs[a.Key] = a.jsonValue()
+	}
+	b, err := json.Marshal(attrs)
+	return string(b), err
+}
+
+// csvSafe prefixes cells starting with a formula character with a single quote, so that
+// opening an export in a spreadsheet does not evaluate logged input
+func csvSafe(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
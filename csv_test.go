@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestExportCSV(t *testing.T) {
+	records := []string{
+		"[2023-03-01T10:00:00Z] [api.INFO] started",
+		"[2023-03-01T10:00:02.5Z] [api.ERROR] failed, \"badly\"\n\tmain.go:12",
+	}
+	var out bytes.Buffer
+	if err := ExportCSV(&out, records); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{
+		{"timestamp", "env", "level", "message", "attrs"},
+		{"2023-03-01T10:00:00Z", "api", "INFO", "started", ""},
+		{"2023-03-01T10:00:02.5Z", "api", "ERROR", "failed, \"badly\"\n\tmain.go:12", ""},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("expected %d rows, got %v", len(expected), rows)
+	}
+	for i, row := range expected {
+		for j, cell := range row {
+			if rows[i][j] != cell {
+				t.Errorf("expected row %d column %d to be %q, got %q", i, j, cell, rows[i][j])
+			}
+		}
+	}
+	if err := ExportCSV(&out, []string{"garbage"}); err != ErrMalformedRecord {
+		t.Errorf("expected a malformed record to fail, got %v", err)
+	}
+}
+
+func TestExportEntriesCSV(t *testing.T) {
+	entries := []Entry{{
+		Env:     "api",
+		Level:   INFO,
+		Message: "=HYPERLINK(\"http://example.com\")",
+		Fields:  Fields{"user": "@alice"},
+		Attrs:   []Attr{Int("n", 2)},
+	}}
+	var out bytes.Buffer
+	if err := ExportEntriesCSV(&out, entries); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows[1][3] != "'=HYPERLINK(\"http://example.com\")" {
+		t.Errorf("expected the formula to be escaped, got %q", rows[1][3])
+	}
+	if rows[1][4] != `{"n":2,"user":"@alice"}` {
+		t.Errorf("expected the fields and attributes as JSON, got %q", rows[1][4])
+	}
+}
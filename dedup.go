@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type dedup struct {
+	mu             sync.Mutex
+	window         time.Duration
+	level, message string
+	count          int
+	since          time.Time
+}
+
+// check reports whether the entry should be written. When a run of repeated entries ends,
+// or the window has passed, an entry summarising the repeats is returned
+func (d *dedup) check(level, message string, now time.Time) (bool, *Entry) {
+	if d == nil {
+		return true, nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if level == d.level && message == d.message {
+		if d.count == 0 {
+			d.since = now
+		}
+		d.count++
+		if now.Sub(d.since) < d.window {
+			return false, nil
+		}
+		return false, d.repeated()
+	}
+	repeated := d.repeated()
+	d.level, d.message = level, message
+	return true, repeated
+}
+
+func (d *dedup) repeated() *Entry {
+	if d.count == 0 {
+		return nil
+	}
+	e := &Entry{
+		Level:   d.level,
+		Message: fmt.Sprintf("message repeated %d times: %s", d.count, d.message),
+	}
+	d.count = 0
+	return e
+}
@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDedup(t *testing.T) {
+	dl, err := NewLog(filepath.Join(t.TempDir(), "dedup.log"), "TEST", LEVEL_INFO, LEVEL_NONE, WithRingBuffer(10), WithDedup(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		dl.Error("retrying connection")
+	}
+	dl.Info("connected")
+	entries := dl.RecentEntries()
+	expected := []string{"initialising log", "retrying connection", "message repeated 3 times: retrying connection", "connected"}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, e := range entries {
+		if e.Message != expected[i] {
+			t.Errorf("expected entry %d to be '%s', got '%s'", i, expected[i], e.Message)
+		}
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	d := &dedup{window: time.Second}
+	now := time.Now()
+	d.check(INFO, "tick", now)
+	if ok, _ := d.check(INFO, "tick", now); ok {
+		t.Error("expected repeated entry to be suppressed")
+	}
+	_, repeated := d.check(INFO, "tick", now.Add(2*time.Second))
+	if repeated == nil || repeated.Message != "message repeated 2 times: tick" {
+		t.Errorf("expected a repeat summary once the window passed, got %v", repeated)
+	}
+}
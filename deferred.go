@@ -0,0 +1,122 @@
+package logging
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultDeferredEntries is the number of entries a deferred log holds when no limit is given
+const defaultDeferredEntries = 1000
+
+// deferredBuffer holds the entries of a deferred log until they are flushed or discarded
+type deferredBuffer struct {
+	mu      sync.Mutex
+	entries []Entry // a ring of up to max entries, the oldest at start once it is full
+	start   int
+	max     int
+}
+
+// add holds an entry, replacing the oldest once max are held
+func (d *deferredBuffer) add(e Entry) {
+	if len(d.entries) < d.max {
+		d.entries = append(d.entries, e)
+		return
+	}
+	d.entries[d.start] = e
+	d.start = (d.start + 1) % d.max
+}
+
+// take returns the entries held, oldest first, and empties the buffer
+func (d *deferredBuffer) take() []Entry {
+	n := len(d.entries)
+	entries := append(d.entries[d.start:n:n], d.entries[:d.start]...)
+	d.entries, d.start = nil, 0
+	return entries
+}
+
+// Deferred returns a child log for one request or operation that holds its INFO, SUCCESS and
+// DEBUG entries in memory instead of writing them, whatever the log level. They are written
+// in full, with their original times, before the first ERROR or more severe entry written
+// through it, or when FlushDeferred is called; otherwise DiscardDeferred drops them once the
+// operation succeeds. This gives detailed traces of failures without the volume of always
+// logging at DEBUG. Other entries are written as usual. Only the last max entries (default
+// 1000) are held. Child logs share the buffer
+func (l *Log) Deferred(max int) *Log {
+	if max <= 0 {
+		max = defaultDeferredEntries
+	}
+	child := *l
+	child.deferred = &deferredBuffer{max: max}
+	return &child
+}
+
+// FlushDeferred writes the entries held by a deferred log, returning the first error; see
+// Deferred. They are sampled, deduplicated and numbered as they are written, like any other
+// entry. The log goes on holding entries written after it
+func (l *Log) FlushDeferred() error {
+	d := l.deferred
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	entries := d.take()
+	d.mu.Unlock()
+	if len(entries) == 0 {
+		return nil
+	}
+	// written whatever the log level, as they were held because of it
+	fl := *l
+	fl.deferred = nil
+	fl.levels = &levels{level: LEVEL_INFO, components: make(map[string]int)}
+	if l.standardLevels {
+		fl.levels.level = LEVEL_DEBUG
+	}
+	var err error
+	for _, e := range entries {
+		if !fl.admit(e.Level, e.Message) {
+			continue
+		}
+		if _, wErr := fl.writeEntry(e); wErr != nil && err == nil {
+			err = wErr
+		}
+	}
+	return err
+}
+
+// DiscardDeferred drops the entries held by a deferred log; see Deferred
+func (l *Log) DiscardDeferred() {
+	if d := l.deferred; d != nil {
+		d.mu.Lock()
+		d.entries, d.start = nil, 0
+		d.mu.Unlock()
+	}
+}
+
+// deferEntry holds an entry of a deferred log below NOTICE, stamped with t or the current
+// time, returning true, and flushes the entries held before an ERROR or more severe one.
+// The entry is built now, so that it keeps the log's fields and caller, but is only sampled,
+// deduplicated and numbered once it is flushed
+func (l *Log) deferEntry(t time.Time, message, level string) bool {
+	severity, ok := Severity(level)
+	if l.deferred == nil || !ok {
+		return false
+	}
+	if severity <= syslogSeverities[ERROR] {
+		if err := l.FlushDeferred(); err != nil {
+			l.handleError(err)
+		}
+		return false
+	}
+	if severity <= syslogSeverities[NOTICE] {
+		return false
+	}
+	e := l.newEntry(level, message)
+	if !t.IsZero() {
+		e.Time = t.UTC()
+	}
+	d := l.deferred
+	d.mu.Lock()
+	d.add(e)
+	d.mu.Unlock()
+	return true
+}
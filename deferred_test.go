@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeferred(t *testing.T) {
+	start := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	ring := NewRingBuffer(20)
+	dl, err := NewLog("", "TEST", LEVEL_WARNING, LEVEL_NONE, WithSink(ring), WithNow(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := func() string {
+		var messages []string
+		for _, e := range ring.Entries() {
+			messages = append(messages, e.Message)
+		}
+		return strings.Join(messages, ",")
+	}
+
+	request := dl.Deferred(0)
+	request.Debug("loading user")
+	now = now.Add(time.Second)
+	request.With(String("user", "42")).Info("charging card")
+	request.Warning("card expires soon")
+	if got := messages(); got != "card expires soon" {
+		t.Fatalf("expected DEBUG and INFO entries to be held, got %s", got)
+	}
+	now = now.Add(time.Second)
+	request.Error("payment failed")
+	if got := messages(); got != "card expires soon,loading user,charging card,payment failed" {
+		t.Fatalf("expected the entries held to be written before the error, got %s", got)
+	}
+	if e := ring.Entries()[2]; !e.Time.Equal(start.Add(time.Second)) || field(e, "user") != "42" {
+		t.Errorf("expected the original time and attributes to be kept, got %s", e)
+	}
+
+	ring = NewRingBuffer(20)
+	if dl, err = NewLog("", "TEST", LEVEL_WARNING, LEVEL_NONE, WithSink(ring)); err != nil {
+		t.Fatal(err)
+	}
+	request = dl.Deferred(2)
+	request.Info("one")
+	request.Info("two")
+	request.Info("three")
+	if err = request.FlushDeferred(); err != nil || messages() != "two,three" {
+		t.Errorf("expected the last 2 entries to be flushed, got %s and %v", messages(), err)
+	}
+	request.Info("succeeded")
+	request.DiscardDeferred()
+	if err = request.FlushDeferred(); err != nil || messages() != "two,three" {
+		t.Errorf("expected the entries of a successful request to be discarded, got %s", messages())
+	}
+}
+
+func TestDeferredWritePath(t *testing.T) {
+	ring := NewRingBuffer(20)
+	dl, err := NewLog("", "TEST", LEVEL_WARNING, LEVEL_NONE, WithSink(ring), WithSampling(DEBUG, 2), WithSequence())
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := dl.Deferred(3)
+	for _, m := range []string{"one", "two", "three", "four", "five"} {
+		request.Debug(m)
+	}
+	if err = request.FlushDeferred(); err != nil {
+		t.Fatal(err)
+	}
+	// the last 3 are held; flushed like any other write, every second DEBUG entry is sampled
+	var messages []string
+	var seqs []uint64
+	for _, e := range ring.Entries() {
+		if e.Level == DEBUG {
+			messages, seqs = append(messages, e.Message), append(seqs, e.Seq)
+		}
+	}
+	if strings.Join(messages, ",") != "three,five" || seqs[0] == 0 || seqs[1] <= seqs[0] {
+		t.Errorf("expected the held entries to be sampled and numbered when flushed, got %v %v", messages, seqs)
+	}
+}
@@ -0,0 +1,48 @@
+package logging
+
+import "fmt"
+
+// discontinuity returns the message of a marker entry if the log file was truncated, replaced
+// or removed by something other than the log since it was last written, closing a replaced
+// file so it is reopened. It is called with the write lock held
+func (l *Log) discontinuity() string {
+	if !l.tamperMarks || l.writer == nil || l.writer.file == nil {
+		return ""
+	}
+	info, err := l.getFS().Stat(l.path)
+	if err != nil {
+		l.writer.close()
+		return "log file was removed externally; earlier records are missing from this file"
+	}
+	if !sameFile(l.writer.info, info) {
+		l.writer.close()
+		return "log file was replaced externally; earlier records are missing from this file"
+	}
+	if info.Size() < l.writer.size {
+		message := fmt.Sprintf("log file was truncated externally from %d to %d bytes", l.writer.size, info.Size())
+		l.writer.size = info.Size()
+		return message
+	}
+	return ""
+}
+
+// writeDiscontinuityMarker writes a WARNING entry marking a discontinuity in the file, if
+// there is one. It is called with the write lock held
+func (l *Log) writeDiscontinuityMarker() {
+	message := l.discontinuity()
+	if message == "" {
+		return
+	}
+	e := l.newEntry(WARNING, message)
+	e.Attrs = append(e.Attrs, Bool("discontinuity", true))
+	msg, out, err := l.sealRecord(l.sign(l.encoder.AppendEntry(nil, e)))
+	if err == nil {
+		offset := l.indexOffset()
+		if err = l.appendWithPolicies(append(out, l.lineEnd()...)); err == nil {
+			l.indexRecords(offset, msg)
+		}
+	}
+	if err != nil {
+		l.handleError(err)
+	}
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDiscontinuityMarkers(t *testing.T) {
+	fs := NewMemFS()
+	dl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithDiscontinuityMarkers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dl.Info("one")
+	file, _ := fs.OpenFile("/logs/app.log", os.O_WRONLY, 0)
+	file.Truncate(0)
+	file.Close()
+	dl.Info("two")
+	content, _ := dl.readFile("/logs/app.log")
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "[TEST.WARNING] log file was truncated externally from") ||
+		!strings.HasSuffix(lines[0], "discontinuity=true") || !strings.HasSuffix(lines[1], "two") {
+		t.Fatalf("expected a truncation marker before the entry, got %q", content)
+	}
+	if _, err = dl.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	dl.Info("three")
+	fs.Rename("/logs/app.log", "/logs/moved.log")
+	dl.Info("four")
+	content, _ = dl.readFile("/logs/app.log")
+	lines = strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "log file was removed externally") || !strings.HasSuffix(lines[1], "four") {
+		t.Errorf("expected only the external removal to be marked, got %q", content)
+	}
+}
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"errors"
+	"sort"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// DiskFullPolicy determines what happens to an entry when the filesystem is full
+type DiskFullPolicy int
+
+const (
+	// DiskFullError returns the write error to the caller (the default)
+	DiskFullError DiskFullPolicy = iota
+	// DiskFullDrop drops the entry and counts it; see DiskFullDropped
+	DiskFullDrop
+	// DiskFullReclaim deletes the oldest backups of the log file (files named <path>.*, or
+	// as set by WithRotationName) one at a time until the entry can be written
+	DiskFullReclaim
+	// DiskFullBlock retries the write until space is available or the log is closed
+	DiskFullBlock
+)
+
+// diskFullRetryInterval is how often a blocked write is retried
+const diskFullRetryInterval = time.Second
+
+type diskFullPolicy struct {
+	policy  DiskFullPolicy
+	dropped atomic.Uint64
+}
+
+func isDiskFull(err error) bool {
+	return errors.Is(err, syscall.ENOSPC)
+}
+
+// DiskFullDropped returns the number of entries dropped because the filesystem was full
+func (l *Log) DiskFullDropped() uint64 {
+	if l.diskFull == nil {
+		return 0
+	}
+	return l.diskFull.dropped.Load()
+}
+
+func (l *Log) handleDiskFull(record []byte, err error) error {
+	if l.diskFull == nil {
+		return err
+	}
+	switch l.diskFull.policy {
+	case DiskFullDrop:
+		l.diskFull.dropped.Add(1)
+		return nil
+	case DiskFullReclaim:
+		return l.reclaimAndWrite(record, err)
+	case DiskFullBlock:
+		for isDiskFull(err) {
+			// waits with the write lock released, failing with ErrClosed once the log is closed
+			if err = l.stall(diskFullRetryInterval); err != nil {
+				return err
+			}
+			err = l.appendWhole(record)
+		}
+	}
+	return err
+}
+
+func (l *Log) reclaimAndWrite(record []byte, err error) error {
+	backups, gErr := l.backups()
+	if gErr != nil {
+		return err
+	}
+	for _, backup := range backups {
+		if rErr := l.getFS().Remove(backup); rErr != nil {
+			continue
+		}
+		if err = l.appendWhole(record); !isDiskFull(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// backups returns the log's rotated files, oldest first
+func (l *Log) backups() ([]string, error) {
+	glob, err := l.rotatedGlob()
+	if err != nil {
+		return nil, err
+	}
+	matches, err := l.getFS().Glob(glob)
+	if err != nil {
+		return nil, err
+	}
+	// the pattern may also match the log file itself and its quarantine file
+	backups := matches[:0]
+	for _, name := range matches {
+		if name != l.path && name != l.path+quarantineSuffix {
+			backups = append(backups, name)
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return l.modTime(backups[i]).Before(l.modTime(backups[j]))
+	})
+	return backups, nil
+}
+
+func (l *Log) modTime(name string) time.Time {
+	stat, err := l.getFS().Stat(name)
+	if err != nil {
+		return time.Time{}
+	}
+	return stat.ModTime()
+}
@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fullFS is a MemFS that reports the disk as full while it holds more than limit files
+type fullFS struct {
+	*MemFS
+	limit int
+}
+
+func (f *fullFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	file, err := f.MemFS.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &fullFile{File: file, fs: f}, nil
+}
+
+type fullFile struct {
+	File
+	fs *fullFS
+}
+
+func (f *fullFile) Write(b []byte) (int, error) {
+	if len(f.fs.Names()) > f.fs.limit {
+		return 0, &os.PathError{Op: "write", Path: "full", Err: syscall.ENOSPC}
+	}
+	return f.File.Write(b)
+}
+
+func TestDiskFullDrop(t *testing.T) {
+	fs := &fullFS{MemFS: NewMemFS(), limit: 1}
+	dl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithDiskFullPolicy(DiskFullDrop))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs.limit = 0
+	if _, err = dl.Info("dropped"); err != nil {
+		t.Errorf("expected the entry to be dropped without error, got %s", err.Error())
+	}
+	if dl.DiskFullDropped() != 1 {
+		t.Errorf("expected 1 dropped entry, got %d", dl.DiskFullDropped())
+	}
+}
+
+func TestDiskFullReclaim(t *testing.T) {
+	fs := &fullFS{MemFS: NewMemFS(), limit: 2}
+	for _, name := range []string{"/logs/app.log.1", "/logs/app.log.2"} {
+		f, _ := fs.MemFS.OpenFile(name, os.O_CREATE|os.O_WRONLY, 0644)
+		f.Write([]byte("old\n"))
+		time.Sleep(time.Millisecond)
+	}
+	dl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithDiskFullPolicy(DiskFullReclaim))
+	if err != nil {
+		t.Fatalf("expected the oldest backup to be reclaimed, got %s", err.Error())
+	}
+	names := fs.Names()
+	if len(names) != 2 || names[0] != "/logs/app.log" || names[1] != "/logs/app.log.2" {
+		t.Errorf("expected only the oldest backup to be removed, got %v", names)
+	}
+	dl.Info("still writing")
+}
+
+func TestDiskFullBlockClose(t *testing.T) {
+	fs := &fullFS{MemFS: NewMemFS(), limit: 1}
+	dl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithDiskFullPolicy(DiskFullBlock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs.limit = 0
+	done := make(chan error)
+	go func() {
+		_, err := dl.Info("blocked")
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	// Close is not held up by the blocked write, which then gives up
+	start := time.Now()
+	dl.Close()
+	if elapsed := time.Since(start); elapsed > diskFullRetryInterval/2 {
+		t.Errorf("expected Close not to wait for the blocked write, took %s", elapsed)
+	}
+	if err = <-done; err != ErrClosed {
+		t.Errorf("expected the blocked write to end with ErrClosed, got %v", err)
+	}
+}
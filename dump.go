@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"os"
+	"runtime"
+)
+
+// writeFatal writes a FATAL entry, followed by a crash dump when enabled, then closes the log
+// so that queued entries and sinks are flushed before the process exits
+func (l *Log) writeFatal(message string) {
+	l.Chain().write(message, "FATAL")
+	if l.crashDump {
+		l.writeCrashDump()
+	}
+	l.Close()
+}
+
+// writeCrashDump writes the memory statistics and a dump of all goroutines as a FATAL entry
+func (l *Log) writeCrashDump() {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	l.With(
+		Int64("heap_alloc", int64(mem.HeapAlloc)),
+		Int64("heap_objects", int64(mem.HeapObjects)),
+		Int64("sys", int64(mem.Sys)),
+		Int64("num_gc", int64(mem.NumGC)),
+		Int("goroutines", runtime.NumGoroutine()),
+	).Chain().write("crash dump\n"+string(goroutineDump()), "FATAL")
+}
+
+// goroutineDump returns the stacks of all goroutines, growing the buffer until they fit
+func goroutineDump() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// DumpOnSignal writes a dump of all goroutines to the log as a single ERROR entry whenever
+// the process receives one of the signals; with none, SIGQUIT where the platform has it. Handling SIGQUIT replaces the runtime's default dump-and-exit. The returned
+// function stops handling the signals
+func (l *Log) DumpOnSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = dumpSignals
+	}
+	return onSignal(sigs, func(sig os.Signal) {
+		l.Chain().Errorf("goroutine dump on %s\n%s", sig, goroutineDump())
+	})
+}
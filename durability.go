@@ -0,0 +1,20 @@
+package logging
+
+import "time"
+
+type syncPolicy struct {
+	interval time.Duration
+	last     time.Time
+}
+
+// due reports whether the file should be synced now. It is called with the log's write lock held
+func (p *syncPolicy) due(now time.Time) bool {
+	if p == nil {
+		return false
+	}
+	if p.interval > 0 && now.Sub(p.last) < p.interval {
+		return false
+	}
+	p.last = now
+	return true
+}
@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSyncPolicy(t *testing.T) {
+	var none *syncPolicy
+	if none.due(time.Now()) {
+		t.Error("expected no syncing without a policy")
+	}
+	every := &syncPolicy{}
+	now := time.Now()
+	if !every.due(now) || !every.due(now) {
+		t.Error("expected every write to be synced")
+	}
+	interval := &syncPolicy{interval: time.Second}
+	results := []bool{interval.due(now), interval.due(now.Add(500 * time.Millisecond)), interval.due(now.Add(time.Second))}
+	for i, expected := range []bool{true, false, true} {
+		if results[i] != expected {
+			t.Errorf("expected sync %d to be due=%t, got %t", i, expected, results[i])
+		}
+	}
+}
+
+func TestSyncEveryWrite(t *testing.T) {
+	sl := NewTestLog(t, WithSyncEveryWrite())
+	if _, err := sl.Info("durable"); err != nil {
+		t.Errorf("expected synced write to succeed, got %s", err.Error())
+	}
+}
@@ -0,0 +1,40 @@
+package logging
+
+import "strings"
+
+// Enabled reports whether an entry at the given level (case insensitive) would be written or reported,
+// so callers can skip building expensive messages
+func (l *Log) Enabled(level string) bool {
+	if l.muted {
+		return false
+	}
+	level = strings.ToUpper(level)
+	return l.shouldWrite(level) || l.shouldReport(level)
+}
+
+func (l *Log) ErrorEnabled() bool {
+	return l.Enabled(ERROR)
+}
+
+func (l *Log) WarningEnabled() bool {
+	return l.Enabled(WARNING)
+}
+
+func (l *Log) DebugEnabled() bool {
+	return l.Enabled(DEBUG)
+}
+
+func (l *Log) InfoEnabled() bool {
+	return l.Enabled(INFO)
+}
+
+// If returns the log when cond is true and a copy that writes nothing otherwise, so
+// l.If(verbose).Debugf(...) neither formats nor writes the message unless verbose is set
+func (l *Log) If(cond bool) *Log {
+	if cond || l.muted {
+		return l
+	}
+	child := *l
+	child.muted = true
+	return &child
+}
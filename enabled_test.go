@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestEnabled(t *testing.T) {
+	el, err := NewLog(filepath.Join(t.TempDir(), "enabled.log"), "TEST", LEVEL_WARNING, LEVEL_DEBUG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]bool{
+		ERROR:    true,
+		"debug":  true, // reported but not written
+		INFO:     false,
+		"CUSTOM": true,
+	}
+	for level, expected := range cases {
+		if el.Enabled(level) != expected {
+			t.Errorf("expected level %s to be enabled=%t", level, expected)
+		}
+	}
+	if !el.WarningEnabled() || el.InfoEnabled() {
+		t.Error("expected warning to be enabled and info to be disabled")
+	}
+}
+
+type countingStringer struct{ calls *int }
+
+func (s countingStringer) String() string {
+	*s.calls++
+	return "formatted"
+}
+
+func TestIf(t *testing.T) {
+	il, err := NewLog(filepath.Join(t.TempDir(), "if.log"), "TEST", LEVEL_DEBUG, LEVEL_NONE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	if result, _ := il.If(false).Debugf("value %s", countingStringer{&calls}); result != "" || calls != 0 {
+		t.Errorf("expected a false condition to skip formatting and writing, got '%s' after %d calls", result, calls)
+	}
+	if il.If(false).If(true).DebugEnabled() {
+		t.Error("expected a muted log to stay muted")
+	}
+	if result, _ := il.If(true).Debugf("value %s", countingStringer{&calls}); result == "" || calls != 1 {
+		t.Errorf("expected a true condition to write, got '%s' after %d calls", result, calls)
+	}
+}
@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPooledBuffer is the largest buffer returned to the pool, so one huge entry doesn't pin memory
+const maxPooledBuffer = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 256)
+		return &b
+	},
+}
+
+func getBuffer() *[]byte {
+	return bufferPool.Get().(*[]byte)
+}
+
+func putBuffer(b *[]byte) {
+	if cap(*b) > maxPooledBuffer {
+		return
+	}
+	*b = (*b)[:0]
+	bufferPool.Put(b)
+}
+
+// appendEntry appends the entry in the log file format to dst without intermediate allocations
+func appendEntry(dst []byte, e Entry) []byte {
+	dst = append(dst, '[')
+	dst = appendTime(dst, e.Time, e.precision)
+	dst = append(dst, "] ["...)
+	dst = append(dst, e.Env...)
+	dst = append(dst, '.')
+	dst = append(dst, e.Level...)
+	dst = append(dst, "] "...)
+	return appendMessage(dst, e)
+}
+
+// appendMessage appends the message of an entry followed by its fields, attributes and
+// sequence number, as in the default text format
+func appendMessage(dst []byte, e Entry) []byte {
+	dst = append(dst, e.Message...)
+	if len(e.Fields) > 0 {
+		dst = append(dst, e.Fields.String()...)
+	}
+	for _, a := range e.Attrs {
+		dst = append(dst, ' ')
+		dst = append(dst, a.Key...)
+		dst = append(dst, '=')
+		dst = a.appendValue(dst)
+	}
+	if e.Seq > 0 {
+		dst = append(dst, seqPrefix...)
+		dst = strconv.AppendUint(dst, e.Seq, 10)
+	}
+	return dst
+}
+
+// timeLayouts are the RFC3339 layouts with 0 to 9 fractional second digits
+var timeLayouts = func() [10]string {
+	var layouts [10]string
+	for digits := range layouts {
+		layouts[digits] = time.RFC3339
+		if digits > 0 {
+			layouts[digits] = "2006-01-02T15:04:05." + strings.Repeat("0", digits) + "Z07:00"
+		}
+	}
+	return layouts
+}()
+
+// appendTime appends t in RFC3339 format with the given number of fractional second digits,
+// formatting UTC times by hand
+func appendTime(dst []byte, t time.Time, digits int) []byte {
+	year, month, day := t.Date()
+	if t.Location() != time.UTC || year < 0 || year > 9999 {
+		return t.AppendFormat(dst, timeLayouts[digits])
+	}
+	hour, min, sec := t.Clock()
+	dst = appendDigits(dst, year, 4)
+	dst = append(dst, '-')
+	dst = appendDigits(dst, int(month), 2)
+	dst = append(dst, '-')
+	dst = appendDigits(dst, day, 2)
+	dst = append(dst, 'T')
+	dst = appendDigits(dst, hour, 2)
+	dst = append(dst, ':')
+	dst = appendDigits(dst, min, 2)
+	dst = append(dst, ':')
+	dst = appendDigits(dst, sec, 2)
+	if digits > 0 {
+		dst = append(dst, '.')
+		dst = appendDigits(dst, t.Nanosecond()/pow10[9-digits], digits)
+	}
+	return append(dst, 'Z')
+}
+
+var pow10 = [10]int{1, 10, 100, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9}
+
+// appendDigits appends a non-negative number zero-padded to width digits
+func appendDigits(dst []byte, n, width int) []byte {
+	var digits [9]byte
+	for i := width - 1; i >= 0; i-- {
+		digits[i] = byte('0' + n%10)
+		n /= 10
+	}
+	return append(dst, digits[:width]...)
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAppendTime(t *testing.T) {
+	times := []time.Time{
+		time.Date(2023, 7, 9, 4, 5, 6, 0, time.UTC),
+		time.Date(999, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(2023, 7, 9, 4, 5, 6, 0, time.FixedZone("SAST", 2*60*60)),
+	}
+	for _, tm := range times {
+		expected := tm.Format(time.RFC3339)
+		if result := string(appendTime(nil, tm, 0)); result != expected {
+			t.Errorf("expected '%s', got '%s'", expected, result)
+		}
+	}
+}
+
+func TestAppendTimePrecision(t *testing.T) {
+	utc := time.Date(2023, 7, 9, 4, 5, 6, 120450789, time.UTC)
+	expected := map[int]string{
+		3: "2023-07-09T04:05:06.120Z",
+		6: "2023-07-09T04:05:06.120450Z",
+		9: "2023-07-09T04:05:06.120450789Z",
+	}
+	for digits, stamp := range expected {
+		if result := string(appendTime(nil, utc, digits)); result != stamp {
+			t.Errorf("expected '%s', got '%s'", stamp, result)
+		}
+	}
+	zoned := utc.In(time.FixedZone("SAST", 2*60*60))
+	if result := string(appendTime(nil, zoned, 3)); result != "2023-07-09T06:05:06.120+02:00" {
+		t.Errorf("expected a zoned time with milliseconds, got '%s'", result)
+	}
+}
+
+func TestInfoAllocations(t *testing.T) {
+	al := NewTestLog(t)
+	al.sinks = nil // no t.Log mirroring
+	allocs := testing.AllocsPerRun(100, func() {
+		al.Info("allocation check")
+	})
+	// the returned string is the only allocation today; the bound leaves headroom for the
+	// runtime and toolchain, catching a write path that allocates per field or per byte.
+	// BenchmarkInfo reports the exact count
+	if allocs > 4 {
+		t.Errorf("expected at most 4 allocations per entry, got %.1f", allocs)
+	}
+}
+
+func BenchmarkInfo(b *testing.B) {
+	bl, err := NewLog(b.TempDir()+"/bench.log", "BENCH", LEVEL_INFO, LEVEL_NONE)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		bl.Info("benchmark message")
+	}
+}
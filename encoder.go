@@ -0,0 +1,258 @@
+package logging
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encoder formats entries. The file and the report path can use different encoders; see
+// WithEncoder and WithReportEncoder
+type Encoder interface {
+	AppendEntry(dst []byte, e Entry) []byte
+}
+
+// TextEncoder formats entries in the default "[time] [env.LEVEL] message key=value" format
+type TextEncoder struct{}
+
+func (TextEncoder) AppendEntry(dst []byte, e Entry) []byte {
+	return appendEntry(dst, e)
+}
+
+// JSONSchemaVersion is the version of the layout of JSONEncoder's records, written as their
+// "schema_version" member. It is raised whenever members are added, renamed or change meaning,
+// and ParseEntry keeps a decoder for every version so archived records stay readable
+const JSONSchemaVersion = 1
+
+// JSONEncoder formats each entry as a JSON object on a single line, with the fields and
+// attributes as members alongside "time", "schema_version", "env", "level" and "message".
+// Signatures and audit chain hashes are added as the members "sig" and "chain"
+type JSONEncoder struct{}
+
+func (JSONEncoder) AppendEntry(dst []byte, e Entry) []byte {
+	dst = append(dst, `{"time":"`...)
+	dst = appendTime(dst, e.Time, e.precision)
+	dst = append(dst, `","schema_version":`...)
+	dst = strconv.AppendInt(dst, JSONSchemaVersion, 10)
+	dst = append(dst, `,"env":`...)
+	dst = appendJSONString(dst, e.Env)
+	dst = append(dst, `,"level":`...)
+	dst = appendJSONString(dst, e.Level)
+	dst = append(dst, `,"message":`...)
+	dst = appendJSONString(dst, e.Message)
+	for _, k := range e.Fields.keys() {
+		dst = appendJSONMember(dst, k, e.Fields[k])
+	}
+	for _, a := range e.Attrs {
+		dst = appendJSONMember(dst, a.Key, a.jsonValue())
+	}
+	if e.Seq > 0 {
+		dst = append(dst, `,"seq":`...)
+		dst = strconv.AppendUint(dst, e.Seq, 10)
+	}
+	return append(dst, '}')
+}
+
+// jsonValue returns the attribute's value as it should be encoded in JSON
+func (a Attr) jsonValue() interface{} {
+	switch v := a.Value().(type) {
+	case time.Duration:
+		return v.String()
+	case error:
+		return v.Error()
+	default:
+		return v
+	}
+}
+
+func appendJSONMember(dst []byte, key string, v interface{}) []byte {
+	dst = append(dst, ',')
+	dst = appendJSONString(dst, key)
+	dst = append(dst, ':')
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return appendJSONString(dst, fieldValue(v))
+	}
+	return append(dst, b...)
+}
+
+func appendJSONString(dst []byte, s string) []byte {
+	b, _ := json.Marshal(s)
+	return append(dst, b...)
+}
+
+// DockerEncoder formats entries in the json-file format of Docker's logging driver, as
+// {"log":"<record>\n","stream":"stdout","time":"<time>"}, with the record in the default text
+// format. Entries at ERROR or more severe go to the "stderr" stream, the rest to "stdout", so
+// files can be read by tooling that parses container logs
+type DockerEncoder struct{}
+
+func (DockerEncoder) AppendEntry(dst []byte, e Entry) []byte {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	*buf = append(appendEntry((*buf)[:0], e), '\n')
+	dst = append(dst, `{"log":`...)
+	dst = appendJSONString(dst, string(*buf))
+	dst = append(dst, `,"stream":"`...)
+	dst = append(dst, dockerStream(e.Level)...)
+	dst = append(dst, `","time":"`...)
+	dst = e.Time.UTC().AppendFormat(dst, time.RFC3339Nano)
+	return append(dst, `"}`...)
+}
+
+// dockerStream returns the stream an entry at level is attributed to
+func dockerStream(level string) string {
+	if severity, ok := Severity(level); ok && severity <= syslogSeverities[ERROR] {
+		return "stderr"
+	}
+	return "stdout"
+}
+
+// ConsoleEncoder formats entries for a terminal as "15:04:05 LEVEL message key=value", with
+// the level coloured unless NoColor is set
+type ConsoleEncoder struct {
+	NoColor bool
+}
+
+// levelColors are the ANSI colours of the levels on the console
+var levelColors = map[string]string{
+	EMERGENCY: "\x1b[1;31m",
+	ALERT:     "\x1b[1;31m",
+	CRITICAL:  "\x1b[1;31m",
+	ERROR:     "\x1b[31m",
+	WARNING:   "\x1b[33m",
+	NOTICE:    "\x1b[36m",
+	SUCCESS:   "\x1b[32m",
+	INFO:      "\x1b[34m",
+	DEBUG:     "\x1b[90m",
+}
+
+func (c ConsoleEncoder) AppendEntry(dst []byte, e Entry) []byte {
+	dst = e.Time.Local().AppendFormat(dst, "15:04:05")
+	dst = append(dst, ' ')
+	color, ok := levelColors[e.Level]
+	if ok && !c.NoColor {
+		dst = append(dst, color...)
+	}
+	dst = append(dst, e.Level...)
+	if ok && !c.NoColor {
+		dst = append(dst, "\x1b[0m"...)
+	}
+	dst = append(dst, ' ')
+	dst = append(dst, e.Message...)
+	if len(e.Fields) > 0 {
+		dst = append(dst, e.Fields.String()...)
+	}
+	for _, a := range e.Attrs {
+		dst = append(dst, ' ')
+		dst = append(dst, a.Key...)
+		dst = append(dst, '=')
+		dst = a.appendValue(dst)
+	}
+	return dst
+}
+
+// LevelEncoder formats entries with the encoder set for their level, and the others with
+// Default, or TextEncoder if it is nil. For example, ERROR entries can be written as JSON
+// with every attribute, such as the caller of WithCaller, while DEBUG entries are written as
+// compact ConsoleEncoder lines. Use it with WithEncoder for the log file, or NewEncoderSink to
+// configure a sink
+type LevelEncoder struct {
+	Default Encoder
+	Levels  map[string]Encoder
+}
+
+func (c LevelEncoder) AppendEntry(dst []byte, e Entry) []byte {
+	if enc, ok := c.Levels[e.Level]; ok {
+		return enc.AppendEntry(dst, e)
+	}
+	if c.Default == nil {
+		return TextEncoder{}.AppendEntry(dst, e)
+	}
+	return c.Default.AppendEntry(dst, e)
+}
+
+// LogfmtEncoder formats entries as logfmt key=value pairs: time, env, level and msg, followed
+// by the fields and attributes. Values with spaces, quotes or newlines are quoted
+type LogfmtEncoder struct{}
+
+func (LogfmtEncoder) AppendEntry(dst []byte, e Entry) []byte {
+	dst = append(dst, "time="...)
+	dst = appendTime(dst, e.Time, e.precision)
+	dst = append(dst, " env="...)
+	dst = appendFieldString(dst, e.Env)
+	dst = append(dst, " level="...)
+	dst = appendFieldString(dst, e.Level)
+	dst = append(dst, " msg="...)
+	dst = appendFieldString(dst, e.Message)
+	if len(e.Fields) > 0 {
+		dst = append(dst, e.Fields.String()...)
+	}
+	for _, a := range e.Attrs {
+		dst = append(dst, ' ')
+		dst = append(dst, a.Key...)
+		dst = append(dst, '=')
+		dst = a.appendValue(dst)
+	}
+	if e.Seq > 0 {
+		dst = append(dst, seqPrefix...)
+		dst = strconv.AppendUint(dst, e.Seq, 10)
+	}
+	return dst
+}
+
+// logfmtForm starts a record written by LogfmtEncoder
+var logfmtForm = regexp.MustCompile(`^time=\d{4}-\d{2}-\d{2}T`)
+
+// parseLogfmtEntry parses a record written by LogfmtEncoder, reading the pairs other than
+// time, env, level, msg and seq into Fields as strings
+func parseLogfmtEntry(record string) (Entry, error) {
+	var e Entry
+	for record != "" {
+		record = strings.TrimLeft(record, " ")
+		eq := strings.IndexByte(record, '=')
+		if eq <= 0 || strings.IndexByte(record[:eq], ' ') >= 0 {
+			return Entry{}, ErrMalformedRecord
+		}
+		key, value := record[:eq], record[eq+1:]
+		if strings.HasPrefix(value, `"`) {
+			quoted, err := strconv.QuotedPrefix(value)
+			if err != nil {
+				return Entry{}, ErrMalformedRecord
+			}
+			record = value[len(quoted):]
+			value, _ = strconv.Unquote(quoted)
+		} else if end := strings.IndexByte(value, ' '); end >= 0 {
+			value, record = value[:end], value[end:]
+		} else {
+			record = ""
+		}
+		switch key {
+		case "time":
+			t, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return Entry{}, ErrMalformedRecord
+			}
+			e.Time, e.precision = t, timePrecision(value)
+		case "env":
+			e.Env = value
+		case "level":
+			e.Level = value
+		case "msg":
+			e.Message = value
+		case "seq":
+			e.Seq, _ = strconv.ParseUint(value, 10, 64)
+		default:
+			if e.Fields == nil {
+				e.Fields = Fields{}
+			}
+			e.Fields[key] = value
+		}
+	}
+	return e, nil
+}
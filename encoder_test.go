@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONEncoder(t *testing.T) {
+	e := Entry{
+		Time:    time.Date(2023, 3, 1, 10, 4, 5, 0, time.UTC),
+		Env:     "prod",
+		Level:   ERROR,
+		Message: "charge \"failed\"\nretrying",
+		Fields:  Fields{"order": 7},
+		Attrs:   []Attr{Duration("latency", 1500*time.Millisecond), Err(errors.New("declined")), Bool("retry", true)},
+		Seq:     3,
+	}
+	line := JSONEncoder{}.AppendEntry(nil, e)
+	if bytes.ContainsRune(line, '\n') {
+		t.Fatalf("expected a single line, got %s", line)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(line, &decoded); err != nil {
+		t.Fatalf("expected valid JSON, got %s: %v", line, err)
+	}
+	expected := map[string]interface{}{
+		"time":    "2023-03-01T10:04:05Z",
+		"env":     "prod",
+		"level":   ERROR,
+		"message": "charge \"failed\"\nretrying",
+		"order":   float64(7),
+		"latency": "1.5s",
+		"error":   "declined",
+		"retry":   true,
+		"seq":     float64(3),
+	}
+	for key, value := range expected {
+		if decoded[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, decoded[key])
+		}
+	}
+}
+
+func TestIndependentEncoders(t *testing.T) {
+	var console bytes.Buffer
+	jl, err := NewLog(t.TempDir()+"/json.log", "TEST", LEVEL_INFO, LEVEL_ERROR,
+		WithEncoder(JSONEncoder{}),
+		WithReporter(NewWriterReporter(&console)),
+		WithReportEncoder(ConsoleEncoder{NoColor: true}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jl.Info("first")
+	jl.Error("second\nline")
+	result, err := jl.GetLog(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 3 {
+		t.Fatalf("expected three JSON records, got %q", result)
+	}
+	for _, record := range result {
+		if !json.Valid([]byte(record)) {
+			t.Errorf("expected a JSON record, got %s", record)
+		}
+	}
+	if !bytes.HasSuffix(console.Bytes(), []byte(" ERROR second\nline\n")) {
+		t.Errorf("expected the console report in its own format, got %q", console.String())
+	}
+}
+
+func TestJSONEncoderSealed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "json.log")
+	key := []byte("key")
+	jl, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithEncoder(JSONEncoder{}), WithSigning(key), WithHashChain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	jl.Info("sealed")
+	if err = Verify(path); err != nil {
+		t.Errorf("expected the chain to verify, got %v", err)
+	}
+	b, _ := os.ReadFile(path)
+	for _, line := range bytes.Split(bytes.TrimSuffix(b, []byte("\n")), []byte("\n")) {
+		var record map[string]interface{}
+		if err = json.Unmarshal(line, &record); err != nil || record["sig"] == nil || record["chain"] == nil {
+			t.Fatalf("expected a JSON object with sig and chain members, got %s (%v)", line, err)
+		}
+		if err = VerifySignature(string(line), key); err != nil {
+			t.Errorf("expected the signature to verify, got %v", err)
+		}
+		if e, err := ParseEntry(string(line)); err != nil || len(e.Fields) != 0 {
+			t.Errorf("expected sig and chain not to be parsed as fields, got %v and %v", e.Fields, err)
+		}
+	}
+}
+
+func TestDockerEncoder(t *testing.T) {
+	e := Entry{
+		Time:    time.Date(2023, 3, 1, 10, 4, 5, 120000000, time.UTC),
+		Env:     "prod",
+		Level:   ERROR,
+		Message: "charge failed",
+		Fields:  Fields{"order": 7},
+	}
+	line := string(DockerEncoder{}.AppendEntry(nil, e))
+	expected := `{"log":"[2023-03-01T10:04:05Z] [prod.ERROR] charge failed order=7\n","stream":"stderr","time":"2023-03-01T10:04:05.12Z"}`
+	if line != expected {
+		t.Errorf("expected '%s', got '%s'", expected, line)
+	}
+	parsed, err := ParseEntry(line)
+	if err != nil || parsed.Level != ERROR || parsed.Message != "charge failed order=7" {
+		t.Errorf("expected the Docker record to parse, got %+v, %v", parsed, err)
+	}
+	e.Level = INFO
+	if line = string(DockerEncoder{}.AppendEntry(nil, e)); !strings.Contains(line, `"stream":"stdout"`) {
+		t.Errorf("expected info entries on stdout, got '%s'", line)
+	}
+}
@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+const encryptedPrefix = "enc:"
+
+type recordCipher struct {
+	mu   sync.Mutex
+	key  func() ([]byte, error)
+	aead cipher.AEAD
+}
+
+// KeyFromEnv returns a key function reading a base64 encoded key from the named environment variable
+func KeyFromEnv(name string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, fmt.Errorf("encryption key variable %s is not set", name)
+		}
+		return base64.StdEncoding.DecodeString(value)
+	}
+}
+
+func (c *recordCipher) getAEAD() (cipher.AEAD, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.aead != nil {
+		return c.aead, nil
+	}
+	key, err := c.key()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	c.aead, err = cipher.NewGCM(block)
+	return c.aead, err
+}
+
+// encrypt seals a record into a single base64 line
+func (c *recordCipher) encrypt(msg []byte) ([]byte, error) {
+	if c == nil {
+		return msg, nil
+	}
+	aead, err := c.getAEAD()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := aead.Seal(nonce, nonce, msg, nil)
+	return []byte(encryptedPrefix + base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+func (c *recordCipher) decrypt(line string) (string, error) {
+	if !strings.HasPrefix(line, encryptedPrefix) {
+		return "", fmt.Errorf("record is not encrypted")
+	}
+	aead, err := c.getAEAD()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(line, encryptedPrefix))
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("encrypted record is too short")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	return string(plain), err
+}
+
+// getEncryptedLog decrypts the last lines records of the file, newest first
+func (l *Log) getEncryptedLog(lines uint) ([]string, error) {
+	b, err := l.readFile(l.path)
+	if err != nil {
+		return []string{}, err
+	}
+	split := strings.Split(strings.TrimRight(string(b), "\n"), "\n")
+	result := make([]string, 0, lines)
+	for i := len(split) - 1; i >= 0 && uint(len(result)) < lines; i-- {
+		if split[i] == "" {
+			continue
+		}
+		record, err := l.cipher.decrypt(split[i])
+		if err != nil {
+			return result, err
+		}
+		result = append(result, record)
+	}
+	return result, nil
+}
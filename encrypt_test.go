@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryption(t *testing.T) {
+	t.Setenv("TEST_LOG_KEY", base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")))
+	path := filepath.Join(t.TempDir(), "encrypted.log")
+	el, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithEncryption(KeyFromEnv("TEST_LOG_KEY")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	el.Info("patient record 42\nwith notes")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "patient") {
+		t.Errorf("expected file content to be encrypted, got '%s'", string(b))
+	}
+	result, err := el.GetLog(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 decrypted records, got %d", len(result))
+	}
+	if !strings.HasSuffix(result[0], "patient record 42\nwith notes") {
+		t.Errorf("expected newest record to be decrypted, got '%s'", result[0])
+	}
+}
+
+func TestEncryptionMissingKey(t *testing.T) {
+	_, err := NewLog(filepath.Join(t.TempDir(), "nokey.log"), "TEST", LEVEL_INFO, LEVEL_NONE, WithEncryption(KeyFromEnv("TEST_LOG_KEY_UNSET")))
+	if err == nil {
+		t.Error("expected an error when the encryption key is not available")
+	}
+}
+
+func TestEncryptedHashChain(t *testing.T) {
+	t.Setenv("TEST_LOG_KEY", base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")))
+	path := filepath.Join(t.TempDir(), "encrypted.log")
+	for _, message := range []string{"before restart", "after restart"} {
+		el, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithEncryption(KeyFromEnv("TEST_LOG_KEY")), WithHashChain())
+		if err != nil {
+			t.Fatal(err)
+		}
+		el.Info(message)
+		if err = el.Verify(); err != nil {
+			t.Errorf("expected the chain to carry on across the restart, got %v", err)
+		}
+		el.Close()
+	}
+	var vErr *VerifyError
+	if err := Verify(path); !errors.As(err, &vErr) || vErr.Record != 1 || vErr.Reason != "is malformed" {
+		t.Errorf("expected an encrypted file not to verify without its key, got %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package logging
+
+import "time"
+
+// Entry is a single log record
+type Entry struct {
+	Time    time.Time
+	Env     string
+	Level   string
+	Message string
+	Fields  Fields
+	Attrs   []Attr
+	// Seq is the entry's sequence number when the log was created WithSequence, otherwise 0
+	Seq uint64
+	// precision is the number of fractional second digits written in the timestamp
+	precision int
+}
+
+// Field returns the value of a field or attribute of the entry
+func (e Entry) Field(key string) (interface{}, bool) {
+	for _, a := range e.Attrs {
+		if a.Key == key {
+			return a.Value(), true
+		}
+	}
+	v, ok := e.Fields[key]
+	return v, ok
+}
+
+// String renders the entry in the log file format
+func (e Entry) String() string {
+	return string(appendEntry(make([]byte, 0, 64+len(e.Message)), e))
+}
@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"os"
+	"strings"
+)
+
+// EnvLevel is the environment variable overriding the log level. Component levels are
+// overridden by variables with the component appended, such as LOGGING_LEVEL_payments
+const EnvLevel = "LOGGING_LEVEL"
+
+// LoadEnvLevels sets the log level and component levels from the environment. Variables that
+// are unset leave the current levels alone; invalid levels are passed to the error handler
+func (l *Log) LoadEnvLevels() {
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(key, EnvLevel) {
+			continue
+		}
+		component := strings.TrimPrefix(key, EnvLevel)
+		if component != "" && !strings.HasPrefix(component, "_") {
+			continue
+		}
+		level, err := ParseLevel(value)
+		if err != nil {
+			l.handleError(err)
+			continue
+		}
+		if component == "" {
+			l.SetLevel(level)
+		} else {
+			l.SetComponentLevel(component[1:], level)
+		}
+	}
+}
+
+// ReloadLevelsOnSignal re-reads the levels from the environment whenever the process receives
+// one of the signals; with none, SIGHUP where the platform has it. The returned function stops
+// handling the signals
+func (l *Log) ReloadLevelsOnSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = reloadSignals
+	}
+	return onSignal(sigs, func(os.Signal) {
+		l.LoadEnvLevels()
+	})
+}
@@ -0,0 +1,22 @@
+package logging
+
+import "testing"
+
+func TestEnvLevels(t *testing.T) {
+	t.Setenv(EnvLevel, "warning")
+	t.Setenv(EnvLevel+"_payments", "DEBUG")
+	t.Setenv(EnvLevel+"SUFFIX", "DEBUG")
+	el, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithEnvLevels())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if el.Level() != LevelWarning {
+		t.Errorf("expected the environment to set the level to warning, got %s", el.Level())
+	}
+	if level := Level(el.Named("payments").threshold()); level != LevelDebug {
+		t.Errorf("expected the payments component at debug, got %s", level)
+	}
+	if _, ok := el.levels.components["SUFFIX"]; ok {
+		t.Error("expected variables merely sharing the prefix to be ignored")
+	}
+}
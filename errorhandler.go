@@ -0,0 +1,31 @@
+package logging
+
+import "sync"
+
+type errorHandler struct {
+	mu      sync.RWMutex
+	handler func(error)
+}
+
+// SetErrorHandler sets a function called whenever writing to the file or a sink fails,
+// including failures that are not returned to a caller. It is shared with child logs
+func (l *Log) SetErrorHandler(handler func(error)) {
+	if l.onError == nil {
+		l.onError = &errorHandler{}
+	}
+	l.onError.mu.Lock()
+	defer l.onError.mu.Unlock()
+	l.onError.handler = handler
+}
+
+func (l *Log) handleError(err error) {
+	if l.onError == nil {
+		return
+	}
+	l.onError.mu.RLock()
+	handler := l.onError.handler
+	l.onError.mu.RUnlock()
+	if handler != nil {
+		handler(err)
+	}
+}
@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+)
+
+type failingSink struct {
+	err error
+}
+
+func (s *failingSink) WriteEntry(e Entry) error {
+	return s.err
+}
+
+func TestErrorHandler(t *testing.T) {
+	sink := &failingSink{}
+	el := NewTestLog(t, WithSink(sink))
+	sinkErr := errors.New("sink unavailable")
+	sink.err = sinkErr
+	handled := make([]error, 0)
+	el.WithFields(Fields{"child": true}).SetErrorHandler(func(err error) {
+		handled = append(handled, err)
+	})
+	result, err := el.Info("written")
+	if err != sinkErr || result == "" {
+		t.Errorf("expected the entry to be written and the sink error returned, got '%s' and %v", result, err)
+	}
+	if len(handled) != 1 || handled[0] != sinkErr {
+		t.Errorf("expected the sink error to be handled once, got %v", handled)
+	}
+}
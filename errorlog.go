@@ -0,0 +1,41 @@
+package logging
+
+import (
+	stdlog "log"
+	"strings"
+)
+
+// ErrorLog returns a standard library logger for http.Server.ErrorLog and
+// httputil.ReverseProxy.ErrorLog. TLS handshake errors and other noise caused by clients are
+// written at WARNING; panics and everything else at ERROR
+func (l *Log) ErrorLog() *stdlog.Logger {
+	return stdlog.New(&errorLogWriter{l: l}, "", 0)
+}
+
+// clientErrors are fragments of server messages caused by misbehaving clients
+var clientErrors = []string{
+	"TLS handshake error",
+	"URL query contains semicolon",
+	"superfluous response.WriteHeader",
+}
+
+// errorLogWriter classifies each message written by the standard logger
+type errorLogWriter struct {
+	l *Log
+}
+
+func (w *errorLogWriter) Write(p []byte) (int, error) {
+	message := strings.TrimSuffix(string(p), "\n")
+	level := ERROR
+	if !strings.Contains(message, "panic") {
+		for _, fragment := range clientErrors {
+			if strings.Contains(message, fragment) {
+				level = WARNING
+				break
+			}
+		}
+	}
+	// the standard logger writes each message in a single call, so multi-line panics stay together
+	w.l.Chain().write(message, level)
+	return len(p), nil
+}
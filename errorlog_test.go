@@ -0,0 +1,24 @@
+package logging
+
+import "testing"
+
+func TestErrorLogAdapter(t *testing.T) {
+	c := NewCapture()
+	logger := c.ErrorLog()
+	logger.Printf("http: TLS handshake error from 10.0.0.9:51234: EOF")
+	logger.Printf("http: panic serving 10.0.0.9:51235: boom\ngoroutine 7 [running]:")
+	logger.Printf("httputil: ReverseProxy read error during body copy: unexpected EOF")
+	entries := c.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected three entries, got %v", entries)
+	}
+	levels := []string{WARNING, ERROR, ERROR}
+	for i, level := range levels {
+		if entries[i].Level != level {
+			t.Errorf("expected entry %d at %s, got %s", i, level, entries[i])
+		}
+	}
+	if entries[1].Message != "http: panic serving 10.0.0.9:51235: boom\ngoroutine 7 [running]:" {
+		t.Errorf("expected the panic to be kept in one entry, got %q", entries[1].Message)
+	}
+}
@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+type errorRate struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	alert     func(count int)
+	times     []time.Time
+	tripped   bool
+}
+
+func (r *errorRate) observe(e Entry) {
+	if r == nil || strings.ToUpper(e.Level) != ERROR {
+		return
+	}
+	r.mu.Lock()
+	cutoff := e.Time.Add(-r.window)
+	i := 0
+	for i < len(r.times) && !r.times[i].After(cutoff) {
+		i++
+	}
+	r.times = append(r.times[i:], e.Time)
+	count := len(r.times)
+	fire := count >= r.threshold && !r.tripped
+	r.tripped = count >= r.threshold
+	r.mu.Unlock()
+	if fire && r.alert != nil {
+		r.alert(count)
+	}
+}
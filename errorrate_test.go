@@ -0,0 +1,26 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestErrorRateAlert(t *testing.T) {
+	alerts := 0
+	r := &errorRate{threshold: 3, window: time.Minute, alert: func(count int) { alerts++ }}
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		r.observe(Entry{Time: now, Level: ERROR})
+	}
+	r.observe(Entry{Time: now, Level: INFO})
+	if alerts != 1 {
+		t.Fatalf("expected the alert to fire once while over the threshold, got %d", alerts)
+	}
+	later := now.Add(2 * time.Minute)
+	r.observe(Entry{Time: later, Level: ERROR})
+	r.observe(Entry{Time: later, Level: ERROR})
+	r.observe(Entry{Time: later, Level: ERROR})
+	if alerts != 2 {
+		t.Errorf("expected the alert to re-arm after the window passed, got %d alerts", alerts)
+	}
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrMissingFields is returned when a registered event type is written without all of its
+// required fields; see WithEventTypes
+var ErrMissingFields = errors.New("missing required event fields")
+
+// ErrUnknownEvent is returned by TypedEvent for a name not registered with WithEventTypes
+var ErrUnknownEvent = errors.New("unknown event type")
+
+var errEventName = errors.New("event type name must not be empty")
+
+// EventType is a registered kind of event, such as "user_signup", so that events written
+// by different teams are consistent
+type EventType struct {
+	Name string
+	// Level is the level of the event's entries (default INFO)
+	Level string
+	// Required are the fields every event of the type must have
+	Required []string
+	// Message is the template of the event's message, resolved from the fields as by Event
+	// (default the name)
+	Message string
+}
+
+// Event writes an INFO entry whose message is a template with named placeholders resolved
+// from the fields, such as "user {user_id} purchased {sku}". The fields are attached to the
+// entry and the raw template is kept as "template", so entries can be grouped by it
+func (l *Log) Event(template string, fields Fields) (string, error) {
+	return l.WriteEvent(template, INFO, fields)
+}
+
+// TypedEvent writes an entry for the event type registered as name with WithEventTypes,
+// attaching the fields and the name as "event". Nothing is written if name is not registered,
+// returning ErrUnknownEvent, or if a required field is missing, returning ErrMissingFields
+func (l *Log) TypedEvent(name string, fields Fields) (string, error) {
+	t, ok := l.events[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownEvent, name)
+	}
+	return l.writeEventType(t, fields)
+}
+
+// writeEventType writes an event of a registered type
+func (l *Log) writeEventType(t EventType, fields Fields) (string, error) {
+	var missing []string
+	for _, key := range t.Required {
+		if _, ok := fields[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("%w for %s: %s", ErrMissingFields, t.Name, strings.Join(missing, ", "))
+	}
+	return l.WithFields(fields).With(String("event", t.Name)).Write(resolveTemplate(t.Message, fields), t.Level)
+}
+
+// WriteEvent writes an event at the given level; see Event
+func (l *Log) WriteEvent(template, level string, fields Fields) (string, error) {
+	return l.WithFields(fields).With(String("template", template)).Write(resolveTemplate(template, fields), level)
+}
+
+// resolveTemplate replaces each {name} in the template with the value of the field; unknown
+// placeholders are left as they are
+func resolveTemplate(template string, fields Fields) string {
+	if !strings.Contains(template, "{") {
+		return template
+	}
+	b := make([]byte, 0, len(template)+32)
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		v, ok := fields[template[start+1:end]]
+		if !ok {
+			b = append(b, template[:end+1]...)
+		} else if s, isString := v.(string); isString {
+			b = append(append(b, template[:start]...), s...)
+		} else {
+			b = appendFieldValue(append(b, template[:start]...), v)
+		}
+		template = template[end+1:]
+	}
+	return string(append(b, template...))
+}
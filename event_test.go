@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEvent(t *testing.T) {
+	c := NewCapture()
+	c.Event("user {user_id} purchased {sku} for {amount} via {channel}", Fields{"user_id": 42, "sku": "A-7", "amount": 9.5})
+	e := c.Entries()[0]
+	if e.Message != "user 42 purchased A-7 for 9.5 via {channel}" {
+		t.Errorf("expected placeholders to be resolved, got %s", e.Message)
+	}
+	if v, _ := e.Field("template"); v != "user {user_id} purchased {sku} for {amount} via {channel}" {
+		t.Errorf("expected the raw template to be kept, got %v", v)
+	}
+	if v, _ := e.Field("sku"); v != "A-7" {
+		t.Errorf("expected the fields to be attached, got %v", v)
+	}
+}
+
+func TestResolveTemplate(t *testing.T) {
+	cases := map[string]string{
+		"no placeholders": "no placeholders",
+		"{a}{b}":          "1two",
+		"open { brace":    "open { brace",
+		"{name with gap}": "{name with gap}",
+	}
+	for template, expected := range cases {
+		if result := resolveTemplate(template, Fields{"a": 1, "b": "two"}); result != expected {
+			t.Errorf("expected '%s' to resolve to '%s', got '%s'", template, expected, result)
+		}
+	}
+}
+
+func TestEventType(t *testing.T) {
+	c := NewCapture(WithEventTypes(EventType{Name: "user_signup", Level: NOTICE, Required: []string{"user_id", "plan"}, Message: "user {user_id} signed up"}))
+	c.Reset()
+	if _, err := c.TypedEvent("user_signup", Fields{"user_id": 42}); !errors.Is(err, ErrMissingFields) || !strings.HasSuffix(err.Error(), ": plan") {
+		t.Errorf("expected the missing field to be reported, got %v", err)
+	}
+	if len(c.Entries()) != 0 {
+		t.Error("expected an invalid event not to be written")
+	}
+	c.TypedEvent("user_signup", Fields{"user_id": 42, "plan": "pro"})
+	e := c.Entries()[0]
+	if e.Level != NOTICE || e.Message != "user 42 signed up" {
+		t.Errorf("expected the event type's level and message, got %s %s", e.Level, e.Message)
+	}
+	if v, _ := e.Field("event"); v != "user_signup" {
+		t.Errorf("expected the event name to be attached, got %v", v)
+	}
+	if _, ok := e.Field("template"); ok {
+		t.Error("expected no template on a typed event")
+	}
+	// a misspelt name is not taken for a template
+	if _, err := c.TypedEvent("user_sginup", Fields{"user_id": 42}); !errors.Is(err, ErrUnknownEvent) || len(c.Entries()) != 1 {
+		t.Errorf("expected an unknown event type to be rejected, got %v", err)
+	}
+}
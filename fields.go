@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fields holds structured data attached to an entry
+type Fields map[string]interface{}
+
+// String renders the fields as space-prefixed key=value pairs, sorted by key
+func (f Fields) String() string {
+	if len(f) == 0 {
+		return ""
+	}
+	keys := f.keys()
+	b := make([]byte, 0, 16*len(keys))
+	for _, k := range keys {
+		b = append(b, ' ')
+		b = append(b, k...)
+		b = append(b, '=')
+		b = appendFieldValue(b, f[k])
+	}
+	return string(b)
+}
+
+// keys returns the field keys, sorted
+func (f Fields) keys() []string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func fieldValue(v interface{}) string {
+	return string(appendFieldValue(nil, v))
+}
+
+func appendFieldValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case string:
+		return appendFieldString(b, v)
+	case int:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(b, v, 10)
+	case bool:
+		return strconv.AppendBool(b, v)
+	}
+	return appendFieldString(b, fmt.Sprint(v))
+}
+
+func appendFieldString(b []byte, s string) []byte {
+	if s == "" || strings.ContainsAny(s, " =\"\n\r\t") {
+		return strconv.AppendQuote(b, s)
+	}
+	return append(b, s...)
+}
+
+// WithFields returns a child log that attaches the given fields to every entry.
+// The child shares its destination and configuration with the parent
+func (l *Log) WithFields(fields Fields) *Log {
+	child := *l
+	child.fields = make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		child.fields[k] = v
+	}
+	for k, v := range fields {
+		child.fields[k] = v
+	}
+	return &child
+}
+
+// fieldAttrs converts fields to attributes sorted by key
+func fieldAttrs(fields Fields) []Attr {
+	attrs := make([]Attr, 0, len(fields))
+	for _, k := range fields.keys() {
+		attrs = append(attrs, Any(k, fields[k]))
+	}
+	return attrs
+}
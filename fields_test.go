@@ -0,0 +1,26 @@
+package logging
+
+import (
+	"os"
+	"testing"
+)
+
+func TestStaticFields(t *testing.T) {
+	c := NewCapture(WithProcessInfo(), WithService("billing", "1.4.2"), WithStaticFields(Fields{"region": "eu-west-1"}))
+	c.WithFields(Fields{"invoice": 9}).Info("issued")
+	e := c.Entries()[0]
+	host, _ := os.Hostname()
+	expected := map[string]interface{}{
+		"hostname": host,
+		"pid":      int64(os.Getpid()),
+		"service":  "billing",
+		"version":  "1.4.2",
+		"region":   "eu-west-1",
+		"invoice":  9,
+	}
+	for key, value := range expected {
+		if v, _ := e.Field(key); v != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, v)
+		}
+	}
+}
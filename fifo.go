@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// fifoWriteTimeout bounds how long a write waits for a slow reader to make room in the pipe
+const fifoWriteTimeout = time.Second
+
+// ErrNoReader is returned by a FIFO sink while no process has the named pipe open for reading
+var ErrNoReader = errors.New("no reader attached to the named pipe")
+
+// FIFOSink writes each entry as a line to a named pipe, for collectors that read from one.
+// The pipe is opened without blocking, so nothing waits for a reader to attach: entries
+// written while there is none fail with ErrNoReader, and the pipe is reopened on the next
+// entry after the reader goes away
+type FIFOSink struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// NewFIFOSink returns a sink writing to the named pipe at path, which is created if it does
+// not exist. On Windows, path names a pipe served by another process, e.g. \\.\pipe\collector
+func NewFIFOSink(path string) *FIFOSink {
+	return &FIFOSink{path: path}
+}
+
+func (s *FIFOSink) WriteEntry(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		file, err := openFIFO(s.path)
+		if err != nil {
+			return err
+		}
+		s.file = file
+	}
+	s.file.SetWriteDeadline(time.Now().Add(fifoWriteTimeout))
+	_, err := s.file.WriteString(e.String() + "\n")
+	if err != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+		// the reader has gone away; reconnect on the next entry
+		s.file.Close()
+		s.file = nil
+	}
+	return err
+}
+
+// Close closes the pipe if it is open
+func (s *FIFOSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,17 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package logging
+
+import (
+	"errors"
+	"os"
+)
+
+// openFIFO connects to the named pipe at path, which must already be served by a reader
+func openFIFO(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoReader
+	}
+	return file, err
+}
@@ -0,0 +1,23 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package logging
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// openFIFO opens the named pipe at path for writing without blocking, creating it if needed.
+// Opening fails with ENXIO while there is no reader
+func openFIFO(path string) (*os.File, error) {
+	err := syscall.Mkfifo(path, uint32(defaultFileMode))
+	if err != nil && !errors.Is(err, syscall.EEXIST) {
+		return nil, &os.PathError{Op: "mkfifo", Path: path, Err: err}
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if errors.Is(err, syscall.ENXIO) {
+		return nil, ErrNoReader
+	}
+	return file, err
+}
@@ -0,0 +1,50 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package logging
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestFIFOSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "collector.fifo")
+	sink := NewFIFOSink(path)
+	defer sink.Close()
+	write := func(message string) error {
+		return sink.WriteEntry(Entry{Level: INFO, Message: message})
+	}
+	if err := write("nobody listening"); err != ErrNoReader {
+		t.Fatalf("expected no reader, got %v", err)
+	}
+	openReader := func() (*os.File, *bufio.Reader) {
+		reader, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return reader, bufio.NewReader(reader)
+	}
+	reader, lines := openReader()
+	if err := write("first"); err != nil {
+		t.Fatal(err)
+	}
+	if line, _ := lines.ReadString('\n'); !strings.HasSuffix(line, "first\n") {
+		t.Errorf("expected the first entry, got %q", line)
+	}
+	reader.Close()
+	if err := write("reader gone"); err == nil {
+		t.Error("expected an error after the reader went away")
+	}
+	reader, lines = openReader()
+	defer reader.Close()
+	if err := write("reconnected"); err != nil {
+		t.Fatal(err)
+	}
+	if line, _ := lines.ReadString('\n'); !strings.HasSuffix(line, "reconnected\n") {
+		t.Errorf("expected the entry after reconnecting, got %q", line)
+	}
+}
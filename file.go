@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultFileMode os.FileMode = 0666
+
+var (
+	lf   = []byte("\n")
+	crlf = []byte("\r\n")
+)
+
+// lineEnd returns the bytes ending each record in the file; see WithCRLF
+func (l *Log) lineEnd() []byte {
+	if l.crlf {
+		return crlf
+	}
+	return lf
+}
+
+// trimLineEnd removes a trailing LF or CRLF from a line
+func trimLineEnd(line string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+}
+
+type fileOwner struct {
+	uid, gid int
+}
+
+// createLog creates the log file, and its directories if configured, with the configured
+// permissions, starting it with the encoder's header if it has one
+func (l *Log) createLog() error {
+	if l.dirMode != 0 {
+		if err := l.getFS().MkdirAll(filepath.Dir(l.path), l.dirMode); err != nil {
+			return err
+		}
+	}
+	mode := l.fileMode
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+	file, err := l.getFS().OpenFile(l.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+	if os.IsExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if h, ok := l.encoder.(FileHeaderEncoder); ok && l.cipher == nil {
+		header := h.FileHeader(l.now())
+		if l.crlf {
+			header = bytes.ReplaceAll(header, lf, crlf)
+		}
+		if _, err = file.Write(header); err != nil {
+			return err
+		}
+	}
+	if l.fileMode != 0 {
+		// the mode passed to OpenFile is subject to the umask
+		if err = file.Chmod(l.fileMode); err != nil {
+			return err
+		}
+	}
+	if l.owner != nil {
+		return file.Chown(l.owner.uid, l.owner.gid)
+	}
+	return nil
+}
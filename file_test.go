@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestFileModeAndDirs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not supported on windows")
+	}
+	path := filepath.Join(t.TempDir(), "nested", "dir", "perms.log")
+	_, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithFileMode(0600), WithCreateDirs(0700))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Mode().Perm() != 0600 {
+		t.Errorf("expected file mode to be 0600, got %o", stat.Mode().Perm())
+	}
+	stat, err = os.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Mode().Perm() != 0700 {
+		t.Errorf("expected directory mode to be 0700, got %o", stat.Mode().Perm())
+	}
+}
+
+func TestMissingDirs(t *testing.T) {
+	_, err := NewLog(filepath.Join(t.TempDir(), "missing", "perms.log"), "TEST", LEVEL_INFO, LEVEL_NONE)
+	if err == nil {
+		t.Error("expected an error when parent directories are missing and not created")
+	}
+}
@@ -0,0 +1,38 @@
+package logging
+
+// Filter reports whether an entry should be kept
+type Filter func(e Entry) bool
+
+type filterSink struct {
+	sink    Sink
+	filters []Filter
+}
+
+// FilterSink wraps a sink so that it only receives entries accepted by every filter
+func FilterSink(sink Sink, filters ...Filter) Sink {
+	return &filterSink{sink: sink, filters: filters}
+}
+
+func (f *filterSink) WriteEntry(e Entry) error {
+	for _, filter := range f.filters {
+		if !filter(e) {
+			return nil
+		}
+	}
+	return f.sink.WriteEntry(e)
+}
+
+// FieldEquals returns a filter accepting entries whose field or attribute key has the given value
+func FieldEquals(key string, value interface{}) Filter {
+	return func(e Entry) bool {
+		v, ok := e.Field(key)
+		return ok && v == value
+	}
+}
+
+// Not returns a filter accepting the entries the given filter rejects
+func Not(filter Filter) Filter {
+	return func(e Entry) bool {
+		return !filter(e)
+	}
+}
@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFilters(t *testing.T) {
+	ring := NewRingBuffer(10)
+	fl, err := NewLog(
+		filepath.Join(t.TempDir(), "filter.log"), "TEST", LEVEL_INFO, LEVEL_NONE,
+		WithFilter(Not(func(e Entry) bool { return strings.HasPrefix(e.Message, "health") })),
+		WithSink(FilterSink(ring, FieldEquals("tenant", "acme"))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := fl.Info("health ok"); result != "" {
+		t.Errorf("expected filtered entry not to be written, got '%s'", result)
+	}
+	fl.WithFields(Fields{"tenant": "acme"}).Info("order placed")
+	fl.WithFields(Fields{"tenant": "globex"}).Info("order placed")
+	entries := ring.Entries()
+	if len(entries) != 1 || entries[0].Fields["tenant"] != "acme" {
+		t.Errorf("expected the sink to only receive the acme entry, got %v", entries)
+	}
+}
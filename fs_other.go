@@ -0,0 +1,15 @@
+//go:build !windows
+
+package logging
+
+import "os"
+
+func openFile(name string, flag int, perm os.FileMode) (*os.File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+// isSharingViolation reports whether a rename failed because another process has the file
+// open, which only happens on Windows
+func isSharingViolation(err error) bool {
+	return false
+}
@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMemFS(t *testing.T) {
+	fs := NewMemFS()
+	ml, err := NewLog("/var/log/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithCreateDirs(0755), WithFileMode(0600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ml.Info("in memory\nmulti-line")
+	ml.Error("second")
+	names := fs.Names()
+	if len(names) != 1 || names[0] != "/var/log/app.log" {
+		t.Fatalf("expected a single file in the memory filesystem, got %v", names)
+	}
+	stat, err := fs.Stat("/var/log/app.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Mode().Perm() != 0600 {
+		t.Errorf("expected file mode 0600, got %o", stat.Mode().Perm())
+	}
+	result, err := ml.GetLog(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 || !strings.Contains(result[0], "second") || !strings.Contains(result[1], "in memory\nmulti-line") {
+		t.Errorf("expected to read back both entries, got %v", result)
+	}
+	result, err = ml.GetLog(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 3 {
+		t.Errorf("expected a whole read to return 3 entries, got %d", len(result))
+	}
+}
@@ -0,0 +1,81 @@
+//go:build windows
+
+package logging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// errorSharingViolation is returned by Windows when a file is open without the share mode
+// an operation needs
+const errorSharingViolation syscall.Errno = 32
+
+// openFile opens a file as os.OpenFile does, but shares it for reading, writing and deletion
+// so that other processes can read the log while it is written and it can be renamed while
+// open. os.OpenFile does not allow deletion, which stops rotation while a reader has the file open
+func openFile(name string, flag int, perm os.FileMode) (*os.File, error) {
+	path, err := syscall.UTF16PtrFromString(longPath(name))
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: name, Err: err}
+	}
+	var access uint32
+	switch flag & (os.O_RDONLY | os.O_WRONLY | os.O_RDWR) {
+	case os.O_RDONLY:
+		access = syscall.GENERIC_READ
+	case os.O_WRONLY:
+		access = syscall.GENERIC_WRITE
+	case os.O_RDWR:
+		access = syscall.GENERIC_READ | syscall.GENERIC_WRITE
+	}
+	if flag&os.O_CREATE != 0 {
+		access |= syscall.GENERIC_WRITE
+	}
+	if flag&os.O_APPEND != 0 {
+		access &^= syscall.GENERIC_WRITE
+		access |= syscall.FILE_APPEND_DATA
+	}
+	var disposition uint32
+	switch {
+	case flag&(os.O_CREATE|os.O_EXCL) == os.O_CREATE|os.O_EXCL:
+		disposition = syscall.CREATE_NEW
+	case flag&(os.O_CREATE|os.O_TRUNC) == os.O_CREATE|os.O_TRUNC:
+		disposition = syscall.CREATE_ALWAYS
+	case flag&os.O_CREATE == os.O_CREATE:
+		disposition = syscall.OPEN_ALWAYS
+	case flag&os.O_TRUNC == os.O_TRUNC:
+		disposition = syscall.TRUNCATE_EXISTING
+	default:
+		disposition = syscall.OPEN_EXISTING
+	}
+	attrs := uint32(syscall.FILE_ATTRIBUTE_NORMAL | syscall.FILE_FLAG_BACKUP_SEMANTICS)
+	if flag&os.O_CREATE != 0 && perm&0200 == 0 {
+		attrs |= syscall.FILE_ATTRIBUTE_READONLY
+	}
+	share := uint32(syscall.FILE_SHARE_READ | syscall.FILE_SHARE_WRITE | syscall.FILE_SHARE_DELETE)
+	handle, err := syscall.CreateFile(path, access, share, nil, disposition, attrs, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: name, Err: err}
+	}
+	return os.NewFile(uintptr(handle), name), nil
+}
+
+// longPath returns an absolute path with the \\?\ prefix, which lifts the 260 character limit
+// on paths passed to the Windows API
+func longPath(name string) string {
+	if strings.HasPrefix(name, `\\`) {
+		return name // already prefixed, or a UNC path
+	}
+	abs, err := filepath.Abs(name)
+	if err != nil || len(abs) < 248 {
+		return name
+	}
+	return `\\?\` + abs
+}
+
+func isSharingViolation(err error) bool {
+	return errors.Is(err, errorSharingViolation)
+}
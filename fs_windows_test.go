@@ -0,0 +1,58 @@
+//go:build windows
+
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotateWhileOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	wl, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wl.Close()
+	// a reader sharing the file for deletion does not stop it being renamed
+	shared, err := openFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = wl.Rotate(); err != nil {
+		t.Errorf("expected rotation with a shared reader to succeed, got %v", err)
+	}
+	shared.Close()
+	// os.Open does not share for deletion, so the file is copied and truncated instead
+	wl.Info("second file")
+	reader, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	rotated, err := wl.Rotate()
+	if err != nil {
+		t.Fatalf("expected rotation with an exclusive reader to fall back to copying, got %v", err)
+	}
+	b, _ := os.ReadFile(rotated)
+	if !strings.Contains(string(b), "second file") {
+		t.Errorf("expected the rotated copy to hold the records, got %q", b)
+	}
+	if info, _ := os.Stat(path); info.Size() != 0 {
+		t.Errorf("expected the log file to be truncated, got %d bytes", info.Size())
+	}
+}
+
+func TestLongPath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), strings.Repeat("d", 120), strings.Repeat("e", 120))
+	wl, err := NewLog(filepath.Join(dir, "app.log"), "TEST", LEVEL_INFO, LEVEL_NONE, WithCreateDirs(0755))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wl.Close()
+	if result, err := wl.Info("long path"); err != nil || result == "" {
+		t.Errorf("expected to write beneath a long path, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// Gap is a period between two consecutive entries, From and To, with no entries in between
+type Gap struct {
+	From, To time.Time
+}
+
+// Duration returns the length of the gap
+func (g Gap) Duration() time.Duration {
+	return g.To.Sub(g.From)
+}
+
+// FindGaps reads records from r and returns the gaps between entries longer than threshold,
+// which usually mean the process crashed or stopped logging. Records without a readable
+// time are skipped
+func FindGaps(r io.Reader, threshold time.Duration) ([]Gap, error) {
+	gaps := make([]Gap, 0)
+	scanner := newRecordScanner(r)
+	var last time.Time
+	for {
+		record, err := scanner.next()
+		if err == io.EOF {
+			return gaps, nil
+		}
+		if err != nil {
+			return gaps, err
+		}
+		t, ok := recordTime([]byte(record))
+		if !ok {
+			continue
+		}
+		if !last.IsZero() && t.Sub(last) > threshold {
+			gaps = append(gaps, Gap{From: last, To: t})
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+}
+
+// Gaps returns the gaps between entries in the log file longer than threshold; see FindGaps
+func (l *Log) Gaps(threshold time.Duration) ([]Gap, error) {
+	if l.path == "" {
+		return []Gap{}, nil
+	}
+	file, err := l.getFS().OpenFile(l.path, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return FindGaps(file, threshold)
+}
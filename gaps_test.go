@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindGaps(t *testing.T) {
+	records := strings.Join([]string{
+		"[2024-01-02T00:00:00Z] [TEST.INFO] started",
+		"[2024-01-02T00:00:30Z] [TEST.INFO] working",
+		"  still working",
+		"[2024-01-02T00:20:00Z] [TEST.INFO] started again",
+		"[2024-01-02T00:19:00Z] [TEST.INFO] written late",
+		"[2024-01-02T00:21:00Z] [TEST.INFO] working",
+	}, "\n")
+	gaps, err := FindGaps(strings.NewReader(records), 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gaps) != 1 {
+		t.Fatalf("expected 1 gap, got %v", gaps)
+	}
+	if !gaps[0].From.Equal(time.Date(2024, 1, 2, 0, 0, 30, 0, time.UTC)) || gaps[0].Duration() != 19*time.Minute+30*time.Second {
+		t.Errorf("unexpected gap %v", gaps[0])
+	}
+}
+
+func TestLogGaps(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	gl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(NewMemFS()), WithNow(func() time.Time {
+		return now
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	gl.Info("back")
+	gaps, err := gl.Gaps(time.Minute)
+	if err != nil || len(gaps) != 1 || gaps[0].Duration() != time.Hour {
+		t.Errorf("expected an hour long gap, got %v and %v", gaps, err)
+	}
+}
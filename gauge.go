@@ -0,0 +1,164 @@
+package logging
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	metricGauge = "gauge"
+	metricCount = "count"
+	// defaultMetricInterval is how often aggregated metrics are written when no interval is given
+	defaultMetricInterval = 10 * time.Second
+)
+
+// Gauge writes an INFO metric entry recording the current value of name, such as a queue's
+// depth, with the attributes "metric", "metric_type" (gauge) and "value". With
+// WithMetricAggregation the value is held and only the last one of each interval is written
+func (l *Log) Gauge(name string, value float64) (string, error) {
+	return l.metric(metricGauge, name, value)
+}
+
+// Count writes an INFO metric entry recording that name happened delta times, such as jobs
+// done, as Gauge does with the metric_type count. With WithMetricAggregation the deltas of
+// each interval are summed into one entry
+func (l *Log) Count(name string, delta int64) (string, error) {
+	return l.metric(metricCount, name, float64(delta))
+}
+
+func (l *Log) metric(kind, name string, value float64) (string, error) {
+	if l.metrics != nil {
+		l.metrics.add(kind, name, value, l.attrs)
+		return "", nil
+	}
+	return l.With(metricAttrs(kind, name, value, 0)...).Write(metricMessage(name, value), INFO)
+}
+
+func metricAttrs(kind, name string, value float64, samples int64) []Attr {
+	attrs := []Attr{String("metric", name), String("metric_type", kind), Float64("value", value)}
+	if samples > 0 {
+		attrs = append(attrs, Int64("samples", samples))
+	}
+	return attrs
+}
+
+func metricMessage(name string, value float64) string {
+	return name + "=" + strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// WithMetricAggregation pre-aggregates the metrics of Gauge and Count, writing for each
+// metric recorded in an interval (default 10 seconds) one entry with the last gauge value or
+// the sum of the counts, and the number of values as "samples". Metrics held are written on
+// Close. Metrics recorded through logs with different attributes (see With), such as one for
+// each worker, are aggregated apart and written with those attributes
+func WithMetricAggregation(interval time.Duration) Option {
+	return func(l *Log) {
+		if interval <= 0 {
+			interval = defaultMetricInterval
+		}
+		l.metrics = &metricAggregator{interval: interval, values: make(map[metricKey]*metricValue)}
+	}
+}
+
+type metricAggregator struct {
+	interval time.Duration
+	mu       sync.Mutex
+	values   map[metricKey]*metricValue
+}
+
+type metricKey struct {
+	kind, name string
+	attrs      string // the attributes of the log recording the metric; see attrsKey
+}
+
+type metricValue struct {
+	value   float64
+	samples int64
+	attrs   []Attr
+}
+
+// attrsKey encodes attributes as a string, so that metrics with the same ones share a key
+func attrsKey(attrs []Attr) string {
+	var b []byte
+	for _, a := range attrs {
+		b = append(append(b, a.Key...), '=')
+		b = append(a.appendValue(b), 0)
+	}
+	return string(b)
+}
+
+func (m *metricAggregator) add(kind, name string, value float64, attrs []Attr) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := metricKey{kind, name, attrsKey(attrs)}
+	v, ok := m.values[key]
+	if !ok {
+		v = &metricValue{attrs: attrs}
+		m.values[key] = v
+	}
+	if kind == metricCount {
+		v.value += value
+	} else {
+		v.value = value
+	}
+	v.samples++
+}
+
+// take returns the metrics held, sorted by name and then attributes, and starts the next
+// interval
+func (m *metricAggregator) take() ([]metricKey, map[metricKey]*metricValue) {
+	m.mu.Lock()
+	values := m.values
+	m.values = make(map[metricKey]*metricValue, len(values))
+	m.mu.Unlock()
+	keys := make([]metricKey, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		if keys[i].kind != keys[j].kind {
+			return keys[i].kind < keys[j].kind
+		}
+		return keys[i].attrs < keys[j].attrs
+	})
+	return keys, values
+}
+
+// watchMetrics writes the aggregated metrics every interval until the log is closed
+func (l *Log) watchMetrics() {
+	ticker := time.NewTicker(l.metrics.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-l.done:
+			return
+		}
+		if err := l.flushMetrics(); err != nil {
+			l.handleError(err)
+		}
+	}
+}
+
+// flushMetrics writes an entry for each metric aggregated since the last flush
+func (l *Log) flushMetrics() error {
+	if l.metrics == nil {
+		return nil
+	}
+	keys, values := l.metrics.take()
+	for _, key := range keys {
+		v := values[key]
+		recorder := *l
+		recorder.attrs = v.attrs
+		ml := recorder.With(metricAttrs(key.kind, key.name, v.value, v.samples)...)
+		if _, err := ml.writeEntry(ml.newEntry(INFO, metricMessage(key.name, v.value))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+// field returns the value of an entry's field or attribute, or nil
+func field(e Entry, key string) interface{} {
+	v, _ := e.Field(key)
+	return v
+}
+
+func TestMetrics(t *testing.T) {
+	ring := NewRingBuffer(10)
+	ml, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithSink(ring))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ml.Gauge("queue_depth", 42)
+	ml.Count("jobs_done", 10)
+	entries := ring.Entries()
+	gauge, count := entries[len(entries)-2], entries[len(entries)-1]
+	if gauge.Message != "queue_depth=42" || field(gauge, "metric_type") != "gauge" || field(gauge, "value") != 42.0 {
+		t.Errorf("unexpected gauge entry %s", gauge)
+	}
+	if count.Message != "jobs_done=10" || field(count, "metric") != "jobs_done" || field(count, "metric_type") != "count" {
+		t.Errorf("unexpected count entry %s", count)
+	}
+}
+
+func TestMetricAggregation(t *testing.T) {
+	ring := NewRingBuffer(10)
+	ml, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithSink(ring), WithMetricAggregation(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		ml.Gauge("queue_depth", float64(i))
+		ml.With(String("worker", "a")).Count("jobs_done", 10)
+	}
+	ml.With(String("worker", "b")).Count("jobs_done", 1)
+	if n := len(ring.Entries()); n != 1 {
+		t.Fatalf("expected the metrics to be held, got %d entries", n)
+	}
+	ml.Close()
+	entries := ring.Entries()[1:]
+	if len(entries) != 3 {
+		t.Fatalf("expected one entry for each metric and attributes, got %v", entries)
+	}
+	if entries[0].Message != "jobs_done=30" || field(entries[0], "samples") != int64(3) || field(entries[0], "worker") != "a" {
+		t.Errorf("expected the counts of worker a to be summed, got %s", entries[0])
+	}
+	if entries[1].Message != "jobs_done=1" || field(entries[1], "worker") != "b" {
+		t.Errorf("expected worker b's count apart, got %s", entries[1])
+	}
+	if entries[2].Message != "queue_depth=3" || field(entries[2], "worker") != nil {
+		t.Errorf("expected the last gauge value, got %s", entries[2])
+	}
+}
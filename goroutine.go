@@ -0,0 +1,20 @@
+package logging
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// goroutineID parses the current goroutine's ID from the header of its stack trace,
+// "goroutine 18 [running]:"
+func goroutineID() int64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseInt(string(b), 10, 64)
+	return id
+}
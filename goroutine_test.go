@@ -0,0 +1,25 @@
+package logging
+
+import "testing"
+
+func TestGoroutineID(t *testing.T) {
+	c := NewCapture(WithGoroutineID())
+	c.Info("main")
+	done := make(chan struct{})
+	go func() {
+		c.Info("worker")
+		close(done)
+	}()
+	<-done
+	entries := c.Entries()
+	main, _ := entries[0].Field("goroutine")
+	worker, _ := entries[1].Field("goroutine")
+	if main == int64(0) || worker == int64(0) || main == worker {
+		t.Errorf("expected distinct goroutine IDs, got %v and %v", main, worker)
+	}
+	off := NewCapture()
+	off.Info("off")
+	if _, ok := off.Entries()[0].Field("goroutine"); ok {
+		t.Error("expected the goroutine ID to be off by default")
+	}
+}
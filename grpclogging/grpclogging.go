@@ -0,0 +1,116 @@
+// Package grpclogging logs gRPC calls with a logging.Log, through server and client
+// interceptors. It is kept apart from the logging package so that only programs using gRPC
+// depend on it
+package grpclogging
+
+import (
+	"context"
+	"io"
+	"time"
+
+	logging "github.com/blainemoser/Logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor logs each unary call the server handles; see logging.Log.LogRPC
+func UnaryServerInterceptor(l *logging.Log) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		l.WithContext(ctx).LogRPC(logging.RPC{
+			Method:   info.FullMethod,
+			Code:     status.Code(err).String(),
+			Latency:  time.Since(start),
+			Peer:     peerAddr(ctx),
+			Request:  req,
+			Response: resp,
+			Err:      err,
+		})
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs each streaming call the server handles once it ends. Messages
+// are not logged
+func StreamServerInterceptor(l *logging.Log) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		ctx := ss.Context()
+		l.WithContext(ctx).LogRPC(logging.RPC{
+			Method:  info.FullMethod,
+			Code:    status.Code(err).String(),
+			Latency: time.Since(start),
+			Peer:    peerAddr(ctx),
+			Err:     err,
+		})
+		return err
+	}
+}
+
+// UnaryClientInterceptor logs each unary call the client makes, with the target it is made to
+// as the peer
+func UnaryClientInterceptor(l *logging.Log) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		l.WithContext(ctx).LogRPC(logging.RPC{
+			Method:   method,
+			Code:     status.Code(err).String(),
+			Latency:  time.Since(start),
+			Peer:     cc.Target(),
+			Request:  req,
+			Response: reply,
+			Err:      err,
+		})
+		return err
+	}
+}
+
+// StreamClientInterceptor logs each streaming call the client makes once it ends: when a
+// receive returns io.EOF or an error, or the stream cannot be opened. Streams the caller
+// abandons without reading to the end are not logged
+func StreamClientInterceptor(l *logging.Log) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		rpc := logging.RPC{Method: method, Peer: cc.Target()}
+		if err != nil {
+			rpc.Code, rpc.Latency, rpc.Err = status.Code(err).String(), time.Since(start), err
+			l.WithContext(ctx).LogRPC(rpc)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, log: l.WithContext(ctx), rpc: rpc, start: start}, nil
+	}
+}
+
+// clientStream logs its call when a receive ends it
+type clientStream struct {
+	grpc.ClientStream
+	log    *logging.Log
+	rpc    logging.RPC
+	start  time.Time
+	logged bool
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil && !s.logged {
+		s.logged = true
+		s.rpc.Latency = time.Since(s.start)
+		if err != io.EOF {
+			s.rpc.Code, s.rpc.Err = status.Code(err).String(), err
+		}
+		s.log.LogRPC(s.rpc)
+	}
+	return err
+}
+
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
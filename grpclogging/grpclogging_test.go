@@ -0,0 +1,77 @@
+package grpclogging
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	logging "github.com/blainemoser/Logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestInterceptors(t *testing.T) {
+	server, client := logging.NewCapture(), logging.NewCapture()
+	lis := bufconn.Listen(1 << 16)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(server.Log)),
+		grpc.StreamInterceptor(StreamServerInterceptor(server.Log)),
+	)
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(lis)
+	defer srv.Stop()
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(client.Log)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(client.Log)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	hc := healthpb.NewHealthClient(conn)
+	ctx := context.Background()
+	if _, err = hc.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = hc.Check(ctx, &healthpb.HealthCheckRequest{Service: "missing"}); err == nil {
+		t.Fatal("expected a NotFound error for an unknown service")
+	}
+	watchCtx, cancel := context.WithCancel(ctx)
+	stream, err := hc.Watch(watchCtx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	srv.GracefulStop()
+
+	const check, watch = "/grpc.health.v1.Health/Check", "/grpc.health.v1.Health/Watch"
+	for side, c := range map[string]*logging.Capture{"server": server, "client": client} {
+		entries := c.Entries()
+		if len(entries) != 3 {
+			t.Fatalf("expected three %s entries, got %v", side, entries)
+		}
+		if entries[0].Level != logging.INFO || entries[0].Message != check+" OK" {
+			t.Errorf("expected the %s to log a successful call at info, got %s", side, entries[0])
+		}
+		if entries[1].Level != logging.WARNING || entries[1].Message != check+" NotFound" {
+			t.Errorf("expected the %s to log a NotFound call at warning, got %s", side, entries[1])
+		}
+		if entries[2].Message != watch+" Canceled" {
+			t.Errorf("expected the %s to log the cancelled stream, got %s", side, entries[2])
+		}
+	}
+	if peer, _ := client.Entries()[0].Field("peer"); peer != "bufnet" {
+		t.Errorf("expected the client to log its target as the peer, got %v", peer)
+	}
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+)
+
+// defaultMinFreeSpace is the free space Healthy requires by default; see WithMinFreeSpace
+const defaultMinFreeSpace = 16 << 20
+
+// asyncWedgedAfter is how long an entry may wait in the async queue before Healthy
+// reports the queue as wedged
+const asyncWedgedAfter = 30 * time.Second
+
+var errMinFreeSpace = errors.New("minimum free space must not be negative")
+
+// Healthy checks the write path of the log for use in readiness probes: the log is open,
+// the log file can be opened for writing and its filesystem has the free space set by
+// WithMinFreeSpace, no entry has waited in the async queue for longer than 30 seconds, and
+// the sinks implementing Checker pass their checks, which connects remote sinks that are not
+// connected. Nothing is written. The first problem found is returned
+func (l *Log) Healthy() error {
+	if l.isClosed() {
+		return ErrClosed
+	}
+	if l.path != "" {
+		if err := l.checkOutput(); err != nil {
+			return err
+		}
+	}
+	if l.async != nil {
+		if age := l.async.stats().OldestAge; age > asyncWedgedAfter {
+			return fmt.Errorf("async queue is wedged: oldest entry queued %s ago", age.Round(time.Second))
+		}
+	}
+	for i, s := range l.sinks {
+		if err := checkSink(s); err != nil {
+			return fmt.Errorf("sink %d (%T): %w", i+1, s, err)
+		}
+	}
+	return nil
+}
+
+// checkOutput checks the log file is open, or can be, and has room to grow
+func (l *Log) checkOutput() error {
+	l.mu.Lock()
+	_, err := l.outputFile()
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if _, ok := l.getFS().(osFS); !ok || l.minFree == 0 {
+		return nil
+	}
+	dir := filepath.Dir(l.path)
+	if free, ok := freeSpace(dir); ok && free < uint64(l.minFree) {
+		return fmt.Errorf("only %d bytes free on the filesystem of %s", free, dir)
+	}
+	return nil
+}
+
+// HealthHandler returns a handler for readiness probes, responding 200 OK if Healthy passes
+// and 503 Service Unavailable with the problem if not
+func (l *Log) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := l.Healthy(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "ok")
+	})
+}
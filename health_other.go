@@ -0,0 +1,8 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package logging
+
+// freeSpace is not supported on this platform, so the free space is not checked
+func freeSpace(dir string) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHealthy(t *testing.T) {
+	remote := &failingSink{}
+	breaker := NewCircuitBreakerSink(remote, BreakerPolicy{Failures: 1, Cooldown: time.Hour})
+	hl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(NewMemFS()), WithSink(breaker))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := hl.HealthHandler()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected a healthy log, got %d: %s", rec.Code, rec.Body.String())
+	}
+	remote.err = errors.New("down")
+	hl.Info("lost")
+	if err = hl.Healthy(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected the open circuit to be reported, got %v", err)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected an unhealthy log, got %d", rec.Code)
+	}
+	hl.Close()
+	if err = hl.Healthy(); err != ErrClosed {
+		t.Errorf("expected a closed log to be unhealthy, got %v", err)
+	}
+}
+
+func TestHealthyFreeSpace(t *testing.T) {
+	dir := t.TempDir()
+	free, ok := freeSpace(dir)
+	if !ok {
+		t.Skip("free space is not available on this platform")
+	}
+	hl, err := NewLog(filepath.Join(dir, "app.log"), "TEST", LEVEL_INFO, LEVEL_NONE, WithMinFreeSpace(int64(free)+1<<40))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hl.Close()
+	if err = hl.Healthy(); err == nil {
+		t.Error("expected too little free space to be reported")
+	}
+}
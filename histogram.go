@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"errors"
+	"time"
+)
+
+// maxHistogramBuckets is the most buckets a histogram is built with
+const maxHistogramBuckets = 10000
+
+var (
+	errBucketSize     = errors.New("histogram bucket must be positive")
+	errTooManyBuckets = errors.New("histogram range holds too many buckets")
+)
+
+// Bucket counts the entries of each level written in an interval starting at Start
+type Bucket struct {
+	Start  time.Time
+	Counts map[string]int
+}
+
+// Histogram counts the entries of each level written from from up to but not including to,
+// in consecutive buckets of the given width, e.g. to chart when errors spiked. Every bucket is
+// returned, including those with no entries, up to 10000 of them. It reads the file as
+// ReadRange does
+func (l *Log) Histogram(from, to time.Time, bucket time.Duration) ([]Bucket, error) {
+	if bucket <= 0 {
+		return nil, errBucketSize
+	}
+	if (to.Sub(from)-1)/bucket >= maxHistogramBuckets {
+		return nil, errTooManyBuckets
+	}
+	buckets := make([]Bucket, 0)
+	for start := from; start.Before(to); start = start.Add(bucket) {
+		buckets = append(buckets, Bucket{Start: start.UTC(), Counts: make(map[string]int)})
+	}
+	records, err := l.ReadRange(from, to)
+	if err != nil {
+		return buckets, err
+	}
+	for _, record := range records {
+		e, err := ParseEntry(record)
+		if err != nil {
+			continue
+		}
+		buckets[int(e.Time.Sub(from)/bucket)].Counts[e.Level]++
+	}
+	return buckets, nil
+}
@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogram(t *testing.T) {
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	now := start
+	hl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(NewMemFS()), WithNow(func() time.Time {
+		return now
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 30; i++ {
+		now = start.Add(time.Duration(i) * time.Minute)
+		hl.Info("tick")
+		if i >= 20 {
+			hl.Error("failed")
+		}
+	}
+	buckets, err := hl.Histogram(start, start.Add(30*time.Minute), 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 3 {
+		t.Fatalf("expected 3 buckets, got %d", len(buckets))
+	}
+	if buckets[0].Counts[INFO] != 11 || buckets[0].Counts[ERROR] != 0 {
+		t.Errorf("unexpected first bucket %v", buckets[0].Counts)
+	}
+	if !buckets[2].Start.Equal(start.Add(20*time.Minute)) || buckets[2].Counts[INFO] != 10 || buckets[2].Counts[ERROR] != 10 {
+		t.Errorf("unexpected last bucket %v at %s", buckets[2].Counts, buckets[2].Start)
+	}
+	if _, err = hl.Histogram(start, start.Add(time.Hour), 0); err != errBucketSize {
+		t.Errorf("expected %v, got %v", errBucketSize, err)
+	}
+	if _, err = hl.Histogram(time.Time{}, start, time.Nanosecond); err != errTooManyBuckets {
+		t.Errorf("expected %v, got %v", errTooManyBuckets, err)
+	}
+}
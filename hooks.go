@@ -0,0 +1,40 @@
+package logging
+
+// PreWriteHook is called before an entry is redacted, reported or written. It may modify
+// the entry; returning false drops it
+type PreWriteHook func(e *Entry) bool
+
+// PostWriteHook is called after an entry has been written, with the record as written to the file
+type PostWriteHook func(e Entry, record string)
+
+// applyPreHooks runs the pre-write hooks on a copy of the entry, keeping the
+// entry on the stack when there are no hooks
+func (l *Log) applyPreHooks(e Entry) (Entry, bool) {
+	keep := l.runPreHooks(&e)
+	return e, keep
+}
+
+func (l *Log) runPreHooks(e *Entry) bool {
+	for _, hook := range l.preHooks {
+		if !hook(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *Log) runPostHooks(e Entry, record string) {
+	for _, hook := range l.postHooks {
+		hook(e, record)
+	}
+	l.subs.publish(e)
+}
+
+// levelCallback adapts a callback for one level to a post-write hook
+func levelCallback(level string, fn func(e Entry)) PostWriteHook {
+	return func(e Entry, record string) {
+		if e.Level == level {
+			fn(e)
+		}
+	}
+}
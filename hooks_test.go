@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	written := make([]string, 0)
+	hl, err := NewLog(
+		filepath.Join(t.TempDir(), "hooks.log"), "TEST", LEVEL_INFO, LEVEL_NONE,
+		WithPreWriteHook(func(e *Entry) bool {
+			return !strings.Contains(e.Message, "healthcheck")
+		}),
+		WithPreWriteHook(func(e *Entry) bool {
+			e.Fields = Fields{"region": "eu-west-1"}
+			return true
+		}),
+		WithPostWriteHook(func(e Entry, record string) {
+			written = append(written, record)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, _ := hl.Info("GET /healthcheck")
+	if result != "" {
+		t.Errorf("expected vetoed entry not to be written, got '%s'", result)
+	}
+	result, _ = hl.Info("GET /orders")
+	if !strings.HasSuffix(result, "GET /orders region=eu-west-1") {
+		t.Errorf("expected entry to be enriched by the hook, got '%s'", result)
+	}
+	if len(written) != 2 || written[1] != result {
+		t.Errorf("expected post-write hook to see the initialising entry and the written entry, got %v", written)
+	}
+}
+
+func TestOnLevel(t *testing.T) {
+	var errors, warnings int
+	ol, err := NewLog(
+		filepath.Join(t.TempDir(), "onlevel.log"), "TEST", LEVEL_INFO, LEVEL_NONE,
+		OnLevel(ERROR, func(e Entry) { errors++ }),
+		OnLevel(WARNING, func(e Entry) { warnings++ }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ol.Error("one")
+	ol.Error("two")
+	ol.Warning("three")
+	ol.Info("four")
+	if errors != 2 || warnings != 1 {
+		t.Errorf("expected 2 error and 1 warning callbacks, got %d and %d", errors, warnings)
+	}
+}
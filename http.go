@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RequestIDHeader is the header the access log reads the request ID from
+const RequestIDHeader = "X-Request-ID"
+
+// Middleware returns a handler logging every request through the log with its method, path,
+// status, latency, response size, remote address and request ID, along with the protocol and,
+// when present, the query, referer, user agent and basic auth user. Server errors are logged
+// at ERROR, client errors at WARNING and everything else at INFO. To write an access log in
+// the Apache/NCSA combined format, pass the middleware a log using CombinedLogEncoder
+func (l *Log) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := l.now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		attrs := []Attr{
+			String("method", r.Method),
+			String("path", r.URL.Path),
+			Int("status", status),
+			Duration("latency", l.now().Sub(start)),
+			Int64("bytes", rec.bytes),
+			String("remote_addr", r.RemoteAddr),
+			String("proto", r.Proto),
+		}
+		if r.URL.RawQuery != "" {
+			attrs = append(attrs, String("query", r.URL.RawQuery))
+		}
+		if referer := r.Referer(); referer != "" {
+			attrs = append(attrs, String("referer", referer))
+		}
+		if agent := r.UserAgent(); agent != "" {
+			attrs = append(attrs, String("user_agent", agent))
+		}
+		if user, _, ok := r.BasicAuth(); ok && user != "" {
+			attrs = append(attrs, String("user", user))
+		}
+		if id, ok := RequestIDFromContext(r.Context()); ok {
+			attrs = append(attrs, String("request_id", id))
+		} else if id := r.Header.Get(RequestIDHeader); id != "" {
+			attrs = append(attrs, String("request_id", id))
+		}
+		l.With(attrs...).Chain().write(fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, status), statusLevel(status))
+	})
+}
+
+func statusLevel(status int) string {
+	switch {
+	case status >= 500:
+		return ERROR
+	case status >= 400:
+		return WARNING
+	}
+	return INFO
+}
+
+// statusRecorder records the status and size of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush lets streaming handlers flush through the recorder
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
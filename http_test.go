@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	c := NewCapture(WithNow(steppingClock(25 * time.Millisecond)))
+	handler := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("hello"))
+	}))
+	req := httptest.NewRequest("GET", "/greet", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/fail", nil))
+	entries := c.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected two access entries, got %v", entries)
+	}
+	ok := entries[0]
+	if ok.Level != INFO || ok.Message != "GET /greet 200" {
+		t.Errorf("expected an info entry for the successful request, got %s", ok)
+	}
+	expected := map[string]interface{}{
+		"status":     int64(200),
+		"bytes":      int64(5),
+		"request_id": "req-1",
+		"latency":    25 * time.Millisecond,
+		"method":     "GET",
+	}
+	for key, value := range expected {
+		if v, _ := ok.Field(key); v != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, v)
+		}
+	}
+	if entries[1].Level != ERROR {
+		t.Errorf("expected a server error to be logged at error, got %s", entries[1].Level)
+	}
+}
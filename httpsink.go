@@ -0,0 +1,374 @@
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultHTTPBatchSize   = 100
+	defaultHTTPBatchAge    = 5 * time.Second
+	defaultHTTPMaxBuffered = 10000
+)
+
+// HTTPSinkOptions configures an HTTPSink
+type HTTPSinkOptions struct {
+	// Client sends the requests, http.DefaultClient if nil; see NewHTTPClient for TLS and proxies
+	Client *http.Client
+	// Encoder formats each entry as a line of the request body (default JSONEncoder, giving
+	// newline-delimited JSON). Line breaks within a record are escaped as \n and \r, so that
+	// each entry stays one line
+	Encoder Encoder
+	// ContentType is the type of the uncompressed body (default application/x-ndjson)
+	ContentType string
+	// Header is added to every request, e.g. for an API key
+	Header http.Header
+	// BatchSize is the number of entries sent in a request (default 100)
+	BatchSize int
+	// BatchAge is how long an entry waits for its batch to fill before it is sent anyway
+	// (default 5 seconds)
+	BatchAge time.Duration
+	// MaxBuffered caps the entries waiting to be sent, the oldest batch being dropped when it
+	// is exceeded while the endpoint is failing (default 10000). It does not apply with QueueDir
+	MaxBuffered int
+	// QueueDir, if set, keeps the entries waiting to be sent in segment files in the directory,
+	// with the position reached in a cursor file, so that they survive restarts
+	QueueDir string
+	// QueueMaxBytes caps the size of the queue in QueueDir, the oldest segment being dropped
+	// when it is exceeded (default 64MB)
+	QueueMaxBytes int64
+	// FileMode is the mode of the files in QueueDir (default 0666)
+	FileMode os.FileMode
+	// FS is the filesystem QueueDir is on (default the operating system's)
+	FS FS
+	// Uncompressed sends the bodies as they are instead of compressed with gzip
+	Uncompressed bool
+	// Retry, if set, retries a request that failed before it is left for the next attempt
+	// to send the batch, which is made every BatchAge/2
+	Retry *RetryPolicy
+	// Ack, if set, reads the acknowledgement of a batch of n entries from a successful
+	// response, returning how many of the entries, from the first, the endpoint accepted.
+	// Those not accepted are sent again. By default a 2xx status accepts the whole batch
+	Ack func(resp *http.Response, n int) (int, error)
+}
+
+// HTTPSink sends entries to an HTTP endpoint in batches, each POSTed as one request whose
+// body is compressed with gzip. Entries are sent in the background, so WriteEntry does not
+// wait on the network. Entries are only removed from the queue once the endpoint acknowledges
+// them, so a batch that fails is kept and retried and entries are delivered at least once.
+// With QueueDir the entries waiting survive restarts. ERROR and more severe entries are not
+// dropped when the queue is full, though they may then be sent after later entries, until
+// they take the queue to twice its cap; see DroppedErrors
+type HTTPSink struct {
+	url     string
+	opts    HTTPSinkOptions
+	mu      sync.Mutex
+	queue   shipQueue
+	times   []time.Time // when the entries from timesAt on were queued
+	timesAt uint64      // entries before it were queued before the sink was opened
+	lastErr error       // of the last request, nil once one succeeds
+	dropped atomic.Uint64
+	// droppedErrors counts the entries dropped that were kept; see DroppedErrors
+	droppedErrors atomic.Uint64
+	sending       sync.Mutex
+	kick          chan struct{}
+	stop          chan struct{}
+	cancel        context.CancelFunc // cancels the requests and retries of the background flush
+	done          chan struct{}
+}
+
+// NewHTTPSink returns an HTTPSink POSTing to url. With QueueDir, entries left in the queue by
+// an earlier HTTPSink are sent first
+func NewHTTPSink(url string, opts HTTPSinkOptions) (*HTTPSink, error) {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.Encoder == nil {
+		opts.Encoder = JSONEncoder{}
+	}
+	if opts.ContentType == "" {
+		opts.ContentType = "application/x-ndjson"
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = defaultHTTPBatchSize
+	}
+	if opts.BatchAge <= 0 {
+		opts.BatchAge = defaultHTTPBatchAge
+	}
+	if opts.MaxBuffered < opts.BatchSize {
+		opts.MaxBuffered = defaultHTTPMaxBuffered
+	}
+	if opts.QueueMaxBytes <= 0 {
+		opts.QueueMaxBytes = defaultSpillMaxBytes
+	}
+	if opts.FileMode == 0 {
+		opts.FileMode = defaultFileMode
+	}
+	var queue shipQueue = &memQueue{max: opts.MaxBuffered}
+	if opts.QueueDir != "" {
+		q, err := openDiskQueue(opts.FS, opts.QueueDir, opts.QueueMaxBytes, opts.FileMode)
+		if err != nil {
+			return nil, err
+		}
+		queue = q
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &HTTPSink{
+		cancel:  cancel,
+		url:     url,
+		opts:    opts,
+		queue:   queue,
+		timesAt: queue.tail(),
+		kick:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.run(ctx)
+	if queue.tail() > queue.cursor() {
+		s.wake()
+	}
+	return s, nil
+}
+
+// WriteEntry queues the entry, to be sent once a batch is full or it has waited for BatchAge.
+// An error is only returned if it cannot be written to the queue on disk
+func (s *HTTPSink) WriteEntry(e Entry) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	*buf = append(oneLine(s.opts.Encoder.AppendEntry((*buf)[:0], e)), '\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	severity, ok := Severity(e.Level)
+	dropped, droppedKept, err := s.queue.push(*buf, ok && severity <= syslogSeverities[ERROR])
+	if dropped+droppedKept > 0 {
+		s.dropped.Add(uint64(dropped + droppedKept))
+		s.droppedErrors.Add(uint64(droppedKept))
+	}
+	if err != nil {
+		return err
+	}
+	s.times = append(s.times, time.Now())
+	s.trimTimes()
+	if s.queue.tail()-s.queue.cursor() >= uint64(s.opts.BatchSize) {
+		s.wake()
+	}
+	return nil
+}
+
+// trimTimes drops the times of entries no longer queued. It is called with the lock held
+func (s *HTTPSink) trimTimes() {
+	if cursor := s.queue.cursor(); cursor > s.timesAt {
+		n := cursor - s.timesAt
+		if n > uint64(len(s.times)) {
+			n = uint64(len(s.times))
+		}
+		s.times, s.timesAt = s.times[n:], cursor
+	}
+}
+
+// oldest returns when the oldest entry queued was queued, or the zero time if it was queued
+// before the sink was opened. It is called with the lock held
+func (s *HTTPSink) oldest() time.Time {
+	if s.queue.cursor() < s.timesAt || len(s.times) == 0 {
+		return time.Time{}
+	}
+	return s.times[0]
+}
+
+func (s *HTTPSink) wake() {
+	select {
+	case s.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (s *HTTPSink) run(ctx context.Context) {
+	defer close(s.done)
+	ticker := time.NewTicker(s.opts.BatchAge / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.kick:
+		case <-ticker.C:
+		case <-s.stop:
+			return
+		}
+		s.flush(ctx, false)
+	}
+}
+
+// flush sends batches while one is full or its oldest entry has waited for BatchAge, or every
+// entry queued if all is set, until a request fails or is only partly acknowledged or ctx is
+// done. It returns the number of entries acknowledged
+func (s *HTTPSink) flush(ctx context.Context, all bool) (int, error) {
+	s.sending.Lock()
+	defer s.sending.Unlock()
+	sent := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+		s.mu.Lock()
+		waiting := s.queue.tail() - s.queue.cursor()
+		ready := waiting > 0 && (all || waiting >= uint64(s.opts.BatchSize) || time.Since(s.oldest()) >= s.opts.BatchAge)
+		var body []byte
+		var n int
+		var end queuePos
+		var err error
+		start := s.queue.cursor()
+		if ready {
+			body, n, end, err = s.queue.peek(s.opts.BatchSize)
+		}
+		s.mu.Unlock()
+		if !ready || n == 0 || err != nil {
+			return sent, err
+		}
+		var acked int
+		err = s.opts.Retry.doContext(ctx, func() (err error) {
+			acked, err = s.send(ctx, body, n)
+			return err
+		})
+		if err == nil && acked < n {
+			err = fmt.Errorf("%s acknowledged %d of %d entries", s.url, acked, n)
+		}
+		s.mu.Lock()
+		s.lastErr = err
+		var cerr error
+		// the position after the entries acknowledged is only known while none were dropped
+		// during the request; otherwise the whole batch is sent again
+		if acked == n || (acked > 0 && s.queue.cursor() == start) {
+			if acked < n {
+				_, _, end, cerr = s.queue.peek(acked)
+			}
+			if cerr == nil {
+				cerr = s.queue.commit(end)
+				s.trimTimes()
+			}
+		}
+		s.mu.Unlock()
+		if cerr != nil {
+			return sent, cerr
+		}
+		sent += acked
+		if err != nil {
+			return sent, err
+		}
+	}
+}
+
+// send POSTs a batch of n entries, returning how many were acknowledged
+func (s *HTTPSink) send(ctx context.Context, body []byte, n int) (int, error) {
+	if !s.opts.Uncompressed {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(body)
+		if err := zw.Close(); err != nil {
+			return 0, err
+		}
+		body = buf.Bytes()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	for key, values := range s.opts.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", s.opts.ContentType)
+	if !s.opts.Uncompressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return 0, fmt.Errorf("sending %d entries to %s failed: %s: %s", n, s.url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	defer io.Copy(io.Discard, resp.Body)
+	if s.opts.Ack == nil {
+		return n, nil
+	}
+	acked, err := s.opts.Ack(resp, n)
+	if acked < 0 || acked > n {
+		acked = 0
+	}
+	return acked, err
+}
+
+// Dropped returns the number of entries dropped because the queue was full; see MaxBuffered
+// and QueueMaxBytes
+func (s *HTTPSink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// DroppedErrors returns the number of the entries dropped that were ERROR or more severe,
+// which are only dropped once the queue is twice its cap
+func (s *HTTPSink) DroppedErrors() uint64 {
+	return s.droppedErrors.Load()
+}
+
+// QueueStats returns the number of entries waiting to be sent, the age of the oldest and the
+// number dropped
+func (s *HTTPSink) QueueStats() QueueStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := QueueStats{Depth: int64(s.queue.tail() - s.queue.cursor()), Dropped: s.dropped.Load(), DroppedErrors: s.droppedErrors.Load()}
+	if oldest := s.oldest(); stats.Depth > 0 && !oldest.IsZero() {
+		stats.OldestAge = time.Since(oldest)
+	}
+	return stats
+}
+
+// Check returns the error of the last request if it failed, so that Healthy reports an
+// endpoint that is down
+func (s *HTTPSink) Check() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+// Drain sends every waiting entry, stopping at the first request that fails or when ctx is
+// done. Entries left are counted as dropped
+func (s *HTTPSink) Drain(ctx context.Context) (flushed, dropped int, err error) {
+	type result struct {
+		flushed int
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		flushed, err := s.flush(ctx, true)
+		done <- result{flushed, err}
+	}()
+	select {
+	case r := <-done:
+		flushed, err = r.flushed, r.err
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	return flushed, int(s.QueueStats().Depth), err
+}
+
+// Close stops sending in the background and closes the queue. Call Drain first to send the
+// entries waiting; with QueueDir, those left are sent by the next HTTPSink using it
+func (s *HTTPSink) Close() error {
+	s.cancel()
+	close(s.stop)
+	<-s.done
+	s.sending.Lock()
+	defer s.sending.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queue.close()
+}
@@ -0,0 +1,206 @@
+package logging
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector records the entries POSTed to it, failing requests while down is set
+type collector struct {
+	mu       sync.Mutex
+	down     bool
+	requests int
+	messages []string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests++
+	if c.down {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if r.Header.Get("Content-Encoding") != "gzip" || r.Header.Get("Authorization") != "Bearer key" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	zr, err := gzip.NewReader(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		e, err := ParseEntry(scanner.Text())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.messages = append(c.messages, e.Message)
+	}
+}
+
+func (c *collector) received() (int, []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests, append([]string(nil), c.messages...)
+}
+
+func TestHTTPSink(t *testing.T) {
+	c := &collector{down: true}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	sink, err := NewHTTPSink(server.URL, HTTPSinkOptions{
+		Header:    http.Header{"Authorization": {"Bearer key"}},
+		BatchSize: 2,
+		BatchAge:  time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	for _, message := range []string{"one", "two", "three"} {
+		sink.WriteEntry(Entry{Time: time.Now(), Level: INFO, Message: message})
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for sink.Check() == nil && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if sink.Check() == nil || sink.QueueStats().Depth != 3 {
+		t.Fatalf("expected the failed batch to be kept, got %+v", sink.QueueStats())
+	}
+	c.mu.Lock()
+	c.down = false
+	c.mu.Unlock()
+	flushed, dropped, err := sink.Drain(context.Background())
+	if err != nil || flushed != 3 || dropped != 0 {
+		t.Fatalf("expected every entry to be sent, got %d, %d and %v", flushed, dropped, err)
+	}
+	if _, messages := c.received(); len(messages) != 3 || messages[0] != "one" || messages[2] != "three" {
+		t.Errorf("expected the entries in order, got %v", messages)
+	}
+	if sink.Check() != nil {
+		t.Errorf("expected the sink to recover, got %v", sink.Check())
+	}
+}
+
+func TestHTTPSinkQueueDir(t *testing.T) {
+	c := &collector{down: true}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	opts := HTTPSinkOptions{Header: http.Header{"Authorization": {"Bearer key"}}, BatchSize: 2, QueueDir: t.TempDir()}
+	sink, err := NewHTTPSink(server.URL, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, message := range []string{"one", "two", "three"} {
+		sink.WriteEntry(Entry{Time: time.Now(), Level: INFO, Message: message})
+	}
+	if _, _, err = sink.Drain(context.Background()); err == nil {
+		t.Fatal("expected the collector to be down")
+	}
+	sink.Close()
+
+	c.mu.Lock()
+	c.down = false
+	c.mu.Unlock()
+	// a restarted sink sends the entries left in the queue
+	sink, err = NewHTTPSink(server.URL, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if depth := sink.QueueStats().Depth; depth != 3 {
+		t.Fatalf("expected 3 entries to survive the restart, got %d", depth)
+	}
+	sink.WriteEntry(Entry{Time: time.Now(), Level: INFO, Message: "four"})
+	if _, dropped, err := sink.Drain(context.Background()); err != nil || dropped != 0 {
+		t.Fatalf("expected every entry to be sent, got %d left and %v", dropped, err)
+	}
+	sink.Close()
+	if _, messages := c.received(); strings.Join(messages, ",") != "one,two,three,four" {
+		t.Errorf("expected the entries in order, got %v", messages)
+	}
+
+	sink, err = NewHTTPSink(server.URL, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	if depth := sink.QueueStats().Depth; depth != 0 {
+		t.Errorf("expected the cursor to be kept, got %d entries waiting", depth)
+	}
+}
+
+func TestHTTPSinkAck(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	// the endpoint accepts one entry of each request
+	ack := func(resp *http.Response, n int) (int, error) {
+		return 1, nil
+	}
+	sink, err := NewHTTPSink(server.URL, HTTPSinkOptions{
+		Header:    http.Header{"Authorization": {"Bearer key"}},
+		BatchSize: 4,
+		BatchAge:  time.Hour,
+		Ack:       ack,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	for _, message := range []string{"one", "two", "three"} {
+		sink.WriteEntry(Entry{Time: time.Now(), Level: INFO, Message: message})
+	}
+	flushed, dropped, err := sink.Drain(context.Background())
+	if err == nil || flushed != 1 || dropped != 2 {
+		t.Fatalf("expected one entry to be acknowledged, got %d, %d and %v", flushed, dropped, err)
+	}
+	for i := 0; i < 2; i++ {
+		sink.Drain(context.Background())
+	}
+	if depth := sink.QueueStats().Depth; depth != 0 {
+		t.Errorf("expected every entry to be acknowledged, got %d waiting", depth)
+	}
+	if _, messages := c.received(); strings.Join(messages, ",") != "one,two,three,two,three,three" {
+		t.Errorf("expected the entries not acknowledged to be sent again, got %v", messages)
+	}
+}
+
+func TestHTTPSinkKeepsErrors(t *testing.T) {
+	c := &collector{down: true}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	sink, err := NewHTTPSink(server.URL, HTTPSinkOptions{
+		Header:      http.Header{"Authorization": {"Bearer key"}},
+		BatchSize:   2,
+		BatchAge:    time.Hour,
+		MaxBuffered: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	sink.WriteEntry(Entry{Time: time.Now(), Level: ERROR, Message: "failed"})
+	for _, message := range []string{"one", "two", "three", "four"} {
+		sink.WriteEntry(Entry{Time: time.Now(), Level: INFO, Message: message})
+	}
+	c.mu.Lock()
+	c.down = false
+	c.mu.Unlock()
+	sink.Drain(context.Background())
+	if _, messages := c.received(); strings.Join(messages, ",") != "three,failed,four" {
+		t.Errorf("expected the error to be kept, got %v", messages)
+	}
+	if sink.Dropped() != 2 || sink.DroppedErrors() != 0 {
+		t.Errorf("expected 2 entries to be dropped and no errors, got %d and %d", sink.Dropped(), sink.DroppedErrors())
+	}
+}
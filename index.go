@@ -0,0 +1,240 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// indexSuffix is appended to the hidden sidecar file holding a log's sparse index
+const indexSuffix = ".idx"
+
+// sparseIndex records the offset and time of every nth record written; see WithIndex
+type sparseIndex struct {
+	every int
+	count int
+}
+
+type indexEntry struct {
+	offset int64
+	time   time.Time
+}
+
+// indexPath returns the path of the sidecar index, .<name>.idx beside the log file
+func (l *Log) indexPath() string {
+	dir, name := filepath.Split(l.path)
+	return filepath.Join(dir, "."+name+indexSuffix)
+}
+
+// indexOffset returns the offset the next record will be written at, or -1 if the log is
+// not indexed. It is called with the write lock held
+func (l *Log) indexOffset() int64 {
+	if l.index == nil || l.cipher != nil {
+		return -1
+	}
+	file, err := l.outputFile()
+	if err != nil {
+		return -1
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return -1
+	}
+	return info.Size()
+}
+
+// indexRecords adds the records written at offset to the index. It is called with the
+// write lock held
+func (l *Log) indexRecords(offset int64, msgs ...[]byte) {
+	if offset < 0 {
+		return
+	}
+	var buf []byte
+	for _, msg := range msgs {
+		if l.index.count%l.index.every == 0 {
+			if t, ok := recordTime(msg); ok {
+				buf = fmt.Appendf(buf, "%d %d\n", offset, t.UnixNano())
+			}
+		}
+		l.index.count++
+		offset += int64(len(msg) + len(l.lineEnd()))
+	}
+	if len(buf) == 0 {
+		return
+	}
+	mode := l.fileMode
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+	file, err := l.getFS().OpenFile(l.indexPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, mode)
+	if err != nil {
+		l.handleError(err)
+		return
+	}
+	defer file.Close()
+	if _, err = file.Write(buf); err != nil {
+		l.handleError(err)
+	}
+}
+
+// resetIndex discards the index after the log file is rotated. It is called with the
+// write lock held
+func (l *Log) resetIndex() {
+	if l.index == nil {
+		return
+	}
+	l.index.count = 0
+	if err := l.getFS().Remove(l.indexPath()); err != nil && !os.IsNotExist(err) {
+		l.handleError(err)
+	}
+}
+
+// loadIndex reads the index, skipping entries beyond the end of the file
+func (l *Log) loadIndex(size int64) []indexEntry {
+	b, err := l.readFile(l.indexPath())
+	if err != nil {
+		return nil
+	}
+	entries := make([]indexEntry, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		var offset, nanos int64
+		if _, err = fmt.Sscanf(scanner.Text(), "%d %d", &offset, &nanos); err != nil || offset >= size {
+			continue
+		}
+		entries = append(entries, indexEntry{offset: offset, time: time.Unix(0, nanos).UTC()})
+	}
+	return entries
+}
+
+// indexed reports whether the record at an index entry's offset is the one indexed, which
+// is not so if the file has been replaced or truncated since
+func indexed(file io.ReaderAt, e indexEntry) bool {
+	b := make([]byte, 64)
+	n, _ := file.ReadAt(b, e.offset)
+	t, ok := recordTime(b[:n])
+	return ok && t.Equal(e.time)
+}
+
+// recordTime reads the time at the start of a record in the text or JSON format, or parses
+// a record in another format to read its time
+func recordTime(record []byte) (time.Time, bool) {
+	var stamp []byte
+	switch {
+	case bytes.HasPrefix(record, []byte("[")):
+		stamp = record[1:]
+		if i := bytes.IndexByte(stamp, ']'); i >= 0 {
+			stamp = stamp[:i]
+		}
+	case bytes.HasPrefix(record, []byte(`{"time":"`)):
+		stamp = record[len(`{"time":"`):]
+		if i := bytes.IndexByte(stamp, '"'); i >= 0 {
+			stamp = stamp[:i]
+		}
+	default:
+		// the other formats are read in full
+		e, err := ParseEntry(string(record))
+		return e.Time, err == nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, string(stamp))
+	return t, err == nil
+}
+
+// indexedTail returns the last lines records, newest first as GetLog does, reading from the
+// index entry closest to the end that still leaves enough records. It returns false if the
+// index cannot be used
+func (l *Log) indexedTail(lines int, size int64) ([]string, bool) {
+	if l.index == nil || lines <= 0 {
+		return nil, false
+	}
+	entries := l.loadIndex(size)
+	j := len(entries) - 1 - (lines+l.index.every-1)/l.index.every
+	if j < 0 || !indexed(l.file, entries[j]) {
+		return nil, false
+	}
+	records := make([]string, 0, lines)
+	scanner := newRecordScanner(io.NewSectionReader(l.file, entries[j].offset, size-entries[j].offset))
+	for {
+		record, err := scanner.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false
+		}
+		records = append(records, strings.Trim(record, " "))
+	}
+	if len(records) < lines {
+		return nil, false
+	}
+	records = records[len(records)-lines:]
+	l.reverseNode(&records)
+	return records, true
+}
+
+// ReadRange returns the records written from from up to but not including to, oldest first.
+// With an index (see WithIndex) reading starts from the last indexed record before from
+// instead of the start of the file. Records are assumed to be in chronological order.
+// The file is memory mapped if set by WithMmap
+func (l *Log) ReadRange(from, to time.Time) ([]string, error) {
+	result := make([]string, 0)
+	if l.path == "" {
+		return result, nil
+	}
+	file, err := l.getFS().OpenFile(l.path, os.O_RDONLY, 0)
+	if err != nil {
+		return result, err
+	}
+	defer file.Close()
+	size, err := l.committedSize(file)
+	if err != nil {
+		return result, err
+	}
+	var offset int64
+	if l.index != nil {
+		var start indexEntry
+		for _, e := range l.loadIndex(size) {
+			if !e.time.Before(from) {
+				break
+			}
+			start = e
+		}
+		if start.offset > 0 && indexed(file, start) {
+			offset = start.offset
+		}
+	}
+	var r io.Reader = io.NewSectionReader(file, offset, size-offset)
+	if l.mmap {
+		if mr, unmap, ok := l.mappedReader(file, offset, size); ok {
+			defer unmap()
+			r = mr
+		}
+	}
+	scanner := newRecordScanner(r)
+	for {
+		record, err := scanner.next()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+		t, ok := recordTime([]byte(record))
+		if !ok {
+			l.handleError(fmt.Errorf("read range: unreadable record skipped: %w", ErrMalformedRecord))
+			continue
+		}
+		if t.Before(from) {
+			continue
+		}
+		if !t.Before(to) {
+			return result, nil
+		}
+		result = append(result, record)
+	}
+}
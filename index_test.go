@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func spinIndexedLog(t *testing.T, fs *MemFS, start time.Time) *Log {
+	now := start
+	il, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithIndex(10), WithNow(func() time.Time {
+		return now
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < 100; i++ {
+		now = start.Add(time.Duration(i) * time.Second)
+		il.Infof("entry %d", i)
+	}
+	return il
+}
+
+func TestIndex(t *testing.T) {
+	fs := NewMemFS()
+	il := spinIndexedLog(t, fs, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	b, err := il.readFile("/logs/.app.log.idx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(b), "\n"); lines != 10 {
+		t.Errorf("expected 10 index entries, got %d", lines)
+	}
+	tail, err := il.GetLog(15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unindexed := *il
+	unindexed.index = nil
+	scanned, _ := unindexed.GetLog(15)
+	// the scanned tail keeps the file's final newline
+	scanned[0] = strings.TrimSuffix(scanned[0], "\n")
+	if fmt.Sprint(tail) != fmt.Sprint(scanned) {
+		t.Errorf("expected the indexed tail to match the scanned tail, got %v and %v", tail, scanned)
+	}
+	if len(tail) != 15 || !strings.HasSuffix(tail[0], "entry 99") {
+		t.Errorf("unexpected tail %v", tail)
+	}
+	if _, err = il.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fs.Stat("/logs/.app.log.idx"); err == nil {
+		t.Error("expected the index to be removed on rotation")
+	}
+}
+
+func TestReadRange(t *testing.T) {
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	il := spinIndexedLog(t, NewMemFS(), start)
+	records, err := il.ReadRange(start.Add(45*time.Second), start.Add(55*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 10 || !strings.HasSuffix(records[0], "entry 45") || !strings.HasSuffix(records[9], "entry 54") {
+		t.Errorf("unexpected records %v", records)
+	}
+}
+
+func TestIndexReplacedFile(t *testing.T) {
+	fs := NewMemFS()
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	il := spinIndexedLog(t, fs, start)
+	fs.Remove("/logs/app.log")
+	il.writer.close()
+	for i := 0; i < 20; i++ {
+		il.Infof("replacement %d", i)
+	}
+	records, err := il.ReadRange(start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 20 || !strings.HasSuffix(records[0], "replacement 0") {
+		t.Errorf("expected a stale index to be ignored, got %d records", len(records))
+	}
+}
+
+func TestIndexFileMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if _, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithIndex(1), WithFileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(filepath.Dir(path), ".app.log"+indexSuffix))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected the index to be created with the log's mode, got %v and %v", info, err)
+	}
+}
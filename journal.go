@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// journalStderr is the destination of reports when stderr is connected to the systemd journal
+var journalStderr = NewJournalReporter(os.Stderr)
+
+// NewJournalReporter returns a reporter writing each record to w with a "<N>" prefix giving
+// the syslog severity of its level, which journald reads as the priority of the line. Custom
+// levels are reported as INFO. When no reporter is set and stderr is connected to the journal,
+// as systemd announces with JOURNAL_STREAM, reports are written to stderr this way instead of
+// through the standard library logger
+func NewJournalReporter(w io.Writer) Reporter {
+	r := &writerReporter{w: w}
+	return ReporterFunc(func(e Entry, msg []byte) {
+		severity, ok := Severity(e.Level)
+		if !ok {
+			severity = syslogSeverities[INFO]
+		}
+		buf := getBuffer()
+		defer putBuffer(buf)
+		*buf = append(strconv.AppendInt(append((*buf)[:0], '<'), int64(severity), 10), '>')
+		*buf = append(*buf, msg...)
+		r.Report(e, *buf)
+	})
+}
+
+// stderrIsJournal reports whether stderr is the stream systemd connected to the journal,
+// by comparing its device and inode with those in JOURNAL_STREAM
+func stderrIsJournal() bool {
+	stream := os.Getenv("JOURNAL_STREAM")
+	if stream == "" {
+		return false
+	}
+	dev, ino, ok := fileID(os.Stderr)
+	return ok && stream == strconv.FormatUint(dev, 10)+":"+strconv.FormatUint(ino, 10)
+}
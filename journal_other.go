@@ -0,0 +1,10 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package logging
+
+import "os"
+
+// fileID is unavailable where there is no journald
+func fileID(f *os.File) (dev, ino uint64, ok bool) {
+	return 0, 0, false
+}
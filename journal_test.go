@@ -0,0 +1,30 @@
+package logging
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestJournalReporter(t *testing.T) {
+	var out bytes.Buffer
+	c := NewCapture(WithReporter(NewJournalReporter(&out)), WithReportEncoder(ConsoleEncoder{NoColor: true}))
+	c.reportLevel = LEVEL_INFO
+	c.Error("failed")
+	c.Write("custom", "AUDIT")
+	lines := bytes.Split(bytes.TrimSuffix(out.Bytes(), []byte("\n")), []byte("\n"))
+	if len(lines) != 2 || !bytes.HasPrefix(lines[0], []byte("<3>")) || !bytes.HasPrefix(lines[1], []byte("<6>")) {
+		t.Errorf("expected journald priority prefixes, got %q", out.String())
+	}
+	t.Setenv("JOURNAL_STREAM", "")
+	if stderrIsJournal() {
+		t.Error("expected stderr not to be the journal without JOURNAL_STREAM")
+	}
+	if dev, ino, ok := fileID(os.Stderr); ok {
+		t.Setenv("JOURNAL_STREAM", strconv.FormatUint(dev, 10)+":"+strconv.FormatUint(ino, 10))
+		if !stderrIsJournal() {
+			t.Error("expected stderr to be the journal when JOURNAL_STREAM names it")
+		}
+	}
+}
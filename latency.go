@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencySamples is the number of recent operations latency percentiles are computed over
+const latencySamples = 1024
+
+// LatencyStats summarises how long an operation has taken; see WithLatencyStats
+type LatencyStats struct {
+	Count    uint64        // operations recorded
+	Sum      time.Duration // total time of the operations recorded
+	P50, P95 time.Duration // over the most recent 1024 operations
+	Max      time.Duration // of every operation recorded
+}
+
+type latencyRecorder struct {
+	mu      sync.Mutex
+	samples [latencySamples]time.Duration
+	count   uint64
+	sum     time.Duration
+	max     time.Duration
+}
+
+// latencies holds the write and flush latencies of a log, shared with child logs
+type latencies struct {
+	write, flush latencyRecorder
+}
+
+func (r *latencyRecorder) observe(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples[r.count%latencySamples] = d
+	r.count++
+	r.sum += d
+	if d > r.max {
+		r.max = d
+	}
+}
+
+func (r *latencyRecorder) stats() LatencyStats {
+	r.mu.Lock()
+	n := r.count
+	if n > latencySamples {
+		n = latencySamples
+	}
+	samples := append([]time.Duration(nil), r.samples[:n]...)
+	stats := LatencyStats{Count: r.count, Sum: r.sum, Max: r.max}
+	r.mu.Unlock()
+	if len(samples) == 0 {
+		return stats
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	stats.P50 = samples[(len(samples)-1)*50/100]
+	stats.P95 = samples[(len(samples)-1)*95/100]
+	return stats
+}
+
+// observeWrite records the time taken to deliver an entry since start
+func (l *Log) observeWrite(start time.Time) {
+	if l.latency != nil {
+		l.latency.write.observe(time.Since(start))
+	}
+}
+
+// observeFlush records the time taken to sync the file since start
+func (l *Log) observeFlush(start time.Time) {
+	if l.latency != nil {
+		l.latency.flush.observe(time.Since(start))
+	}
+}
@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLatencyStats(t *testing.T) {
+	var r latencyRecorder
+	for i := 1; i <= 100; i++ {
+		r.observe(time.Duration(i) * time.Millisecond)
+	}
+	stats := r.stats()
+	if stats.Count != 100 || stats.P50 != 50*time.Millisecond || stats.P95 != 95*time.Millisecond || stats.Max != 100*time.Millisecond {
+		t.Errorf("unexpected latency stats %+v", stats)
+	}
+	ll, err := NewLog(filepath.Join(t.TempDir(), "latency.log"), "TEST", LEVEL_INFO, LEVEL_NONE, WithLatencyStats(), WithSyncEveryWrite())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ll.Info("timed")
+	if s := ll.Stats(); s.Write.Count != 2 || s.Flush.Count != 2 || s.Write.Max <= 0 {
+		t.Errorf("expected both writes and flushes to be recorded, got %+v and %+v", s.Write, s.Flush)
+	}
+	rec := httptest.NewRecorder()
+	ll.MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, metric := range []string{"logging_write_latency_seconds_count 2", `logging_flush_latency_seconds{quantile="0.95"}`, `logging_queue_depth{queue="async"} 0`} {
+		if !strings.Contains(body, metric) {
+			t.Errorf("expected the metrics to include %s, got %s", metric, body)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package logging
+
+// WriteFunc writes the message returned by fn, only calling it if the level is enabled; see Enabled
+func (l *Log) WriteFunc(fn func() string, level string) (string, error) {
+	if !l.Enabled(level) {
+		return "", nil
+	}
+	return l.Write(fn(), level)
+}
+
+func (l *Log) ErrorFunc(fn func() string) (string, error) {
+	return l.WriteFunc(fn, ERROR)
+}
+
+func (l *Log) SuccessFunc(fn func() string) (string, error) {
+	return l.WriteFunc(fn, SUCCESS)
+}
+
+func (l *Log) WarningFunc(fn func() string) (string, error) {
+	return l.WriteFunc(fn, WARNING)
+}
+
+func (l *Log) DebugFunc(fn func() string) (string, error) {
+	return l.WriteFunc(fn, DEBUG)
+}
+
+func (l *Log) InfoFunc(fn func() string) (string, error) {
+	return l.WriteFunc(fn, INFO)
+}
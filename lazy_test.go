@@ -0,0 +1,29 @@
+package logging
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLazyMessages(t *testing.T) {
+	ll, err := NewLog(filepath.Join(t.TempDir(), "lazy.log"), "TEST", LEVEL_WARNING, LEVEL_NONE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	expensive := func() string {
+		calls++
+		return "expensive state dump"
+	}
+	if result, _ := ll.DebugFunc(expensive); result != "" {
+		t.Errorf("expected filtered entry not to be written, got '%s'", result)
+	}
+	if calls != 0 {
+		t.Errorf("expected the message not to be built for a disabled level, got %d calls", calls)
+	}
+	result, _ := ll.ErrorFunc(expensive)
+	if calls != 1 || !strings.HasSuffix(result, "expensive state dump") {
+		t.Errorf("expected the message to be built once and written, got %d calls and '%s'", calls, result)
+	}
+}
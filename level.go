@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is a log or report level. It marshals to and from its name, so levels can be read
+// from JSON or YAML configuration and used with the flag package
+type Level int
+
+// The levels as Level values; they equal the LEVEL_ constants
+const (
+	LevelNone    Level = LEVEL_NONE
+	LevelError   Level = LEVEL_ERROR
+	LevelWarning Level = LEVEL_WARNING
+	LevelDebug   Level = LEVEL_DEBUG
+	LevelInfo    Level = LEVEL_INFO
+	// the syslog levels, which write entries at least as severe in either ordering
+	LevelNotice    Level = LEVEL_NOTICE
+	LevelCritical  Level = LEVEL_CRITICAL
+	LevelAlert     Level = LEVEL_ALERT
+	LevelEmergency Level = LEVEL_EMERGENCY
+)
+
+// levelNames maps the levels back to their names
+var levelNames = map[Level]string{
+	LevelNone:      NONE,
+	LevelError:     ERROR,
+	LevelWarning:   WARNING,
+	LevelDebug:     DEBUG,
+	LevelInfo:      INFO,
+	LevelNotice:    NOTICE,
+	LevelCritical:  CRITICAL,
+	LevelAlert:     ALERT,
+	LevelEmergency: EMERGENCY,
+}
+
+// ParseLevel returns the level with the given name (case insensitive). Unlike LogLevel,
+// it returns an error for unrecognised names
+func ParseLevel(name string) (Level, error) {
+	key := strings.ToUpper(strings.TrimSpace(name))
+	level, ok := logLevels[key]
+	if !ok {
+		level, ok = syslogThresholds[key]
+	}
+	if !ok {
+		return LevelNone, fmt.Errorf("unknown log level '%s'", name)
+	}
+	return Level(level), nil
+}
+
+func (lv Level) String() string {
+	if name, ok := levelNames[lv]; ok {
+		return name
+	}
+	return fmt.Sprintf("Level(%d)", int(lv))
+}
+
+func (lv Level) MarshalText() ([]byte, error) {
+	if _, ok := levelNames[lv]; !ok {
+		return nil, fmt.Errorf("invalid log level %d", int(lv))
+	}
+	return []byte(lv.String()), nil
+}
+
+func (lv *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*lv = level
+	return nil
+}
+
+// Set parses the level from a flag value, implementing flag.Value
+func (lv *Level) Set(name string) error {
+	return lv.UnmarshalText([]byte(name))
+}
+
+// Level returns the log's level
+func (l *Log) Level() Level {
+	return Level(l.levels.lookup(""))
+}
+
+// SetLevel changes the log level of the log and every log sharing its configuration
+func (l *Log) SetLevel(level Level) {
+	l.levels.mu.Lock()
+	defer l.levels.mu.Unlock()
+	l.levels.level = l.clampLevel(int(level))
+}
+
+// ReportLevel returns the log's report level
+func (l *Log) ReportLevel() Level {
+	return Level(l.reportLevel)
+}
+
+// syslogLevels are the legacy thresholds at which the syslog levels are written: the levels
+// more severe than ERROR are written whenever errors are, and notices whenever warnings are
+var syslogLevels = map[string]int{
+	EMERGENCY: LEVEL_ERROR,
+	ALERT:     LEVEL_ERROR,
+	CRITICAL:  LEVEL_ERROR,
+	NOTICE:    LEVEL_WARNING,
+}
+
+// syslogThresholds are the log and report levels of the syslog levels
+var syslogThresholds = map[string]int{
+	EMERGENCY: LEVEL_EMERGENCY,
+	ALERT:     LEVEL_ALERT,
+	CRITICAL:  LEVEL_CRITICAL,
+	NOTICE:    LEVEL_NOTICE,
+}
+
+// syslogSeverities are the RFC 5424 severities of the levels
+var syslogSeverities = map[string]int{
+	EMERGENCY: 0,
+	ALERT:     1,
+	CRITICAL:  2,
+	ERROR:     3,
+	WARNING:   4,
+	NOTICE:    5,
+	INFO:      6,
+	DEBUG:     7,
+}
+
+// Severity returns the syslog severity of a level (case insensitive), from 0 for EMERGENCY to
+// 7 for DEBUG. SUCCESS is treated as INFO; other custom levels have no severity
+func Severity(level string) (int, bool) {
+	level = strings.ToUpper(level)
+	if level == SUCCESS {
+		level = INFO
+	}
+	severity, ok := syslogSeverities[level]
+	return severity, ok
+}
@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"encoding/json"
+	"flag"
+	"testing"
+)
+
+func TestLevelText(t *testing.T) {
+	var config struct {
+		Level  Level `json:"level"`
+		Report Level `json:"report"`
+	}
+	if err := json.Unmarshal([]byte(`{"level":"warning","report":"NONE"}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Level != LevelWarning || config.Report != LevelNone {
+		t.Errorf("expected warning and none, got %s and %s", config.Level, config.Report)
+	}
+	b, err := json.Marshal(config)
+	if err != nil || string(b) != `{"level":"WARNING","report":"NONE"}` {
+		t.Errorf("expected levels to marshal to their names, got %s (%v)", b, err)
+	}
+	if err := json.Unmarshal([]byte(`{"level":"verbose"}`), &config); err == nil {
+		t.Error("expected an unknown level to fail")
+	}
+	if _, err := json.Marshal(Level(9)); err == nil {
+		t.Error("expected an invalid level not to marshal")
+	}
+}
+
+func TestLevelFlag(t *testing.T) {
+	level := LevelInfo
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&level, "level", "log level")
+	if err := fs.Parse([]string{"-level", "error"}); err != nil {
+		t.Fatal(err)
+	}
+	ll, err := NewLog("", "TEST", int(level), LEVEL_NONE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ll.Level() != LevelError || ll.Level().String() != ERROR {
+		t.Errorf("expected the flag to set the level to error, got %s", ll.Level())
+	}
+}
+
+func TestStandardLevels(t *testing.T) {
+	cases := map[int][]string{
+		LEVEL_DEBUG:   {DEBUG, INFO, WARNING, ERROR},
+		LEVEL_INFO:    {INFO, WARNING, ERROR},
+		LEVEL_WARNING: {WARNING, ERROR},
+		LEVEL_ERROR:   {ERROR},
+		LEVEL_NONE:    {},
+	}
+	for level, written := range cases {
+		sl, err := NewLog("", "TEST", level, LEVEL_NONE, WithStandardLevels())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entryLevel := range []string{DEBUG, INFO, WARNING, ERROR} {
+			expected := false
+			for _, w := range written {
+				expected = expected || w == entryLevel
+			}
+			if sl.shouldWrite(entryLevel) != expected {
+				t.Errorf("expected %s entries to be written at level %s: %t", entryLevel, Level(level), expected)
+			}
+		}
+	}
+	legacy, _ := NewLog("", "TEST", LEVEL_DEBUG, LEVEL_NONE)
+	if legacy.shouldWrite(INFO) {
+		t.Error("expected the legacy ordering to be kept by default")
+	}
+}
+
+func TestSyslogLevels(t *testing.T) {
+	legacy, _ := NewLog("", "TEST", LEVEL_ERROR, LEVEL_NONE)
+	standard, _ := NewLog("", "TEST", LEVEL_WARNING, LEVEL_NONE, WithStandardLevels())
+	cases := []struct {
+		level            string
+		legacy, standard bool
+	}{
+		{EMERGENCY, true, true},
+		{CRITICAL, true, true},
+		{NOTICE, false, false},
+		{"custom", true, true},
+	}
+	for _, c := range cases {
+		if legacy.shouldWrite(c.level) != c.legacy || standard.shouldWrite(c.level) != c.standard {
+			t.Errorf("expected %s to be written: legacy %t, standard %t", c.level, c.legacy, c.standard)
+		}
+	}
+	info, _ := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithStandardLevels())
+	if !info.shouldWrite(NOTICE) || info.shouldWrite(DEBUG) {
+		t.Error("expected notices but not debug entries at the standard info level")
+	}
+	if severity, ok := Severity("alert"); !ok || severity != 1 {
+		t.Errorf("expected alert to have severity 1, got %d", severity)
+	}
+	if severity, _ := Severity(SUCCESS); severity != 6 {
+		t.Errorf("expected success to map to the info severity, got %d", severity)
+	}
+}
+
+func TestSyslogLevelThresholds(t *testing.T) {
+	level, err := ParseLevel("critical")
+	if err != nil || level != LevelCritical || level.String() != CRITICAL {
+		t.Fatalf("expected critical to parse, got %s and %v", level, err)
+	}
+	if ll, _ := NewLog("", "TEST", int(level), LEVEL_NONE); ll.Level() != LevelInfo {
+		t.Errorf("expected a syslog level to be taken as info without WithSyslogLevels, got %s", ll.Level())
+	}
+	for _, standard := range []bool{false, true} {
+		cl, _ := NewLog("", "TEST", int(level), LEVEL_NONE, WithSyslogLevels())
+		cl.standardLevels = standard
+		if !cl.shouldWrite(EMERGENCY) || !cl.shouldWrite(CRITICAL) || cl.shouldWrite(ERROR) || cl.shouldWrite(INFO) {
+			t.Errorf("expected only entries at least as severe as critical to be written, standard %t", standard)
+		}
+	}
+	t.Setenv(EnvLevel, "notice")
+	nl, _ := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithSyslogLevels(), WithEnvLevels())
+	if nl.Level() != LevelNotice || !nl.shouldWrite(WARNING) || !nl.shouldWrite(NOTICE) || nl.shouldWrite(INFO) {
+		t.Errorf("expected %s to set the notice level, got %s", EnvLevel, nl.Level())
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+
+package logging
+
+// lockFile is a no-op on platforms without advisory file locking
+func lockFile(file File) (func(), error) {
+	return func() {}, nil
+}
@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFileLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locked.log")
+	logs := make([]*Log, 4)
+	for i := range logs {
+		var err error
+		// separate logs stand in for separate processes sharing the file
+		logs[i], err = NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithFileLock())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	message := strings.Repeat("x", 4096)
+	var wg sync.WaitGroup
+	for i, ll := range logs {
+		wg.Add(1)
+		go func(i int, ll *Log) {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				ll.Info(fmt.Sprintf("%d %s", i, message))
+			}
+		}(i, ll)
+	}
+	wg.Wait()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(string(b), "\n"), "\n") {
+		if !dateForm.MatchString(line) || !(strings.HasSuffix(line, message) || strings.HasSuffix(line, "initialising log")) {
+			t.Fatalf("expected line %d to be a complete record, got '%.80s...'", i, line)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package logging
+
+import "syscall"
+
+// lockFile takes an exclusive flock on the file, returning a function releasing it.
+// Files without a descriptor, such as those of a MemFS, are not locked
+func lockFile(file File) (func(), error) {
+	f, ok := file.(interface{ Fd() uintptr })
+	if !ok {
+		return func() {}, nil
+	}
+	fd := int(f.Fd())
+	if err := syscall.Flock(fd, syscall.LOCK_EX); err != nil {
+		return nil, err
+	}
+	return func() {
+		syscall.Flock(fd, syscall.LOCK_UN)
+	}, nil
+}
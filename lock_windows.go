@@ -0,0 +1,35 @@
+//go:build windows
+
+package logging
+
+import (
+	"math"
+	"syscall"
+	"unsafe"
+)
+
+const lockfileExclusiveLock = 0x2
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+// lockFile takes an exclusive LockFileEx lock on the file, returning a function releasing it.
+// Files without a handle, such as those of a MemFS, are not locked
+func lockFile(file File) (func(), error) {
+	f, ok := file.(interface{ Fd() uintptr })
+	if !ok {
+		return func() {}, nil
+	}
+	handle := f.Fd()
+	overlapped := new(syscall.Overlapped)
+	r, _, err := procLockFileEx.Call(handle, lockfileExclusiveLock, 0, math.MaxUint32, math.MaxUint32, uintptr(unsafe.Pointer(overlapped)))
+	if r == 0 {
+		return nil, err
+	}
+	return func() {
+		procUnlockFileEx.Call(handle, 0, math.MaxUint32, math.MaxUint32, uintptr(unsafe.Pointer(overlapped)))
+	}, nil
+}
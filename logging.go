@@ -1,11 +1,15 @@
 package logging
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,11 +20,20 @@ const (
 	SUCCESS       = "SUCCESS"
 	DEBUG         = "DEBUG"
 	NONE          = "NONE"
+	NOTICE        = "NOTICE"
+	CRITICAL      = "CRITICAL"
+	ALERT         = "ALERT"
+	EMERGENCY     = "EMERGENCY"
 	LEVEL_NONE    = 0
 	LEVEL_ERROR   = 1
 	LEVEL_WARNING = 2
 	LEVEL_DEBUG   = 3
 	LEVEL_INFO    = 4
+	// the syslog levels as thresholds, writing entries at least as severe
+	LEVEL_NOTICE    = 5
+	LEVEL_CRITICAL  = 6
+	LEVEL_ALERT     = 7
+	LEVEL_EMERGENCY = 8
 )
 
 var logLevels map[string]int = map[string]int{
@@ -32,32 +45,230 @@ var logLevels map[string]int = map[string]int{
 }
 
 type Log struct {
-	level       int
-	reportLevel int
-	path, env   string
-	file        *os.File
+	level           int
+	reportLevel     int
+	path, env       string
+	file            File
+	fs              FS
+	sinks           []Sink
+	ring            *RingBuffer
+	recentErrors    *RingBuffer
+	sampler         *sampler
+	dedup           *dedup
+	errorRate       *errorRate
+	anomaly         *rateAnomaly
+	profiles        map[string]*RedactionProfile
+	codes           CodeCatalog
+	events          map[string]EventType
+	tamperMarks     bool
+	minFree         int64
+	redactions      []redaction
+	fields          Fields
+	scope           *fieldScope
+	attrs           []Attr
+	expandErrors    bool
+	printLevel      string
+	goroutineID     bool
+	caller          bool
+	seq             *atomic.Uint64
+	precision       int
+	uptime          bool
+	started         time.Time
+	slow            time.Duration
+	banner          bool
+	crashDump       bool
+	truncation      *truncation
+	masks           map[string]maskMode
+	hashKey         []byte
+	chain           *hashChain
+	cipher          *recordCipher
+	signingKey      []byte
+	fileMode        os.FileMode
+	dirMode         os.FileMode
+	owner           *fileOwner
+	sanitize        bool
+	singleLine      bool
+	stripANSI       bool
+	crlf            bool
+	standardLevels  bool
+	syslogScale     bool // syslog levels are accepted as log and report levels
+	component       string
+	levels          *levels
+	reporter        Reporter
+	journal         bool // stderr is connected to the systemd journal
+	encoder         Encoder
+	reportEncoder   Encoder
+	preHooks        []PreWriteHook
+	postHooks       []PostWriteHook
+	providers       []FieldProvider
+	clock           func() time.Time
+	closed          *atomic.Bool
+	onError         *errorHandler
+	fallback        Sink
+	retry           *RetryPolicy
+	diskFull        *diskFullPolicy
+	naming          *rotationNaming
+	rotateOnStartup bool
+	quarantine      bool
+	compressor      Compressor
+	archive         *archive
+	budget          *diskBudget
+	index           *sparseIndex
+	mmap            bool
+	durability      *syncPolicy
+	lockFile        bool
+	writer          *fileWriter
+	coalescer       *coalescer
+	async           *asyncQueue
+	backpressure    *backpressure
+	watchdog        *watchdog
+	catchUps        []catchUp
+	shippers        []ShipperConfig // built into sinks by NewLog
+	metrics         *metricAggregator
+	subs            *subscriptions
+	once            *onceKeys
+	done            chan struct{}   // closed by Close to stop the background loops
+	loops           *sync.WaitGroup // the background loops, waited for by Close
+	deferred        *deferredBuffer
+	latency         *latencies
+	muted           bool
+	mu              *sync.Mutex
+	opts            []Option // as passed to NewLog, for Clone
+	configErr       error    // the first invalid option, returned by NewLog
 }
 
 const chunkSize = 50
 
 var (
 	dateForm = regexp.MustCompile(`^\[\d{4}-\d{2}-\d{2}(.*?)$`)
+	jsonForm = regexp.MustCompile(`^\{"time":"\d{4}-\d{2}-\d{2}`)
+	// dockerForm starts a record written by DockerEncoder
+	dockerForm = regexp.MustCompile(`^\{"log":"\[\d{4}-\d{2}-\d{2}`)
 )
 
-func NewLog(path, env string, logLevel, reportLevel int) (l *Log, err error) {
-	l = &Log{
-		level:       getLogLevel(logLevel),
-		reportLevel: getLogLevel(reportLevel),
-		path:        path,
-		env:         env,
+// recordForms match the first line of a record in each format ParseEntry reads
+var recordForms = []*regexp.Regexp{dateForm, jsonForm, dockerForm, logfmtForm, cefForm, combinedForm, w3cForm}
+
+// isRecordStart reports whether a line starts a record in one of the formats ParseEntry reads
+func isRecordStart(line string) bool {
+	for _, form := range recordForms {
+		if form.MatchString(line) {
+			return true
+		}
 	}
-	_, err = l.Write("initialising log", "INFO")
+	return false
+}
+
+func NewLog(path, env string, logLevel, reportLevel int, opts ...Option) (l *Log, err error) {
+	l = newLog(path, env, logLevel, reportLevel, opts)
+	l.opts = append([]Option(nil), opts...)
+	if l.configErr != nil {
+		return nil, l.configErr
+	}
+	if l.path != "" && !readableEncoder(l.encoder) {
+		return nil, ErrUnreadableEncoder
+	}
+	if l.budget != nil {
+		// the budget removes the files the rotated file pattern matches unasked
+		if _, err = l.rotatedGlob(); err != nil {
+			return nil, err
+		}
+	}
+	shippers, err := l.startShippers()
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		if err != nil {
+			closeShippers(shippers)
+		}
+	}()
+	for _, s := range l.sinks {
+		if fs, ok := s.(*fileSink); ok && fs.mode == 0 {
+			fs.mode = l.fileMode
+		}
+	}
+	l.started = l.now()
+	if l.quarantine {
+		if err = l.quarantinePartial(); err != nil {
+			return nil, err
+		}
+	}
+	if l.rotateOnStartup {
+		if _, err = l.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+	if l.async != nil {
+		// started only once nothing can fail, so that no goroutine is left behind
+		go l.async.run()
+	}
+	if l.banner {
+		_, err = l.With(l.bannerAttrs()...).Write("initialising log", "INFO")
+	} else {
+		_, err = l.Write("initialising log", "INFO")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if l.sampler != nil {
+		l.loop(l.watchSuppressed)
+	}
+	if l.backpressure != nil {
+		l.loop(l.watchBackpressure)
+	}
+	if l.watchdog != nil {
+		l.loop(l.watch)
+	}
+	for _, c := range l.catchUps {
+		c := c
+		l.loop(func() { l.watchCatchUp(c) })
+	}
+	if l.metrics != nil {
+		l.loop(l.watchMetrics)
+	}
 	return l, nil
 }
 
+// loop runs fn in the background until it returns, which it does once done is closed
+func (l *Log) loop(fn func()) {
+	l.loops.Add(1)
+	go func() {
+		defer l.loops.Done()
+		fn()
+	}()
+}
+
+// newLog returns a log with the arguments of NewLog and its options applied, before anything
+// is opened or started, for NewLog and ValidateConfig
+func newLog(path, env string, logLevel, reportLevel int, opts []Option) *Log {
+	mu := &sync.Mutex{}
+	l := &Log{
+		reportLevel: reportLevel,
+		path:        path,
+		env:         env,
+		mu:          mu,
+		closed:      &atomic.Bool{},
+		onError:     &errorHandler{},
+		subs:        &subscriptions{},
+		once:        &onceKeys{logged: make(map[string]time.Time)},
+		done:        make(chan struct{}),
+		loops:       &sync.WaitGroup{},
+		writer:      &fileWriter{resume: sync.NewCond(mu)},
+		scope:       &fieldScope{},
+		encoder:     TextEncoder{},
+		levels:      &levels{level: logLevel, components: make(map[string]int)},
+		journal:     stderrIsJournal(),
+		minFree:     defaultMinFreeSpace,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	// clamped once the options say whether the syslog levels are accepted
+	l.reportLevel, l.levels.level = l.clampLevel(l.reportLevel), l.clampLevel(l.levels.level)
+	return l
+}
+
 // LogLevel returns the appropriate level from a string input (case insensitive)
 // Note that the levels are (in ascending order of sensitivity)
 // ERROR | WARNING | DEBUG | INFO
@@ -67,6 +278,9 @@ func NewLog(path, env string, logLevel, reportLevel int) (l *Log, err error) {
 func LogLevel(level string) int {
 	level = strings.ToUpper(level)
 	ll, ok := logLevels[level]
+	if !ok {
+		ll, ok = syslogThresholds[level]
+	}
 	if !ok {
 		return LEVEL_INFO
 	}
@@ -82,6 +296,9 @@ func LogLevel(level string) int {
 func ReportLevel(level string) int {
 	level = strings.ToUpper(level)
 	rl, ok := logLevels[level]
+	if !ok {
+		rl, ok = syslogThresholds[level]
+	}
 	if !ok {
 		return LEVEL_INFO
 	}
@@ -89,19 +306,290 @@ func ReportLevel(level string) int {
 }
 
 func (l *Log) Write(message, level string) (result string, err error) {
-	msg := l.logMessage(level, message)
-	l.report(level, msg)
-	if !l.shouldWrite(level) {
+	return l.write(time.Time{}, message, level, nil)
+}
+
+// WriteAt writes an entry with an explicit timestamp instead of the current time, for
+// importing or replaying entries; see WriteBatch to write many at once. The entry is written
+// after those already in the file whatever its time, so entries must be written oldest first,
+// after any already written: an entry older than the one before it is passed over by ReadRange
+// and the index, and Verify reports the file as out of order. Import older entries into a log
+// of their own
+func (l *Log) WriteAt(t time.Time, level, message string) (string, error) {
+	return l.write(t, message, level, nil)
+}
+
+// write writes an entry stamped with t, or the current time if t is zero, describing the
+// outcome in res if it is not nil
+func (l *Log) write(t time.Time, message, level string, res *WriteResult) (result string, err error) {
+	if l.muted {
+		return "", nil
+	}
+	if l.isClosed() {
+		return "", ErrClosed
+	}
+	if l.deferred != nil && l.deferEntry(t, message, level) {
+		return "", nil
+	}
+	if !l.admit(level, message) {
+		return
+	}
+	e := l.newEntry(level, message)
+	if !t.IsZero() {
+		e.Time = t.UTC()
+	}
+	return l.writeEntryResult(e, res)
+}
+
+// admit runs the anomaly detector, sampler and deduplication on an entry about to be written,
+// writing the summaries they produce, and returns false if the entry is to be dropped
+func (l *Log) admit(level, message string) bool {
+	l.reportAnomalies(l.anomaly.observe(level, l.now()))
+	ok, summary := l.sampler.allow(level, l.now())
+	if !ok {
+		return false
+	}
+	if summary != "" {
+		l.writeEntry(l.newEntry(WARNING, summary))
+	}
+	ok, repeated := l.dedup.check(level, message, l.now())
+	if repeated != nil {
+		l.writeEntry(l.newEntry(repeated.Level, repeated.Message))
+	}
+	return ok
+}
+
+func (l *Log) writeEntry(e Entry) (result string, err error) {
+	return l.writeEntryResult(e, nil)
+}
+
+func (l *Log) writeEntryResult(e Entry, res *WriteResult) (result string, err error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	e, msg, ok := l.prepare(e, (*buf)[:0])
+	if res != nil {
+		res.Entry = e
+	}
+	if !ok {
 		return
 	}
-	err = l.openLogForWrite()
+	*buf = msg
+	if l.async != nil {
+		l.async.enqueue(e, append([]byte(nil), msg...))
+		if res != nil {
+			res.Queued = true
+		}
+		return string(msg), nil
+	}
+	return l.deliver(e, msg, res)
+}
+
+// prepare runs the hooks and transformations on an entry, then formats and reports it.
+// It returns false if the entry should not be written
+func (l *Log) prepare(e Entry, dst []byte) (Entry, []byte, bool) {
+	if l.muted {
+		return e, nil, false
+	}
+	e = l.provide(e)
+	if len(l.preHooks) > 0 {
+		var keep bool
+		if e, keep = l.applyPreHooks(e); !keep {
+			return e, nil, false
+		}
+	}
+	e.Message = l.truncate(l.redact(validUTF8(e.Message)))
+	if l.stripANSI {
+		e.Message = stripANSI(e.Message)
+	}
+	if l.sanitize {
+		e.Message = escapeControl(e.Message)
+	} else if l.singleLine {
+		e.Message = escapeLineBreaks(e.Message)
+	}
+	e.Fields = l.maskFields(l.redactFields(e.Fields))
+	e.Attrs = l.maskAttrs(l.redactAttrs(e.Attrs))
+	l.errorRate.observe(e)
+	l.recordError(e)
+	write := l.shouldWrite(e.Level)
+	if write && l.seq != nil && e.Seq == 0 {
+		// numbered only once nothing can drop it, so that a gap means a lost record
+		e.Seq = l.seq.Add(1)
+	}
+	msg := l.sign(l.encoder.AppendEntry(dst, e))
+	l.report(e, msg)
+	return e, msg, write
+}
+
+// deliver writes a formatted entry to the sinks and the file, recording the sinks that
+// received it in res if it is not nil
+func (l *Log) deliver(e Entry, msg []byte, res *WriteResult) (result string, err error) {
+	defer l.observeWrite(time.Now())
+	sinkErr := l.writeSinks(e, res)
+	if sinkErr != nil {
+		l.handleError(sinkErr)
+	}
+	written, err := l.writeFile(msg)
 	if err != nil {
-		return "", err
+		l.handleError(err)
+		if l.fallback == nil || l.fallback.WriteEntry(e) != nil {
+			return "", err
+		}
+		written, err = msg, nil
+		if res != nil {
+			res.Fallback = true
+		}
 	}
-	defer l.file.Close()
-	_, err = l.file.Write(append(msg, []byte("\n")...))
+	msg = written
 	result = string(msg)
-	return
+	l.runPostHooks(e, result)
+	return result, sinkErr
+}
+
+func (l *Log) writeFile(msg []byte) ([]byte, error) {
+	if l.path == "" {
+		return msg, nil // entries only go to sinks
+	}
+	if l.coalescer != nil {
+		return l.writeCoalesced(msg)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.awaitStall()
+	l.rotateOverBudget(len(msg))
+	unlock, err := l.syncChain()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	l.writeDiscontinuityMarker()
+	msg, out, err := l.sealRecord(msg)
+	if err != nil {
+		return nil, err
+	}
+	offset := l.indexOffset()
+	if err = l.appendWithPolicies(append(out, l.lineEnd()...)); err != nil {
+		return msg, err
+	}
+	l.indexRecords(offset, msg)
+	return msg, nil
+}
+
+// writeRecords appends formatted records to the file, with a single write where possible
+// (see writeGroups), returning the records as written (including any audit chain hash)
+func (l *Log) writeRecords(msgs [][]byte) ([][]byte, error) {
+	if l.path == "" {
+		return msgs, nil // entries only go to sinks
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.awaitStall()
+	var n int
+	for _, msg := range msgs {
+		n += len(msg)
+	}
+	l.rotateOverBudget(n)
+	unlock, err := l.syncChain()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	l.writeDiscontinuityMarker()
+	written := make([][]byte, len(msgs))
+	outs := make([][]byte, len(msgs))
+	for i, msg := range msgs {
+		msg, out, err := l.sealRecord(msg)
+		if err != nil {
+			return nil, err
+		}
+		written[i], outs[i] = msg, append(out, l.lineEnd()...)
+	}
+	offset := l.indexOffset()
+	for _, group := range l.writeGroups(outs) {
+		if err := l.appendWithPolicies(group); err != nil {
+			return written, err
+		}
+	}
+	l.indexRecords(offset, written...)
+	return written, nil
+}
+
+// sealRecord adds the audit chain hash to a record and encrypts it, returning the record
+// and the bytes to write. It is called with the write lock held
+func (l *Log) sealRecord(msg []byte) ([]byte, []byte, error) {
+	msg, err := l.chain.seal(l, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	out, err := l.cipher.encrypt(msg)
+	return msg, out, err
+}
+
+// appendWithPolicies appends to the file, applying the retry and disk-full policies. A short
+// write is not retried, as the rest of the record could land after another writer's; see
+// appendWhole
+func (l *Log) appendWithPolicies(b []byte) error {
+	var short error
+	err := l.retry.retry(func() error {
+		err := l.appendWhole(b)
+		if errors.Is(err, io.ErrShortWrite) {
+			short = err
+		}
+		return err
+	}, func(delay time.Duration) error {
+		if short != nil {
+			return short
+		}
+		return l.stall(delay)
+	})
+	if err != nil && isDiskFull(err) {
+		err = l.handleDiskFull(b, err)
+	}
+	return err
+}
+
+// appendWhole appends a record with a single write. If only part of it is written it fails
+// with io.ErrShortWrite, ending the part written with a line break so that the next record
+// starts on a line of its own
+func (l *Log) appendWhole(b []byte) error {
+	n, err := l.appendRecord(b)
+	if err == nil || n == 0 {
+		return err
+	}
+	l.appendRecord(l.lineEnd())
+	return fmt.Errorf("%w: %d of %d bytes written: %v", io.ErrShortWrite, n, len(b), err)
+}
+
+// appendRecord appends to the file with a single write, returning the number of bytes written
+func (l *Log) appendRecord(b []byte) (int, error) {
+	file, err := l.outputFile()
+	if err != nil {
+		return 0, err
+	}
+	if l.lockFile && !l.writer.locked {
+		unlock, err := lockFile(file)
+		if err != nil {
+			return 0, err
+		}
+		defer unlock()
+	}
+	n, err := file.Write(b)
+	l.writer.size += int64(n)
+	if err != nil {
+		l.writer.close() // reopen on the next write
+		return n, err
+	}
+	now := l.now()
+	if l.durability.due(now) {
+		start := time.Now()
+		err = file.Sync()
+		l.observeFlush(start)
+	}
+	if l.budget.due(now) {
+		if bErr := l.enforceDiskBudget(); bErr != nil {
+			l.handleError(bErr)
+		}
+	}
+	return n, err
 }
 
 func (l *Log) Error(message string) (string, error) {
@@ -125,35 +613,66 @@ func (l *Log) Info(message string) (string, error) {
 }
 
 func (l *Log) Errorf(message string, vars ...interface{}) (string, error) {
-	return l.Error(fmt.Sprintf(message, vars...))
+	return l.writef(ERROR, message, vars)
 }
 
 func (l *Log) Successf(message string, vars ...interface{}) (string, error) {
-	return l.Success(fmt.Sprintf(message, vars...))
+	return l.writef(SUCCESS, message, vars)
 }
 
 func (l *Log) Warningf(message string, vars ...interface{}) (string, error) {
-	return l.Warning(fmt.Sprintf(message, vars...))
+	return l.writef(WARNING, message, vars)
 }
 
 func (l *Log) Debugf(message string, vars ...interface{}) (string, error) {
-	return l.Debug(fmt.Sprintf(message, vars...))
+	return l.writef(DEBUG, message, vars)
 }
 
 func (l *Log) Infof(message string, vars ...interface{}) (string, error) {
-	return l.Info(fmt.Sprintf(message, vars...))
+	return l.writef(INFO, message, vars)
 }
 
-func (l *Log) logMessage(level, message string) []byte {
-	return []byte(
-		fmt.Sprintf(
-			"[%s] [%s.%s] %s",
-			time.Now().UTC().Format(time.RFC3339),
-			l.env,
-			level,
-			message,
-		),
-	)
+// writef formats and writes a message, skipping the formatting for a log muted by If
+func (l *Log) writef(level, message string, vars []interface{}) (string, error) {
+	if l.muted {
+		return "", nil
+	}
+	return l.Write(fmt.Sprintf(message, vars...), level)
+}
+
+func (l *Log) newEntry(level, message string) Entry {
+	now := l.now()
+	e := Entry{
+		Time:      now.UTC(),
+		Env:       l.env,
+		Level:     level,
+		Message:   message,
+		Fields:    l.scopedFields(),
+		Attrs:     l.attrs,
+		precision: l.precision,
+	}
+	if l.uptime {
+		e.Attrs = append(e.Attrs[:len(e.Attrs):len(e.Attrs)], Duration("uptime", now.Sub(l.started)))
+	}
+	if l.goroutineID {
+		e.Attrs = append(e.Attrs[:len(e.Attrs):len(e.Attrs)], Int64("goroutine", goroutineID()))
+	}
+	if l.caller {
+		e.Attrs = append(e.Attrs[:len(e.Attrs):len(e.Attrs)], String("caller", caller()))
+	}
+	return e
+}
+
+// now returns the current time from the configured clock
+func (l *Log) now() time.Time {
+	if l.clock != nil {
+		return l.clock()
+	}
+	return time.Now()
+}
+
+func (l *Log) logMessage(e Entry) []byte {
+	return l.encoder.AppendEntry(nil, e)
 }
 
 // Path returns the file path
@@ -163,16 +682,30 @@ func (l *Log) Path() string {
 
 // GetLog returns lines of the log
 func (l *Log) GetLog(lines uint) (result []string, err error) {
+	if l.path == "" {
+		return []string{}, nil
+	}
+	if l.cipher != nil {
+		return l.getEncryptedLog(lines)
+	}
 	err = l.openLogForRead()
 	if err != nil {
 		return result, err
 	}
 	defer l.file.Close()
-	stat, err := l.file.Stat()
+	size, err := l.committedSize(l.file)
 	if err != nil {
 		return result, err
 	}
-	l.readChunks(int64(lines), stat.Size(), &result)
+	if tail, ok := l.indexedTail(int(lines), size); ok {
+		return tail, nil
+	}
+	if l.mmap {
+		if tail, ok := l.mappedTail(int(lines), size); ok {
+			return tail, nil
+		}
+	}
+	l.readChunks(int64(lines), size, &result)
 	return result, err
 }
 
@@ -228,14 +761,17 @@ func (l *Log) wholeRead(fileSize int64) ([]string, error) {
 	node := make([]string, 0)
 	result := make([]string, 0)
 	for i, v := range splitLog {
-		if dateForm.MatchString(v) {
+		if isRecordStart(v) {
 			if len(node) > 0 {
 				result = append(result, strings.Trim(strings.Join(node, "\n"), " "))
 			}
-			node = []string{v}
+			node = []string{strings.TrimSuffix(v, "\r")}
 			continue
 		}
-		node = append(node, strings.Trim(v, " "))
+		if len(node) == 0 {
+			continue // before the first record, such as a file header
+		}
+		node = append(node, strings.Trim(v, " \r"))
 		if i == len(splitLog)-1 && len(node) > 0 {
 			result = append(result, strings.Trim(strings.Join(node, "\n"), " "))
 		}
@@ -246,14 +782,14 @@ func (l *Log) wholeRead(fileSize int64) ([]string, error) {
 func (l *Log) iterateChunkSplit(split []string, result *[]string) {
 	node := make([]string, 0)
 	for i := len(split) - 1; i > 0; i-- {
-		if dateForm.MatchString(split[i]) {
-			node = append(node, split[i])
+		if isRecordStart(split[i]) {
+			node = append(node, strings.TrimSuffix(split[i], "\r"))
 			l.reverseNode(&node)
 			*result = append(*result, strings.Trim(strings.Join(node, "\n"), " "))
 			node = make([]string, 0)
 			continue
 		}
-		node = append(node, strings.Trim(split[i], " "))
+		node = append(node, strings.Trim(split[i], " \r"))
 	}
 }
 
@@ -271,8 +807,7 @@ func (l *Log) reverseNode(node *[]string) {
 
 func (l *Log) ErrLog(e error, fatal bool) string {
 	if fatal {
-		l.Write(e.Error(), "FATAL")
-		l.file.Close()
+		l.writeFatal(e.Error())
 		log.Fatal(e)
 		return ""
 	}
@@ -281,22 +816,22 @@ func (l *Log) ErrLog(e error, fatal bool) string {
 }
 
 func (l *Log) openLogForWrite() error {
-	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_WRONLY, os.ModeAppend)
+	file, err := l.getFS().OpenFile(l.path, os.O_APPEND|os.O_WRONLY, os.ModeAppend)
 	if err != nil && os.IsNotExist(err) {
-		_, err = os.Create(l.path)
+		err = l.createLog()
 		if err != nil {
 			return err
 		}
 		return l.openLogForWrite()
 	}
-	l.file = file
+	l.writer.file = file
 	return err
 }
 
 func (l *Log) openLogForRead() error {
-	file, err := os.OpenFile(l.path, os.O_RDONLY, os.ModeDevice)
+	file, err := l.getFS().OpenFile(l.path, os.O_RDONLY, os.ModeDevice)
 	if err != nil && os.IsNotExist(err) {
-		_, err = os.Create(l.path)
+		err = l.createLog()
 		if err != nil {
 			return err
 		}
@@ -307,23 +842,56 @@ func (l *Log) openLogForRead() error {
 }
 
 func (l *Log) shouldWrite(level string) bool {
-	level = strings.ToUpper(level)
-	logLevel, ok := logLevels[level]
-	if !ok {
-		return true // we don't impose logging restrictions for custom levels
-	}
-	return logLevel <= l.level
+	return l.allows(strings.ToUpper(level), l.threshold())
 }
 
-func (l *Log) report(level string, msg []byte) {
-	if l.reportLevel <= LEVEL_NONE {
+func (l *Log) report(e Entry, msg []byte) {
+	if !l.shouldReport(e.Level) {
+		return
+	}
+	if l.reportEncoder != nil {
+		buf := getBuffer()
+		defer putBuffer(buf)
+		msg = l.reportEncoder.AppendEntry((*buf)[:0], e)
+		*buf = msg
+	}
+	if l.reporter != nil {
+		l.reporter.Report(e, msg)
 		return
 	}
-	reportLevel, ok := logLevels[level]
-	if !ok || reportLevel <= l.reportLevel {
-		reportMsg(msg)
+	if l.journal {
+		journalStderr.Report(e, msg)
 		return
 	}
+	reportMsg(msg)
+}
+
+func (l *Log) shouldReport(level string) bool {
+	if l.reportLevel <= LEVEL_NONE {
+		return false
+	}
+	return l.allows(level, l.reportLevel)
+}
+
+// allows reports whether an entry at level passes the threshold, using the standard ordering
+// when configured or the threshold is a syslog level, and the legacy one otherwise
+func (l *Log) allows(level string, threshold int) bool {
+	if threshold > LEVEL_INFO {
+		severity, ok := Severity(level)
+		return !ok || severity <= syslogSeverities[Level(threshold).String()]
+	}
+	logLevel, ok := logLevels[level]
+	if !ok {
+		logLevel, ok = syslogLevels[level]
+	}
+	if !ok {
+		return true // we don't impose logging restrictions for custom levels
+	}
+	if !l.standardLevels {
+		return logLevel <= threshold
+	}
+	severity, ok := Severity(level)
+	return ok && threshold > LEVEL_NONE && severity <= syslogSeverities[Level(threshold).String()]
 }
 
 func reportMsg(msg []byte) {
@@ -339,3 +907,12 @@ func getLogLevel(level int) int {
 	}
 	return level
 }
+
+// clampLevel returns the log or report level the log accepts for level: one on the legacy
+// scale, or also a syslog level with WithSyslogLevels
+func (l *Log) clampLevel(level int) int {
+	if l.syslogScale && level > LEVEL_INFO && level <= LEVEL_EMERGENCY {
+		return level
+	}
+	return getLogLevel(level)
+}
@@ -156,10 +156,17 @@ func TestLogPath(t *testing.T) {
 }
 
 func TestReporting(t *testing.T) {
+	previous := l.l
 	err := spinTestLog(LEVEL_ERROR, LEVEL_DEBUG)
 	if err != nil {
 		t.Fatal(err)
 	}
+	// the log is on a new file if the suite has crossed a second boundary
+	if previous.path != l.l.path {
+		previous.Close()
+		os.Remove(previous.path)
+	}
+	l.l.Error("before reporting")
 	msg := fmt.Sprintf("%s: %d", "expects a report here but not a log", time.Now().Unix())
 	l.l.Debug(msg)
 	content, err := getFileContent()
@@ -174,10 +181,17 @@ func TestReporting(t *testing.T) {
 }
 
 func TestCustomLevel(t *testing.T) {
+	previous := l.l
 	err := spinTestLog(LEVEL_ERROR, LEVEL_NONE) // Lowest possible sensitivity
 	if err != nil {
 		t.Fatal(err)
 	}
+	// the log is on a new file if the suite has crossed a second boundary
+	if previous.path != l.l.path {
+		previous.Close()
+		os.Remove(previous.path)
+	}
+	l.l.Error("before custom level")
 	msg := fmt.Sprintf("%s: %d", "expects a report here and a log because we always log custom levels", time.Now().Unix())
 	l.l.Write(msg, "CUSTOMLEVEL")
 	checkWrite(t, "CUSTOMLEVEL", msg)
@@ -192,7 +206,7 @@ func initialiseTest() {
 }
 
 func tearDownTest() {
-	defer l.l.file.Close()
+	defer l.l.Close()
 	err := os.Remove(l.l.path)
 	if err != nil {
 		log.Println(err)
@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+type maskMode int
+
+const (
+	maskHide maskMode = iota
+	maskHash
+)
+
+const fieldMask = "****"
+
+// hashKeySize is the size of the key generated when WithHashKey is not used
+const hashKeySize = 32
+
+func (l *Log) setMasks(mode maskMode, keys []string) {
+	if l.masks == nil {
+		l.masks = make(map[string]maskMode)
+	}
+	if mode == maskHash && l.hashKey == nil {
+		l.hashKey = newHashKey()
+	}
+	for _, k := range keys {
+		l.masks[strings.ToLower(k)] = mode
+	}
+}
+
+// maskFields returns a copy of fields with sensitive values masked or hashed
+func (l *Log) maskFields(fields Fields) Fields {
+	if len(l.masks) == 0 || len(fields) == 0 {
+		return fields
+	}
+	masked := make(Fields, len(fields))
+	for k, v := range fields {
+		mode, ok := l.masks[strings.ToLower(k)]
+		switch {
+		case !ok:
+			masked[k] = v
+		case mode == maskHash:
+			masked[k] = hashValue(l.hashKey, v)
+		default:
+			masked[k] = fieldMask
+		}
+	}
+	return masked
+}
+
+// maskAttrs returns a copy of attrs with sensitive values masked or hashed
+func (l *Log) maskAttrs(attrs []Attr) []Attr {
+	if len(l.masks) == 0 || len(attrs) == 0 {
+		return attrs
+	}
+	var masked []Attr
+	for i, a := range attrs {
+		mode, ok := l.masks[strings.ToLower(a.Key)]
+		if !ok {
+			continue
+		}
+		if masked == nil {
+			masked = append([]Attr{}, attrs...)
+		}
+		if mode == maskHash {
+			masked[i] = String(a.Key, hashValue(l.hashKey, a.Value()))
+		} else {
+			masked[i] = String(a.Key, fieldMask)
+		}
+	}
+	if masked == nil {
+		return attrs
+	}
+	return masked
+}
+
+// hashValue returns a keyed hash of the value, so that values with little entropy such as
+// email addresses cannot be recovered by hashing candidates without the key
+func hashValue(key []byte, v interface{}) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(fmt.Sprint(v)))
+	return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil)[:16])
+}
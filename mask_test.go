@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFieldMasking(t *testing.T) {
+	ml, err := NewLog(filepath.Join(t.TempDir(), "mask.log"), "TEST", LEVEL_INFO, LEVEL_NONE, WithMaskedFields("SSN"), WithHashedFields("email"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := ml.WithFields(Fields{"email": "jane@example.com", "ssn": "123-45-6789", "plan": "pro"}).Info("signup")
+	second, _ := ml.WithFields(Fields{"email": "jane@example.com"}).Info("login")
+	if strings.Contains(first, "jane@example.com") || strings.Contains(first, "123-45-6789") {
+		t.Errorf("expected sensitive fields to be masked, got '%s'", first)
+	}
+	if !strings.Contains(first, "plan=pro") || !strings.Contains(first, "ssn="+fieldMask) {
+		t.Errorf("expected other fields to be kept and ssn masked, got '%s'", first)
+	}
+	hash := hashValue(ml.hashKey, "jane@example.com")
+	if !strings.Contains(first, "email="+hash) || !strings.Contains(second, "email="+hash) {
+		t.Errorf("expected email to be hashed consistently as %s, got '%s' and '%s'", hash, first, second)
+	}
+}
+
+func TestHashKey(t *testing.T) {
+	key := []byte("secret")
+	a, b := NewCapture(WithHashedFields("email"), WithHashKey(key)), NewCapture(WithHashKey(key), WithHashedFields("email"))
+	other := NewCapture(WithHashedFields("email"))
+	for _, c := range []*Capture{a, b, other} {
+		c.WithFields(Fields{"email": "jane@example.com"}).Info("signup")
+	}
+	hash := func(c *Capture) interface{} {
+		v, _ := c.Entries()[0].Field("email")
+		return v
+	}
+	if hash(a) != hashValue(key, "jane@example.com") || hash(a) != hash(b) {
+		t.Errorf("expected logs sharing a key to hash alike, got %v and %v", hash(a), hash(b))
+	}
+	if hash(other) == hash(a) {
+		t.Error("expected a log without the key to hash differently")
+	}
+}
+
+func TestWithFields(t *testing.T) {
+	fl, err := NewLog(filepath.Join(t.TempDir(), "fields.log"), "TEST", LEVEL_INFO, LEVEL_NONE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child := fl.WithFields(Fields{"a": 1}).WithFields(Fields{"b": "two words"})
+	result, _ := child.Info("fields")
+	if !strings.HasSuffix(result, `fields a=1 b="two words"`) {
+		t.Errorf("expected fields to be appended in key order, got '%s'", result)
+	}
+	if len(fl.fields) != 0 {
+		t.Errorf("expected parent log to have no fields, got %v", fl.fields)
+	}
+}
@@ -0,0 +1,146 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+)
+
+// recordScanner reads complete records, including multi-line messages, from a log file
+type recordScanner struct {
+	scanner *bufio.Scanner
+	pending string
+	started bool
+	partial bool
+}
+
+func newRecordScanner(r io.Reader) *recordScanner {
+	s := &recordScanner{scanner: bufio.NewScanner(r)}
+	s.scanner.Buffer(make([]byte, 0, 64<<10), 64<<20)
+	s.scanner.Split(s.scanLines)
+	return s
+}
+
+// scanLines splits lines as bufio.ScanLines does, noting whether the last line is unterminated
+func (s *recordScanner) scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) > 0 && bytes.IndexByte(data, '\n') < 0 {
+		s.partial = true
+	}
+	return bufio.ScanLines(data, atEOF)
+}
+
+// next returns the next record, or io.EOF once the reader is exhausted. Lines before the
+// first record are skipped, as is a last record without a terminating newline, which was
+// cut short by a crash or is still being written
+func (s *recordScanner) next() (string, error) {
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if !isRecordStart(line) {
+			if s.started {
+				s.pending += "\n" + line
+			}
+			continue
+		}
+		record, hadRecord := s.pending, s.started
+		s.pending, s.started = line, true
+		if hadRecord {
+			return strings.TrimRight(record, "\n"), nil
+		}
+	}
+	if err := s.scanner.Err(); err != nil {
+		return "", err
+	}
+	if !s.started {
+		return "", io.EOF
+	}
+	record := s.pending
+	s.pending, s.started = "", false
+	if s.partial {
+		return "", io.EOF
+	}
+	return strings.TrimRight(record, "\n"), nil
+}
+
+// MergeReader merges the entries of several logs into one chronologically ordered stream.
+// Entries with the same time are returned in the order of the readers
+type MergeReader struct {
+	sources []*recordScanner
+	heads   []*Entry
+	closers []io.Closer
+}
+
+// NewMergeReader returns a reader merging the records read from each reader
+func NewMergeReader(readers ...io.Reader) *MergeReader {
+	m := &MergeReader{
+		sources: make([]*recordScanner, len(readers)),
+		heads:   make([]*Entry, len(readers)),
+	}
+	for i, r := range readers {
+		m.sources[i] = newRecordScanner(r)
+	}
+	return m
+}
+
+// OpenMerged opens the log files at the paths and returns a reader merging them
+func OpenMerged(paths ...string) (*MergeReader, error) {
+	readers := make([]io.Reader, 0, len(paths))
+	closers := make([]io.Closer, 0, len(paths))
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			for _, c := range closers {
+				c.Close()
+			}
+			return nil, err
+		}
+		readers = append(readers, file)
+		closers = append(closers, file)
+	}
+	m := NewMergeReader(readers...)
+	m.closers = closers
+	return m, nil
+}
+
+// Next returns the earliest remaining entry, or io.EOF once every log is exhausted
+func (m *MergeReader) Next() (Entry, error) {
+	earliest := -1
+	for i, source := range m.sources {
+		if m.heads[i] == nil && source != nil {
+			record, err := source.next()
+			if err == io.EOF {
+				m.sources[i] = nil
+				continue
+			}
+			if err != nil {
+				return Entry{}, err
+			}
+			e, err := ParseEntry(record)
+			if err != nil {
+				return Entry{}, err
+			}
+			m.heads[i] = &e
+		}
+		if m.heads[i] != nil && (earliest < 0 || m.heads[i].Time.Before(m.heads[earliest].Time)) {
+			earliest = i
+		}
+	}
+	if earliest < 0 {
+		return Entry{}, io.EOF
+	}
+	e := *m.heads[earliest]
+	m.heads[earliest] = nil
+	return e, nil
+}
+
+// Close closes the files opened by OpenMerged
+func (m *MergeReader) Close() error {
+	var err error
+	for _, c := range m.closers {
+		if cErr := c.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
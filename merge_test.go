@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMergeReader(t *testing.T) {
+	api := strings.Join([]string{
+		"[2023-03-01T10:00:00Z] [api.INFO] request received",
+		"[2023-03-01T10:00:02Z] [api.ERROR] upstream failed",
+		"goroutine 1 [running]:",
+		"\tmain.go:12",
+		"[2023-03-01T10:00:04Z] [api.INFO] recovered",
+	}, "\n") + "\n"
+	worker := `{"time":"2023-03-01T10:00:01.500Z","env":"worker","level":"INFO","message":"job started","job":7}` + "\n" +
+		`{"time":"2023-03-01T10:00:03Z","env":"worker","level":"WARNING","message":"job slow"}` + "\n"
+	m := NewMergeReader(strings.NewReader(api), strings.NewReader(worker))
+	expected := []string{"request received", "job started", "upstream failed\ngoroutine 1 [running]:\n\tmain.go:12", "job slow", "recovered"}
+	for i, message := range expected {
+		e, err := m.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Message != message {
+			t.Errorf("expected entry %d to be '%s', got '%s'", i, message, e.Message)
+		}
+		if i == 1 && e.Fields["job"] == nil {
+			t.Errorf("expected JSON members to be read as fields, got %v", e.Fields)
+		}
+	}
+	if _, err := m.Next(); err != io.EOF {
+		t.Errorf("expected the end of the merged logs, got %v", err)
+	}
+}
+
+func TestOpenMerged(t *testing.T) {
+	dir := t.TempDir()
+	clock := steppingClock(time.Second)
+	paths := []string{filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log")}
+	for _, path := range paths {
+		ml, err := NewLog(path, filepath.Base(path), LEVEL_INFO, LEVEL_NONE, WithNow(clock))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ml.Info("first")
+		ml.Info("second")
+		ml.Close()
+	}
+	m, err := OpenMerged(paths...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	previous := time.Time{}
+	count := 0
+	for {
+		e, err := m.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Time.Before(previous) {
+			t.Errorf("expected entries in chronological order, got %s after %s", e.Time, previous)
+		}
+		previous = e.Time
+		count++
+	}
+	if count != 6 {
+		t.Errorf("expected six entries, got %d", count)
+	}
+}
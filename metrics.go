@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// metricsContentType is the content type of the Prometheus text exposition format
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WriteMetrics writes the log's Stats in the Prometheus text exposition format: the write and
+// flush latencies as summaries and the depth of each queue as a gauge
+func (l *Log) WriteMetrics(w io.Writer) error {
+	stats := l.Stats()
+	bw := bufio.NewWriter(w)
+	writeLatencyMetric(bw, "logging_write_latency_seconds", "Time taken to write an entry to the sinks and file.", stats.Write)
+	writeLatencyMetric(bw, "logging_flush_latency_seconds", "Time taken to sync the log file to disk.", stats.Flush)
+	fmt.Fprintln(bw, "# HELP logging_queue_depth Entries waiting to be written.")
+	fmt.Fprintln(bw, "# TYPE logging_queue_depth gauge")
+	fmt.Fprintf(bw, "logging_queue_depth{queue=\"async\"} %d\n", stats.Async.Depth)
+	for i, q := range stats.Sinks {
+		fmt.Fprintf(bw, "logging_queue_depth{queue=\"sink %d\"} %d\n", i+1, q.Depth)
+	}
+	return bw.Flush()
+}
+
+func writeLatencyMetric(w io.Writer, name, help string, stats LatencyStats) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s summary\n", name)
+	fmt.Fprintf(w, "%s{quantile=\"0.5\"} %g\n", name, stats.P50.Seconds())
+	fmt.Fprintf(w, "%s{quantile=\"0.95\"} %g\n", name, stats.P95.Seconds())
+	fmt.Fprintf(w, "%s{quantile=\"1\"} %g\n", name, stats.Max.Seconds())
+	fmt.Fprintf(w, "%s_sum %g\n", name, stats.Sum.Seconds())
+	fmt.Fprintf(w, "%s_count %d\n", name, stats.Count)
+}
+
+// MetricsHandler returns a handler serving WriteMetrics, to be scraped by Prometheus
+func (l *Log) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", metricsContentType)
+		if err := l.WriteMetrics(w); err != nil {
+			l.handleError(err)
+		}
+	})
+}
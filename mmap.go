@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"runtime/debug"
+	"strings"
+)
+
+// mapped is a read-only memory mapping of the log file
+type mapped struct {
+	data  []byte
+	unmap func() error
+}
+
+// mapLog maps the log file into memory for reading, returning false where mapping is not
+// supported, such as on a MemFS, so that the caller falls back to plain reads. Reading a
+// mapping of a file truncated meanwhile faults; see readMapped
+func (l *Log) mapLog() (*mapped, bool) {
+	file, err := l.getFS().OpenFile(l.path, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close() // the mapping outlives the descriptor
+	info, err := file.Stat()
+	if err != nil {
+		return nil, false
+	}
+	data, unmap, ok := mmapFile(file, info.Size())
+	if !ok {
+		return nil, false
+	}
+	return &mapped{data: data, unmap: unmap}, true
+}
+
+func (m *mapped) close() {
+	m.unmap()
+}
+
+// readMapped runs fn, which reads a mapping, returning false if the mapping faulted because
+// the file was truncated under it, such as by copytruncate rotation, rather than crashing
+func readMapped(fn func()) (ok bool) {
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		if r := recover(); r != nil {
+			if _, fault := r.(interface{ Addr() uintptr }); !fault {
+				panic(r)
+			}
+			ok = false
+		}
+	}()
+	fn()
+	return true
+}
+
+// mappedTail returns the last lines records of the first size bytes of a mapped log, newest
+// first as GetLog does. It returns false if the log cannot be mapped or the mapping faults
+func (l *Log) mappedTail(lines int, size int64) (records []string, ok bool) {
+	m, ok := l.mapLog()
+	if !ok || size > int64(len(m.data)) {
+		if ok {
+			m.close()
+		}
+		return nil, false
+	}
+	defer m.close()
+	ok = readMapped(func() {
+		records = tailRecords(m.data[:size], lines)
+	})
+	return records, ok
+}
+
+// mappedReader returns a reader over a mapped log from offset up to size, or false if the log
+// cannot be mapped. Should the mapping fault the rest is read from file
+func (l *Log) mappedReader(file io.ReaderAt, offset, size int64) (io.Reader, func(), bool) {
+	m, ok := l.mapLog()
+	if !ok || offset > size || size > int64(len(m.data)) {
+		if ok {
+			m.close()
+		}
+		return nil, nil, false
+	}
+	return &mapReader{data: m.data, file: file, pos: offset, end: size}, m.close, true
+}
+
+// mapReader reads a mapping from pos up to end, switching to reading the file at the same
+// position once the mapping faults
+type mapReader struct {
+	data     []byte
+	file     io.ReaderAt
+	fallback io.Reader
+	pos, end int64
+}
+
+func (r *mapReader) Read(p []byte) (int, error) {
+	if r.fallback != nil {
+		return r.fallback.Read(p)
+	}
+	if r.pos >= r.end {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.end-r.pos {
+		p = p[:r.end-r.pos]
+	}
+	var n int
+	if !readMapped(func() { n = copy(p, r.data[r.pos:]) }) {
+		r.fallback = io.NewSectionReader(r.file, r.pos, r.end-r.pos)
+		return r.fallback.Read(p)
+	}
+	r.pos += int64(n)
+	return n, nil
+}
+
+// tailRecords walks back through data a line at a time, returning the last lines records
+// newest first. Only the returned records are copied. An unterminated last line is skipped
+func tailRecords(data []byte, lines int) []string {
+	result := make([]string, 0, lines)
+	data = data[:bytes.LastIndexByte(data, '\n')+1]
+	end := len(data)
+	for pos := len(data); pos > 0 && len(result) < lines; {
+		start := bytes.LastIndexByte(data[:pos], '\n') + 1
+		if recordStart(data[start:pos]) {
+			result = append(result, strings.Trim(strings.TrimRight(string(data[start:end]), "\r\n"), " "))
+			end = start
+		}
+		pos = start - 1
+	}
+	return result
+}
+
+// recordStart reports whether a line starts a record, as isRecordStart does, without
+// converting it to a string
+func recordStart(line []byte) bool {
+	for _, form := range recordForms {
+		if form.Match(line) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,8 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package logging
+
+// mmapFile is not supported on this platform, so reads fall back to ReadAt
+func mmapFile(file File, size int64) ([]byte, func() error, bool) {
+	return nil, nil, false
+}
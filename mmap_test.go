@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTailRecords(t *testing.T) {
+	data := []byte("[2024-01-02T00:00:00Z] [TEST.INFO] one\n[2024-01-02T00:00:01Z] [TEST.INFO] two\n  continued\n[2024-01-02T00:00:02Z] [TEST.INFO] three\n")
+	records := tailRecords(data, 2)
+	if len(records) != 2 || records[0] != "[2024-01-02T00:00:02Z] [TEST.INFO] three" || records[1] != "[2024-01-02T00:00:01Z] [TEST.INFO] two\n  continued" {
+		t.Errorf("unexpected records %q", records)
+	}
+	if records = tailRecords(data, 10); len(records) != 3 {
+		t.Errorf("expected every record when asking for more than there are, got %q", records)
+	}
+}
+
+func TestMmapReads(t *testing.T) {
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	now := start
+	ml, err := NewLog(filepath.Join(t.TempDir(), "app.log"), "TEST", LEVEL_INFO, LEVEL_NONE, WithMmap(), WithNow(func() time.Time {
+		return now
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 50; i++ {
+		now = start.Add(time.Duration(i) * time.Second)
+		ml.Infof("entry %d", i)
+	}
+	mapped, err := ml.GetLog(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := *ml
+	plain.mmap = false
+	read, _ := plain.GetLog(5)
+	read[0] = strings.TrimSuffix(read[0], "\n")
+	if fmt.Sprint(mapped) != fmt.Sprint(read) {
+		t.Errorf("expected mapped and plain reads to match, got %q and %q", mapped, read)
+	}
+	records, err := ml.ReadRange(start.Add(10*time.Second), start.Add(20*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 10 || !strings.HasSuffix(records[0], "entry 10") {
+		t.Errorf("unexpected records %q", records)
+	}
+}
+
+func TestMmapTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	ml, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithMmap())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ml.Info(strings.Repeat("x", 10000))
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	r, unmap, ok := ml.mappedReader(file, 0, 10000)
+	if !ok {
+		t.Skip("mapping is not supported on this platform")
+	}
+	defer unmap()
+	// as copytruncate rotation does while the log is being read
+	if err = os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := io.ReadAll(r); err != nil || len(b) != 0 {
+		t.Errorf("expected the read to fall back to the truncated file, got %d bytes and %v", len(b), err)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package logging
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile maps size bytes of an operating system file read-only
+func mmapFile(file File, size int64) ([]byte, func() error, bool) {
+	f, ok := file.(*os.File)
+	if !ok || size <= 0 || int64(int(size)) != size {
+		return nil, nil, false
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, false
+	}
+	return data, func() error { return syscall.Munmap(data) }, true
+}
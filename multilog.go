@@ -0,0 +1,63 @@
+package logging
+
+import "errors"
+
+// ErrUnknownEnv is returned when writing to an environment a MultiLog has no log for
+var ErrUnknownEnv = errors.New("unknown environment")
+
+// MultiLog routes each entry to the log for its environment and to a combined log, e.g. to
+// keep blue and green deployments running side by side in files of their own as well as a
+// shared one. Entries in the combined log carry the environment they were written for
+type MultiLog struct {
+	logs     map[string]*Log
+	combined *Log
+}
+
+// NewMultiLog returns a MultiLog writing to the log for each environment and to combined,
+// which may be nil
+func NewMultiLog(logs map[string]*Log, combined *Log) *MultiLog {
+	return &MultiLog{logs: logs, combined: combined}
+}
+
+// Env returns the log for an environment, or nil if there is none
+func (m *MultiLog) Env(env string) *Log {
+	return m.logs[env]
+}
+
+// Write writes an entry to the log for env and to the combined log, returning the entry as
+// written to the former and the first error encountered
+func (m *MultiLog) Write(env, message, level string) (result string, err error) {
+	l, ok := m.logs[env]
+	if !ok {
+		return "", ErrUnknownEnv
+	}
+	result, err = l.Write(message, level)
+	if m.combined != nil {
+		if _, cErr := m.combined.forEnv(env).Write(message, level); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+	return result, err
+}
+
+// Close closes every log, returning the first error encountered
+func (m *MultiLog) Close() (err error) {
+	for _, l := range m.logs {
+		if cErr := l.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+	if m.combined != nil {
+		if cErr := m.combined.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+// forEnv returns a child log writing entries for another environment
+func (l *Log) forEnv(env string) *Log {
+	child := *l
+	child.env = env
+	return &child
+}
@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMultiLog(t *testing.T) {
+	fs := NewMemFS()
+	open := func(path, env string) *Log {
+		l, err := NewLog(path, env, LEVEL_INFO, LEVEL_NONE, WithFS(fs))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return l
+	}
+	m := NewMultiLog(map[string]*Log{
+		"BLUE":  open("/logs/blue.log", "BLUE"),
+		"GREEN": open("/logs/green.log", "GREEN"),
+	}, open("/logs/all.log", "ALL"))
+	if _, err := m.Write("BLUE", "from blue", INFO); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Write("GREEN", "from green", WARNING); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Write("RED", "from red", INFO); err != ErrUnknownEnv {
+		t.Errorf("expected %v, got %v", ErrUnknownEnv, err)
+	}
+	blue, _ := m.Env("BLUE").readFile("/logs/blue.log")
+	if !strings.Contains(string(blue), "[BLUE.INFO] from blue") || strings.Contains(string(blue), "green") {
+		t.Errorf("unexpected blue log %q", string(blue))
+	}
+	all, _ := m.Env("BLUE").readFile("/logs/all.log")
+	if !strings.Contains(string(all), "[BLUE.INFO] from blue") || !strings.Contains(string(all), "[GREEN.WARNING] from green") {
+		t.Errorf("unexpected combined log %q", string(all))
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Write("BLUE", "closed", INFO); err != ErrClosed {
+		t.Errorf("expected %v, got %v", ErrClosed, err)
+	}
+}
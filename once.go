@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"sync"
+	"time"
+)
+
+// onceKeys records when each key passed to Once or OnceEvery was last allowed to log, shared
+// by a log and its children
+type onceKeys struct {
+	mu     sync.Mutex
+	logged map[string]time.Time
+}
+
+// Once returns the log the first time it is called with key on this log or its children and a
+// log that writes nothing afterwards, for deprecation notices and configuration warnings:
+// l.Once("deprecated-endpoint").Warning(...)
+func (l *Log) Once(key string) *Log {
+	return l.OnceEvery(key, 0)
+}
+
+// OnceEvery is like Once but allows the key to log again once interval has passed since it
+// last did; an interval of zero or less allows it only once
+func (l *Log) OnceEvery(key string, interval time.Duration) *Log {
+	now := l.now()
+	l.once.mu.Lock()
+	defer l.once.mu.Unlock()
+	last, seen := l.once.logged[key]
+	if seen && (interval <= 0 || now.Sub(last) < interval) {
+		return l.If(false)
+	}
+	l.once.logged[key] = now
+	return l
+}
@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOnce(t *testing.T) {
+	now := time.Now()
+	c := NewCapture(WithNow(func() time.Time { return now }))
+	for i := 0; i < 3; i++ {
+		c.Once("TestOnce").Warning("deprecated")
+		c.OnceEvery("TestOnceEvery", time.Minute).Warning("reloading")
+		now = now.Add(40 * time.Second)
+	}
+	counts := map[string]int{}
+	for _, e := range c.Entries() {
+		counts[e.Message]++
+	}
+	if counts["deprecated"] != 1 || counts["reloading"] != 2 {
+		t.Errorf("expected 1 deprecation warning and 2 reload warnings, got %v", counts)
+	}
+	// children share the keys of their log; other logs keep their own
+	before := len(c.Entries())
+	c.With(String("job", "1")).Once("TestOnce").Warning("deprecated")
+	other := NewCapture()
+	other.Reset()
+	other.Once("TestOnce").Warning("deprecated")
+	if len(c.Entries()) != before || len(other.Entries()) != 1 {
+		t.Errorf("expected the key to be scoped to the log, got %d more and %d entries", len(c.Entries())-before, len(other.Entries()))
+	}
+}
@@ -0,0 +1,46 @@
+package logging
+
+import "time"
+
+// Operation is a log for one multi-step operation, started by Begin. Entries written through it
+// are tagged with the operation's name and ID so they can be found together
+type Operation struct {
+	*Log
+	name  string
+	id    string
+	start time.Time
+}
+
+// Begin logs the start of an operation at INFO and returns it; entries written through the
+// operation, and the record logged by End, share its op and op_id attributes:
+//
+//	op := l.Begin("migration")
+//	op.Info("copying tables")
+//	op.End(err)
+func (l *Log) Begin(name string) *Operation {
+	id := NewRequestID()
+	op := &Operation{
+		Log:   l.With(String("op", name), String("op_id", id)),
+		name:  name,
+		id:    id,
+		start: l.now(),
+	}
+	op.Chain().Info(name + " started")
+	return op
+}
+
+// ID returns the operation's ID
+func (op *Operation) ID() string {
+	return op.id
+}
+
+// End logs the end of the operation with its duration, at INFO or, if err is not nil, at ERROR
+// with the error attached
+func (op *Operation) End(err error) {
+	elapsed := Duration("elapsed", op.now().Sub(op.start))
+	if err != nil {
+		op.With(elapsed, Err(err)).Chain().Error(op.name + " failed")
+		return
+	}
+	op.With(elapsed).Chain().Info(op.name + " finished")
+}
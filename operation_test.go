@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOperation(t *testing.T) {
+	now := time.Now()
+	c := NewCapture(WithNow(func() time.Time { return now }))
+	op := c.Begin("migration")
+	op.Info("copying tables")
+	now = now.Add(2 * time.Second)
+	op.End(errors.New("disk full"))
+	c.Info("unrelated")
+	entries := c.Entries()
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+	for _, e := range entries[:3] {
+		if id, _ := e.Field("op_id"); id != op.ID() {
+			t.Errorf("expected '%s' to carry the operation ID %s, got %v", e.Message, op.ID(), id)
+		}
+	}
+	if _, ok := entries[3].Field("op_id"); ok {
+		t.Error("expected entries outside the operation not to carry its ID")
+	}
+	end := entries[2]
+	elapsed, _ := end.Field("elapsed")
+	if end.Level != ERROR || end.Message != "migration failed" || elapsed != 2*time.Second {
+		t.Errorf("expected a failed end record after 2s, got %s '%s' after %v", end.Level, end.Message, elapsed)
+	}
+}
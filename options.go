@@ -0,0 +1,756 @@
+package logging
+
+import (
+	"os"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Option configures optional behaviour of a Log
+type Option func(*Log)
+
+// invalid records an invalid option, so that NewLog returns the first one
+func (l *Log) invalid(err error) {
+	if l.configErr == nil {
+		l.configErr = err
+	}
+}
+
+// WithSink adds a sink that receives every entry written to the log
+func WithSink(s Sink) Option {
+	return func(l *Log) {
+		l.sinks = append(l.sinks, s)
+	}
+}
+
+// WithRingBuffer keeps the last size entries in memory; see RecentEntries
+func WithRingBuffer(size int) Option {
+	return func(l *Log) {
+		l.ring = NewRingBuffer(size)
+		l.sinks = append(l.sinks, l.ring)
+	}
+}
+
+// WithRecentErrors keeps the last size WARNING or more severe entries in memory, including
+// those below the log level or that failed to be written; see LastErrors
+func WithRecentErrors(size int) Option {
+	return func(l *Log) {
+		l.recentErrors = NewRingBuffer(size)
+	}
+}
+
+// WithSampling writes only one of every n entries at the given level. The number of entries
+// left out is written as a warning at most every 10 seconds and when the log is closed
+func WithSampling(level string, n int) Option {
+	return func(l *Log) {
+		l.getSampler().every[strings.ToUpper(level)] = uint64(n)
+	}
+}
+
+// WithRateLimit limits entries at the given level to perSecond, allowing bursts of up to burst entries
+func WithRateLimit(level string, perSecond float64, burst int) Option {
+	return func(l *Log) {
+		l.getSampler().buckets[strings.ToUpper(level)] = &tokenBucket{
+			rate:   perSecond,
+			burst:  float64(burst),
+			tokens: float64(burst),
+		}
+	}
+}
+
+// WithDedup collapses consecutive identical entries into a single "message repeated N times" entry,
+// written when a different entry arrives or once window has passed since the first repeat
+func WithDedup(window time.Duration) Option {
+	return func(l *Log) {
+		l.dedup = &dedup{window: window}
+	}
+}
+
+// WithErrorRateAlert calls alert when threshold or more ERROR entries are written within window.
+// The alert fires once each time the threshold is crossed
+func WithErrorRateAlert(threshold int, window time.Duration, alert func(count int)) Option {
+	return func(l *Log) {
+		l.errorRate = &errorRate{threshold: threshold, window: window, alert: alert}
+	}
+}
+
+// WithRedactionProfile adds a named view of the log for a class of readers, used by GetLogAs
+// and ReadRangeAs. Readers who may see everything need a profile too, e.g. an empty one, as
+// reading through an unknown profile fails
+func WithRedactionProfile(name string, p *RedactionProfile) Option {
+	return func(l *Log) {
+		if l.profiles == nil {
+			l.profiles = make(map[string]*RedactionProfile)
+		}
+		l.profiles[name] = p
+	}
+}
+
+// WithCodeCatalog registers the event codes that may be given to entries with Code, and their
+// descriptions. Catalogs given in several options are merged
+func WithCodeCatalog(catalog CodeCatalog) Option {
+	return func(l *Log) {
+		if l.codes == nil {
+			l.codes = make(CodeCatalog, len(catalog))
+		}
+		for code, description := range catalog {
+			l.codes[code] = description
+		}
+	}
+}
+
+// WithEventTypes registers event types written with TypedEvent. A type registered again under
+// the same name replaces the earlier one
+func WithEventTypes(types ...EventType) Option {
+	return func(l *Log) {
+		if l.events == nil {
+			l.events = make(map[string]EventType, len(types))
+		}
+		for _, t := range types {
+			if t.Name == "" {
+				l.invalid(errEventName)
+				return
+			}
+			if t.Level == "" {
+				t.Level = INFO
+			}
+			if t.Message == "" {
+				t.Message = t.Name
+			}
+			l.events[t.Name] = t
+		}
+	}
+}
+
+// WithRateAnomalyAlert tracks a rolling baseline of entries per minute at each level and calls
+// alert for a minute in which a level's count rises above, or falls below, its baseline by more
+// than factor. Minutes are checked as they end, when the next entry arrives, and the alert fires
+// once each time a level starts deviating. With a nil alert a WARNING entry is written instead
+func WithRateAnomalyAlert(factor float64, alert func(a RateAnomaly)) Option {
+	return func(l *Log) {
+		if factor <= 1 {
+			l.invalid(errAnomalyFactor)
+			return
+		}
+		l.anomaly = &rateAnomaly{factor: factor, alert: alert, levels: make(map[string]*levelRate)}
+	}
+}
+
+// WithRedaction replaces anything matching the given patterns with [REDACTED] in messages, field
+// values and attributes, including errors, before entries are written or reported
+func WithRedaction(patterns ...*regexp.Regexp) Option {
+	return func(l *Log) {
+		for _, p := range patterns {
+			l.redactions = append(l.redactions, redaction{pattern: p, replacement: redacted})
+		}
+	}
+}
+
+// WithRedactedKeys redacts the values of key=value and key: value pairs for the given keys (case insensitive)
+func WithRedactedKeys(keys ...string) Option {
+	return func(l *Log) {
+		l.redactions = append(l.redactions, keyRedactions(keys)...)
+	}
+}
+
+// WithDefaultRedaction redacts passwords, secrets, bearer tokens, API keys and credit card numbers
+func WithDefaultRedaction() Option {
+	return func(l *Log) {
+		l.redactions = append(l.redactions, defaultRedactions...)
+	}
+}
+
+// WithIPAnonymization truncates the IPv4 and IPv6 addresses found in messages, field values
+// and attributes to the prefix lengths of opts, or replaces them with a keyed hash, before
+// entries are written or reported, e.g. for GDPR-compliant access logs
+func WithIPAnonymization(opts IPAnonymization) Option {
+	return func(l *Log) {
+		if err := opts.validate(); err != nil {
+			l.invalid(err)
+			return
+		}
+		if opts.Hash && l.hashKey == nil {
+			l.hashKey = newHashKey()
+		}
+		l.redactions = append(l.redactions, l.ipRedactions(opts)...)
+	}
+}
+
+// WithMaskedFields replaces the values of the given field keys with a fixed mask
+func WithMaskedFields(keys ...string) Option {
+	return func(l *Log) {
+		l.setMasks(maskHide, keys)
+	}
+}
+
+// WithHashedFields replaces the values of the given field keys with a keyed hash, so entries
+// can still be correlated by value without exposing it. Without WithHashKey a random key is
+// used, so hashes only match within the process
+func WithHashedFields(keys ...string) Option {
+	return func(l *Log) {
+		l.setMasks(maskHash, keys)
+	}
+}
+
+// WithHashKey sets the secret key of the hashes written by WithHashedFields, so that they
+// match across processes and restarts
+func WithHashKey(key []byte) Option {
+	return func(l *Log) {
+		l.hashKey = append([]byte(nil), key...)
+	}
+}
+
+// WithHashChain enables audit mode: every record written to the file ends with a hash chained
+// from the previous record, so later edits can be detected with Verify. Each file has its own
+// chain, restarted when the log is rotated; with WithFileLock, records appended by other
+// processes are chained too
+func WithHashChain() Option {
+	return func(l *Log) {
+		l.chain = &hashChain{}
+	}
+}
+
+// WithEncryption encrypts every record written to the file with AES-GCM. The key function
+// is called once, on first use, and must return a 16, 24 or 32 byte key; see KeyFromEnv
+func WithEncryption(key func() ([]byte, error)) Option {
+	return func(l *Log) {
+		l.cipher = &recordCipher{key: key}
+	}
+}
+
+// WithSigning appends an HMAC-SHA256 signature of each record, which can be checked with VerifySignature
+func WithSigning(key []byte) Option {
+	return func(l *Log) {
+		l.signingKey = key
+	}
+}
+
+// WithPath writes to path instead of the path passed to NewLog, for example to give a Clone
+// a file of its own
+func WithPath(path string) Option {
+	return func(l *Log) {
+		l.path = path
+	}
+}
+
+// WithCRLF ends records with CRLF instead of LF, for Windows tools that expect it. The log's
+// own readers accept either
+func WithCRLF() Option {
+	return func(l *Log) {
+		l.crlf = true
+	}
+}
+
+// WithDiscontinuityMarkers checks before each write whether the log file was truncated,
+// replaced or removed by something other than the log since its last write, and if so writes
+// a WARNING entry with the attribute discontinuity=true first, so audits can see the history
+// in the file was modified externally. Rotation by the log itself is not marked
+func WithDiscontinuityMarkers() Option {
+	return func(l *Log) {
+		l.tamperMarks = true
+	}
+}
+
+// WithMinFreeSpace sets the free space in bytes that Healthy requires on the filesystem of
+// the log file (default 16 MiB). Zero disables the check
+func WithMinFreeSpace(bytes int64) Option {
+	return func(l *Log) {
+		if bytes < 0 {
+			l.invalid(errMinFreeSpace)
+			return
+		}
+		l.minFree = bytes
+	}
+}
+
+// WithFileMode sets the permissions used when the log file is created (default 0666 before umask)
+func WithFileMode(mode os.FileMode) Option {
+	return func(l *Log) {
+		l.fileMode = mode
+	}
+}
+
+// WithCreateDirs creates missing parent directories of the log file with the given permissions
+func WithCreateDirs(mode os.FileMode) Option {
+	return func(l *Log) {
+		l.dirMode = mode
+	}
+}
+
+// WithOwner sets the owner of the log file when it is created
+func WithOwner(uid, gid int) Option {
+	return func(l *Log) {
+		l.owner = &fileOwner{uid: uid, gid: gid}
+	}
+}
+
+// WithSanitize escapes newlines and other control characters in messages, so that a single
+// write always produces exactly one line in the file
+func WithSanitize() Option {
+	return func(l *Log) {
+		l.sanitize = true
+	}
+}
+
+// WithSingleLine escapes newlines and carriage returns in messages as \n and \r, so that
+// every record occupies exactly one line. Unlike WithSanitize, other characters are kept
+func WithSingleLine() Option {
+	return func(l *Log) {
+		l.singleLine = true
+	}
+}
+
+// WithStripANSI removes ANSI escape sequences, such as terminal colours, from messages
+func WithStripANSI() Option {
+	return func(l *Log) {
+		l.stripANSI = true
+	}
+}
+
+// WithPreWriteHook adds a hook run before each entry is processed; see PreWriteHook
+func WithPreWriteHook(hook PreWriteHook) Option {
+	return func(l *Log) {
+		l.preHooks = append(l.preHooks, hook)
+	}
+}
+
+// WithPostWriteHook adds a hook run after each entry is successfully written to the file
+func WithPostWriteHook(hook PostWriteHook) Option {
+	return func(l *Log) {
+		l.postHooks = append(l.postHooks, hook)
+	}
+}
+
+// OnLevel adds a callback run after each entry at the given level is written, for example to
+// count errors in the application's own metrics
+func OnLevel(level string, fn func(e Entry)) Option {
+	return WithPostWriteHook(levelCallback(level, fn))
+}
+
+// WithFilter drops entries for which the filter returns false
+func WithFilter(filter Filter) Option {
+	return WithPreWriteHook(func(e *Entry) bool {
+		return filter(*e)
+	})
+}
+
+// WithNow replaces time.Now as the source of entry timestamps, e.g. for deterministic tests or backfilling
+func WithNow(now func() time.Time) Option {
+	return func(l *Log) {
+		l.clock = now
+	}
+}
+
+// WithFS sets the filesystem the log file is written to, e.g. a MemFS in tests
+func WithFS(fs FS) Option {
+	return func(l *Log) {
+		l.fs = fs
+	}
+}
+
+// WithRotationName sets the name pattern of files rotated by Rotate, e.g. "app-%Y%m%d-%03d.log".
+// %Y, %m, %d, %H, %M and %S are replaced with the parts of the rotation time in UTC and a width
+// followed by d, as in %03d, with a sequence number starting at 1 that makes the name unique.
+// If dailyDirs is set, rotated files are placed in a directory named for the day, e.g.
+// logs/2024-01-02/, beside the log file
+func WithRotationName(pattern string, dailyDirs bool) Option {
+	return func(l *Log) {
+		l.naming = &rotationNaming{pattern: pattern, dailyDirs: dailyDirs}
+	}
+}
+
+// WithRotateOnStartup rotates an existing log file when the log is created, so that each
+// run of the process starts a new file
+func WithRotateOnStartup() Option {
+	return func(l *Log) {
+		l.rotateOnStartup = true
+	}
+}
+
+// WithDiskBudget caps the combined size in bytes of the log file and its rotated backups.
+// The oldest backups are removed when the cap is exceeded, checked after each rotation and
+// periodically as entries are written, and the log file is rotated before a write that would
+// take it over the cap. NewLog fails if a rotation name set by WithRotationName starts with
+// a verb, as its rotated files could not be told apart from other files in the directory
+func WithDiskBudget(max int64) Option {
+	return func(l *Log) {
+		l.budget = &diskBudget{max: max}
+	}
+}
+
+// WithIndex maintains a sparse index of the log file, recording the offset and time of
+// every nth record in a hidden sidecar file, .<name>.idx, so that GetLog and ReadRange can
+// seek to the records they need instead of scanning the file. Encrypted logs are not indexed
+func WithIndex(every int) Option {
+	return func(l *Log) {
+		if every < 1 {
+			every = 1
+		}
+		l.index = &sparseIndex{every: every}
+	}
+}
+
+// WithMmap reads the log file through a read-only memory mapping in GetLog and ReadRange,
+// avoiding a copy of each chunk read from very large files. Reads fall back to the usual
+// path where mapping is unsupported, such as on a MemFS or Windows. The file must not be
+// truncated while it is being read
+func WithMmap() Option {
+	return func(l *Log) {
+		l.mmap = true
+	}
+}
+
+// WithQuarantine checks the log file when the log is created for an incomplete last record,
+// left by a crash in the middle of a write, and moves it to <path>.partial so that new
+// entries are not appended onto it. A warning is logged when a record is quarantined
+func WithQuarantine() Option {
+	return func(l *Log) {
+		l.quarantine = true
+	}
+}
+
+// WithCompression compresses each file rotated by Rotate, e.g. with a GzipCompressor or
+// ZstdCompressor, before it is archived
+func WithCompression(c Compressor) Option {
+	return func(l *Log) {
+		l.compressor = c
+	}
+}
+
+// WithArchive archives each file rotated by Rotate with the archiver, e.g. an S3Archiver,
+// removing the rotated file once it has been archived if deleteAfter is set
+func WithArchive(a Archiver, deleteAfter bool) Option {
+	return func(l *Log) {
+		l.archive = &archive{archiver: a, deleteAfter: deleteAfter}
+	}
+}
+
+// WithFallback sets a sink that receives entries the log file could not be written to.
+// The failure is still passed to the error handler
+func WithFallback(s Sink) Option {
+	return func(l *Log) {
+		l.fallback = s
+	}
+}
+
+// WithRetry retries failed writes to the log file according to the policy before
+// falling back; see WithFallback
+func WithRetry(policy RetryPolicy) Option {
+	return func(l *Log) {
+		l.retry = &policy
+	}
+}
+
+// WithDiskFullPolicy sets how writes are handled when the filesystem is full; see DiskFullPolicy
+func WithDiskFullPolicy(policy DiskFullPolicy) Option {
+	return func(l *Log) {
+		l.diskFull = &diskFullPolicy{policy: policy}
+	}
+}
+
+// WithSyncEveryWrite fsyncs the log file after every write, so entries survive power loss
+func WithSyncEveryWrite() Option {
+	return WithSyncInterval(0)
+}
+
+// WithSyncInterval fsyncs the log file after a write if at least interval has passed since the last sync
+func WithSyncInterval(interval time.Duration) Option {
+	return func(l *Log) {
+		l.durability = &syncPolicy{interval: interval}
+	}
+}
+
+// WithFileLock takes an exclusive advisory lock on the log file around every write, so several
+// processes can append to the same file without interleaving records. Each record is always
+// written with a single write to a file opened with O_APPEND
+func WithFileLock() Option {
+	return func(l *Log) {
+		l.lockFile = true
+	}
+}
+
+// WithWriteCoalescing batches the records of goroutines writing at the same time into a
+// single write and sync of the file, cutting the system calls made under load. A goroutine
+// writing alone is not delayed, and each still returns once its record is written
+func WithWriteCoalescing() Option {
+	return func(l *Log) {
+		l.coalescer = &coalescer{}
+	}
+}
+
+// WithAsync makes writes non-blocking: entries that pass the log level are queued and written
+// to the sinks and file by a background goroutine. When the queue is full an entry is dropped
+// according to the policy; see Dropped. Shutdown or Close drain the queue
+func WithAsync(queueSize int, policy DropPolicy) Option {
+	return func(l *Log) {
+		l.async = newAsyncQueue(l, queueSize, policy)
+	}
+}
+
+// WithBackpressureWarning writes a warning when the async queue or a queueing sink (see
+// QueueStatter) holds at least depth entries or its oldest entry has waited at least age.
+// Either threshold may be zero to disable it. Queues are checked every interval, or every 10
+// seconds if it is zero, and warned about again only after they have recovered
+func WithBackpressureWarning(depth int64, age, interval time.Duration) Option {
+	return func(l *Log) {
+		if interval <= 0 {
+			interval = defaultBackpressureInterval
+		}
+		l.backpressure = &backpressure{depth: depth, age: age, interval: interval, warned: make(map[string]bool)}
+	}
+}
+
+// WithWatchdog probes the write path in the background, checking it with Healthy and
+// flushing the log file, so that logging failing silently is noticed. Once the policy's number
+// of probes in a row have failed or exceeded the latency budget, the error handler is called
+// and the alert sink, if any, receives a CRITICAL entry. It fires again only after a probe
+// has succeeded
+func WithWatchdog(policy WatchdogPolicy) Option {
+	return func(l *Log) {
+		if policy.Interval <= 0 {
+			policy.Interval = defaultWatchdogInterval
+		}
+		if policy.Budget <= 0 {
+			policy.Budget = defaultWatchdogBudget
+		}
+		if policy.Failures < 1 {
+			policy.Failures = 1
+		}
+		l.watchdog = &watchdog{policy: policy}
+	}
+}
+
+// WithLatencyStats records how long each write and fsync takes, reported by Stats and
+// WriteMetrics as the median, 95th percentile and maximum
+func WithLatencyStats() Option {
+	return func(l *Log) {
+		l.latency = &latencies{}
+	}
+}
+
+// WithErrorExpansion makes WithError record the message of every wrapped and joined cause
+// as numbered attributes
+func WithErrorExpansion() Option {
+	return func(l *Log) {
+		l.expandErrors = true
+	}
+}
+
+// WithPrintLevel sets the level used by Print, Printf and Println; the default is INFO
+func WithPrintLevel(level string) Option {
+	return func(l *Log) {
+		l.printLevel = strings.ToUpper(level)
+	}
+}
+
+// WithStaticFields attaches the fields to every entry written by the log
+func WithStaticFields(fields Fields) Option {
+	return func(l *Log) {
+		l.attrs = append(l.attrs, fieldAttrs(fields)...)
+	}
+}
+
+// WithFieldProvider adds a function called as each entry is written, whose attributes are
+// added to the entry; it is not called for entries below the log and report levels
+func WithFieldProvider(provider FieldProvider) Option {
+	return func(l *Log) {
+		l.providers = append(l.providers, provider)
+	}
+}
+
+// WithProcessInfo attaches the hostname and process ID, read once when the log is created,
+// to every entry
+func WithProcessInfo() Option {
+	return func(l *Log) {
+		if host, err := os.Hostname(); err == nil {
+			l.attrs = append(l.attrs, String("hostname", host))
+		}
+		l.attrs = append(l.attrs, Int("pid", os.Getpid()))
+	}
+}
+
+// WithService attaches the service name and deployed version to every entry
+func WithService(name, version string) Option {
+	return func(l *Log) {
+		l.attrs = append(l.attrs, String("service", name))
+		if version != "" {
+			l.attrs = append(l.attrs, String("version", version))
+		}
+	}
+}
+
+// WithGoroutineID attaches the ID of the writing goroutine to every entry as "goroutine".
+// Reading the ID is slow, so this is meant for debugging concurrency issues in development
+func WithGoroutineID() Option {
+	return func(l *Log) {
+		l.goroutineID = true
+	}
+}
+
+// WithCaller attaches the file and line that wrote each entry, as "caller". Walking the stack
+// is slow, so this is meant for development
+func WithCaller() Option {
+	return func(l *Log) {
+		l.caller = true
+	}
+}
+
+// WithSequence numbers entries from 1, shared with child logs, so consumers can detect
+// dropped or reordered records. Only entries that pass the level, hooks and filters are
+// numbered, so a gap means a record was lost. The number is written as " seq=N" and parsed
+// into Entry.Seq
+func WithSequence() Option {
+	return func(l *Log) {
+		l.seq = &atomic.Uint64{}
+	}
+}
+
+// WithBuildInfo attaches the main module's version and VCS revision, read from the binary's
+// build information, to every entry, so a log identifies the binary that produced it
+func WithBuildInfo() Option {
+	return func(l *Log) {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			l.attrs = append(l.attrs, buildAttrs(info)...)
+		}
+	}
+}
+
+// WithTimePrecision writes timestamps with sub-second precision, such as time.Millisecond
+// or time.Microsecond, so entries written within the same second can be ordered
+func WithTimePrecision(precision time.Duration) Option {
+	return func(l *Log) {
+		l.precision = 0
+		for unit := time.Second; unit > precision && l.precision < 9; unit /= 10 {
+			l.precision++
+		}
+	}
+}
+
+// WithUptime attaches the time elapsed since the log was created to every entry as "uptime".
+// It is measured on the monotonic clock, so it is unaffected by wall clock jumps
+func WithUptime() Option {
+	return func(l *Log) {
+		l.uptime = true
+	}
+}
+
+// WithSlowThreshold makes Timed log operations taking longer than threshold at WARNING
+func WithSlowThreshold(threshold time.Duration) Option {
+	return func(l *Log) {
+		l.slow = threshold
+	}
+}
+
+// WithStartupBanner adds the env, log and report levels, hostname, PID, Go version and a
+// hash of the configuration to the "initialising log" entry
+func WithStartupBanner() Option {
+	return func(l *Log) {
+		l.banner = true
+	}
+}
+
+// WithCrashDump writes memory statistics and a dump of all goroutines to the log after a
+// fatal entry, before the process exits
+func WithCrashDump() Option {
+	return func(l *Log) {
+		l.crashDump = true
+	}
+}
+
+// WithMaxMessageLength truncates messages longer than max bytes, ending them with
+// "…[truncated N bytes]". Zero means no limit; NewLog fails if max is negative
+func WithMaxMessageLength(max int) Option {
+	return func(l *Log) {
+		if max < 0 {
+			l.invalid(errNegativeMessageLength)
+			return
+		}
+		if l.truncation == nil {
+			l.truncation = &truncation{}
+		}
+		l.truncation.max = max
+	}
+}
+
+// WithOverflowDir writes the full text of truncated messages to a file in dir, which must
+// exist, named in the truncated entry. It has no effect without WithMaxMessageLength
+func WithOverflowDir(dir string) Option {
+	return func(l *Log) {
+		if l.truncation == nil {
+			l.truncation = &truncation{}
+		}
+		l.truncation.dir = dir
+	}
+}
+
+// WithStandardLevels orders the levels conventionally, DEBUG < INFO < WARNING < ERROR, so that
+// the log and report levels are minimum severities: LEVEL_INFO writes INFO, WARNING and ERROR
+// entries but not DEBUG. By default the legacy ordering is kept, in which LEVEL_INFO is the
+// most verbose level and LEVEL_DEBUG excludes INFO entries
+func WithStandardLevels() Option {
+	return func(l *Log) {
+		l.standardLevels = true
+	}
+}
+
+// WithSyslogLevels accepts the syslog levels, LEVEL_NOTICE to LEVEL_EMERGENCY, as log and
+// report levels, writing only entries at least as severe. Without it they are taken as
+// LEVEL_INFO, as any level past it always has been. It applies to the levels passed to
+// NewLog and to those set later, so it goes before WithComponentLevels and WithEnvLevels
+func WithSyslogLevels() Option {
+	return func(l *Log) {
+		l.syslogScale = true
+	}
+}
+
+// WithComponentLevels sets the log levels of named components; see Named and
+// ParseComponentLevels
+func WithComponentLevels(levels map[string]Level) Option {
+	return func(l *Log) {
+		for component, level := range levels {
+			l.levels.components[component] = l.clampLevel(int(level))
+		}
+	}
+}
+
+// WithEnvLevels overrides the log level and component levels from the environment when the
+// log is created; see LoadEnvLevels
+func WithEnvLevels() Option {
+	return func(l *Log) {
+		l.LoadEnvLevels()
+	}
+}
+
+// WithReporter sends reports to r instead of the standard library logger
+func WithReporter(r Reporter) Option {
+	return func(l *Log) {
+		l.reporter = r
+	}
+}
+
+// WithReportLog reports entries by writing them to another log; see NewLogReporter
+func WithReportLog(target *Log) Option {
+	return WithReporter(NewLogReporter(target))
+}
+
+// WithEncoder sets the format of records written to the file; the default is TextEncoder.
+// The file is read back by GetLog, ReadRange and the other readers, so NewLog fails with
+// ErrUnreadableEncoder for a log file with a ConsoleEncoder or a W3CEncoder with its own
+// Fields, whose records cannot be parsed; use them with NewEncoderSink or WithReportEncoder.
+// Custom encoders should write records ParseEntry reads for the readers to work
+func WithEncoder(enc Encoder) Option {
+	return func(l *Log) {
+		l.encoder = enc
+	}
+}
+
+// WithReportEncoder formats reports with enc instead of the file's encoder, for example
+// ConsoleEncoder on the terminal while the file gets JSONEncoder
+func WithReportEncoder(enc Encoder) Option {
+	return func(l *Log) {
+		l.reportEncoder = enc
+	}
+}
@@ -0,0 +1,161 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const seqPrefix = " seq="
+
+// ErrMalformedRecord is returned when a record is not in the log file format
+var ErrMalformedRecord = errors.New("malformed log record")
+
+// ErrUnreadableEncoder is returned by NewLog for a log file encoder whose records ParseEntry
+// cannot read back; see WithEncoder
+var ErrUnreadableEncoder = errors.New("log file encoder writes records that cannot be read back")
+
+// readableEncoder reports whether ParseEntry reads the records of the package's encoder enc.
+// Custom encoders are assumed to be readable
+func readableEncoder(enc Encoder) bool {
+	switch enc := enc.(type) {
+	case ConsoleEncoder:
+		return false
+	case W3CEncoder:
+		return len(enc.Fields) == 0 || strings.Join(enc.Fields, " ") == strings.Join(DefaultW3CFields, " ")
+	case LevelEncoder:
+		for _, level := range enc.Levels {
+			if !readableEncoder(level) {
+				return false
+			}
+		}
+		return enc.Default == nil || readableEncoder(enc.Default)
+	}
+	return true
+}
+
+// ErrUnknownSchema is returned for a JSON record with a schema version this package does not
+// know, written by a newer version of it
+var ErrUnknownSchema = errors.New("unknown JSON schema version")
+
+// jsonDecoders decode the members of JSON records by schema version. Records written before
+// versioning have no schema_version and are version 0
+var jsonDecoders = map[int64]func(members map[string]interface{}) (Entry, error){
+	0: decodeJSONv1, // version 1 only added schema_version
+	1: decodeJSONv1,
+}
+
+var (
+	recordForm = regexp.MustCompile(`(?s)^\[([^\]]+)\] \[([^\]]*)\] ?(.*)$`)
+	seqSuffix  = regexp.MustCompile(` seq=(\d+)$`)
+)
+
+// ParseEntry parses a record in the text, JSON or Docker format, or one written by
+// LogfmtEncoder, CEFEncoder, CombinedLogEncoder or a W3CEncoder with the default fields. In
+// the text format, fields and attributes are not separated from the message, which holds the
+// rest of the record; in JSON, members other than time, env, level, message and seq are read
+// into Fields, as are the other values of the other formats (see their parsers). Any
+// signature and audit chain hash are dropped and the sequence number is read into Seq
+func ParseEntry(record string) (Entry, error) {
+	record, _, _ = chainSuffix.split(record)
+	record, _, _ = signatureSuffix.split(record)
+	// the single line formats, as GetLog returns the last record with its line end
+	line := strings.TrimRight(record, "\r\n")
+	switch {
+	case jsonForm.MatchString(record):
+		return parseJSONEntry(record)
+	case logfmtForm.MatchString(line):
+		return parseLogfmtEntry(line)
+	case cefForm.MatchString(line):
+		return parseCEFEntry(line)
+	case combinedForm.MatchString(line):
+		return parseCombinedEntry(line)
+	case w3cForm.MatchString(line):
+		return parseW3CEntry(line)
+	}
+	if dockerForm.MatchString(record) {
+		var docker struct {
+			Log string `json:"log"`
+		}
+		if err := json.Unmarshal([]byte(record), &docker); err != nil {
+			return Entry{}, ErrMalformedRecord
+		}
+		record = strings.TrimSuffix(docker.Log, "\n")
+	}
+	var e Entry
+	if m := seqSuffix.FindStringSubmatch(record); m != nil {
+		e.Seq, _ = strconv.ParseUint(m[1], 10, 64)
+		record = strings.TrimSuffix(record, m[0])
+	}
+	m := recordForm.FindStringSubmatch(record)
+	if m == nil {
+		return Entry{}, ErrMalformedRecord
+	}
+	t, err := time.Parse(time.RFC3339Nano, m[1])
+	if err != nil {
+		return Entry{}, ErrMalformedRecord
+	}
+	e.Time, e.precision = t, timePrecision(m[1])
+	if i := strings.LastIndexByte(m[2], '.'); i >= 0 {
+		e.Env, e.Level = m[2][:i], m[2][i+1:]
+	} else {
+		e.Level = m[2]
+	}
+	e.Message = m[3]
+	return e, nil
+}
+
+func parseJSONEntry(record string) (Entry, error) {
+	dec := json.NewDecoder(strings.NewReader(record))
+	dec.UseNumber()
+	var members map[string]interface{}
+	if err := dec.Decode(&members); err != nil {
+		return Entry{}, ErrMalformedRecord
+	}
+	var version int64
+	if v, ok := members["schema_version"].(json.Number); ok {
+		var err error
+		if version, err = v.Int64(); err != nil {
+			return Entry{}, ErrMalformedRecord
+		}
+	}
+	decode, ok := jsonDecoders[version]
+	if !ok {
+		return Entry{}, ErrUnknownSchema
+	}
+	return decode(members)
+}
+
+func decodeJSONv1(members map[string]interface{}) (Entry, error) {
+	stamp, _ := members["time"].(string)
+	t, err := time.Parse(time.RFC3339Nano, stamp)
+	if err != nil {
+		return Entry{}, ErrMalformedRecord
+	}
+	e := Entry{Time: t, precision: timePrecision(stamp)}
+	e.Env, _ = members["env"].(string)
+	e.Level, _ = members["level"].(string)
+	e.Message, _ = members["message"].(string)
+	if seq, ok := members["seq"].(json.Number); ok {
+		e.Seq, _ = strconv.ParseUint(seq.String(), 10, 64)
+	}
+	for _, k := range []string{"time", "schema_version", "env", "level", "message", "seq"} {
+		delete(members, k)
+	}
+	if len(members) > 0 {
+		e.Fields = members
+	}
+	return e, nil
+}
+
+// timePrecision returns the number of fractional second digits in an RFC3339 timestamp
+func timePrecision(stamp string) int {
+	i := strings.IndexByte(stamp, '.')
+	if i < 0 {
+		return 0
+	}
+	return len(stamp[i+1:]) - len(strings.TrimLeft(stamp[i+1:], "0123456789"))
+}
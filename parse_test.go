@@ -0,0 +1,176 @@
+package logging
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseEntry(t *testing.T) {
+	e, err := ParseEntry("[2023-03-01T10:04:05Z] [prod.eu.WARNING] disk at 91% mount=/var seq=12 sig=" + strings.Repeat("a", 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Entry{
+		Time:    time.Date(2023, 3, 1, 10, 4, 5, 0, time.UTC),
+		Env:     "prod.eu",
+		Level:   WARNING,
+		Message: "disk at 91% mount=/var",
+		Seq:     12,
+	}
+	if e.String() != expected.String() || e.Seq != 12 {
+		t.Errorf("expected %s, got %s", expected, e)
+	}
+	if _, err := ParseEntry("not a record"); err != ErrMalformedRecord {
+		t.Errorf("expected a malformed record error, got %v", err)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	c := NewCapture(WithSequence())
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.WithFields(Fields{"worker": true}).Info("tick")
+		}()
+	}
+	wg.Wait()
+	seen := make(map[uint64]bool)
+	for _, e := range c.Entries() {
+		parsed, err := ParseEntry(e.String())
+		if err != nil || parsed.Seq != e.Seq {
+			t.Fatalf("expected the sequence number to round trip, got %d from %s", parsed.Seq, e)
+		}
+		seen[e.Seq] = true
+	}
+	for seq := uint64(2); seq <= 51; seq++ { // 1 is the initialising entry
+		if !seen[seq] {
+			t.Errorf("expected sequence number %d to be used", seq)
+		}
+	}
+	// entries below the level are not numbered, so the next one follows on
+	c.SetLevel(LevelWarning)
+	c.Info("filtered")
+	c.Warning("kept")
+	if entries := c.Entries(); entries[len(entries)-1].Seq != 52 {
+		t.Errorf("expected no gap for a filtered entry, got %s", entries[len(entries)-1])
+	}
+}
+
+func TestParseEntryPrecision(t *testing.T) {
+	c := NewCapture(WithTimePrecision(time.Microsecond), WithNow(func() time.Time {
+		return time.Date(2023, 3, 1, 10, 4, 5, 678901234, time.UTC)
+	}))
+	c.Info("precise")
+	record := c.Entries()[0].String()
+	if record != "[2023-03-01T10:04:05.678901Z] [TEST.INFO] precise" {
+		t.Fatalf("expected microsecond precision, got %s", record)
+	}
+	e, err := ParseEntry(record)
+	if err != nil || e.Time.Nanosecond() != 678901000 || e.String() != record {
+		t.Errorf("expected the sub-second record to round trip, got %s (%v)", e, err)
+	}
+}
+
+func TestGetLogPrecision(t *testing.T) {
+	pl := NewTestLog(t, WithTimePrecision(time.Millisecond))
+	pl.Info("first\ncontinued")
+	pl.Info("second")
+	result, err := pl.GetLog(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, record := range result {
+		found = found || strings.HasSuffix(record, "] [TEST.INFO] first\ncontinued")
+	}
+	if len(result) != 3 || !found {
+		t.Errorf("expected millisecond records to be split correctly, got %q", result)
+	}
+}
+
+func TestParseJSONSchemaVersions(t *testing.T) {
+	records := []string{
+		`{"time":"2023-03-01T10:00:00Z","env":"api","level":"INFO","message":"started","job":7}`,
+		`{"time":"2023-03-01T10:00:00Z","schema_version":1,"env":"api","level":"INFO","message":"started","job":7}`,
+	}
+	for _, record := range records {
+		e, err := ParseEntry(record)
+		if err != nil || e.Message != "started" || len(e.Fields) != 1 || e.Fields["job"] != json.Number("7") {
+			t.Errorf("expected '%s' to parse with only the job field, got %+v, %v", record, e, err)
+		}
+	}
+	if _, err := ParseEntry(`{"time":"2023-03-01T10:00:00Z","schema_version":99,"msg":"started"}`); err != ErrUnknownSchema {
+		t.Errorf("expected a newer schema to be rejected, got %v", err)
+	}
+}
+
+func TestReadEncodedFiles(t *testing.T) {
+	start := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	for name, enc := range map[string]Encoder{
+		"logfmt":   LogfmtEncoder{},
+		"cef":      CEFEncoder{Vendor: "Acme", Version: "1"},
+		"combined": CombinedLogEncoder{},
+		"w3c":      W3CEncoder{},
+	} {
+		now := start
+		el, err := NewLog("/logs/app.log", "web", LEVEL_INFO, LEVEL_NONE, WithFS(NewMemFS()), WithEncoder(enc),
+			WithNow(func() time.Time { return now }))
+		if err != nil {
+			t.Fatal(err)
+		}
+		access := el.With(String("method", "GET"), String("path", "/a"), Int("status", 404), String("remote_addr", "10.0.0.7:5000"))
+		for i := 0; i < 3; i++ {
+			now = now.Add(time.Second)
+			access.Write("GET /a 404", WARNING)
+		}
+		records, err := el.GetLog(10)
+		if err != nil || len(records) != 4 {
+			t.Fatalf("%s: expected GetLog to read four records, got %q and %v", name, records, err)
+		}
+		e, err := ParseEntry(records[3])
+		if err != nil || e.Level != WARNING || e.Message != "GET /a 404" || !e.Time.Equal(now) {
+			t.Errorf("%s: expected the last record to parse, got %s and %v", name, e, err)
+		}
+		if path, _ := e.Field("path"); path != "/a" {
+			t.Errorf("%s: expected the path to be read back, got %v", name, path)
+		}
+		records, err = el.ReadRange(start.Add(2*time.Second), now)
+		if err != nil || len(records) != 1 {
+			t.Errorf("%s: expected ReadRange to read one record, got %q and %v", name, records, err)
+		}
+	}
+}
+
+func TestUnreadableEncoder(t *testing.T) {
+	for _, enc := range []Encoder{ConsoleEncoder{}, W3CEncoder{Fields: []string{"x-message"}}, LevelEncoder{Default: ConsoleEncoder{}}} {
+		if _, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(NewMemFS()), WithEncoder(enc)); err != ErrUnreadableEncoder {
+			t.Errorf("expected %T to be rejected for the log file, got %v", enc, err)
+		}
+	}
+}
+
+func TestParseEncodedRoundTrip(t *testing.T) {
+	e := Entry{
+		Time:    time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		Env:     "api",
+		Level:   ERROR,
+		Message: "failed\n\tat main.go:12",
+		Fields:  Fields{"query": "a=b c|d"},
+		Seq:     9,
+	}
+	for _, enc := range []Encoder{LogfmtEncoder{}, CEFEncoder{Vendor: "Ac|me"}} {
+		record := string(enc.AppendEntry(nil, e))
+		parsed, err := ParseEntry(record)
+		if err != nil || parsed.Message != e.Message || parsed.Env != e.Env || parsed.Seq != 9 || !parsed.Time.Equal(e.Time) {
+			t.Errorf("%T: expected %s back, got %s and %v", enc, e, parsed, err)
+		}
+		if q, _ := parsed.Field("query"); q != "a=b c|d" {
+			t.Errorf("%T: expected the field to be read back, got %v", enc, q)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// quarantineSuffix is appended to the path of the file incomplete records are moved to
+const quarantineSuffix = ".partial"
+
+// completeLength returns the length of a file up to the end of its last complete line
+func completeLength(file io.ReaderAt, size int64) int64 {
+	b := make([]byte, 4096)
+	for end := size; end > 0; {
+		start := end - int64(len(b))
+		if start < 0 {
+			start = 0
+		}
+		n, _ := file.ReadAt(b[:end-start], start)
+		if i := bytes.LastIndexByte(b[:n], '\n'); i >= 0 {
+			return start + int64(i) + 1
+		}
+		end = start
+	}
+	return 0
+}
+
+// committedSize returns the length of the file up to its last complete record, read while no
+// record is being written, so that readers never see a record torn by a concurrent write.
+// Records written by other processes are only excluded with WithFileLock
+func (l *Log) committedSize(file File) (int64, error) {
+	info, err := func() (os.FileInfo, error) {
+		if l.mu != nil {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+		}
+		if l.lockFile {
+			unlock, err := lockFile(file)
+			if err != nil {
+				return nil, err
+			}
+			defer unlock()
+		}
+		return file.Stat()
+	}()
+	if err != nil {
+		return 0, err
+	}
+	// the file only grows, so everything before the size read is complete; the check
+	// covers records left incomplete by a crash
+	return completeLength(file, info.Size()), nil
+}
+
+// quarantinePartial moves an incomplete last record to the quarantine file, truncating the
+// log file to its last complete record
+func (l *Log) quarantinePartial() error {
+	if l.path == "" {
+		return nil
+	}
+	n, name, err := l.movePartial()
+	if err != nil || n == 0 {
+		return err
+	}
+	_, err = l.With(Int("bytes", n), String("file", name)).Write("quarantined incomplete record", WARNING)
+	return err
+}
+
+// movePartial does the work of quarantinePartial, returning the length of the record moved
+// and the quarantine file. With WithFileLock the log file is locked throughout, so that a
+// record another process is part way through writing is not taken for an incomplete one
+func (l *Log) movePartial() (int, string, error) {
+	file, err := l.getFS().OpenFile(l.path, os.O_RDWR, 0)
+	if os.IsNotExist(err) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+	if l.lockFile {
+		unlock, err := lockFile(file)
+		if err != nil {
+			return 0, "", err
+		}
+		defer unlock()
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return 0, "", err
+	}
+	complete := completeLength(file, info.Size())
+	if complete == info.Size() {
+		return 0, "", nil
+	}
+	partial := make([]byte, info.Size()-complete, info.Size()-complete+1)
+	if _, err = file.ReadAt(partial, complete); err != nil && err != io.EOF {
+		return 0, "", err
+	}
+	name := l.path + quarantineSuffix
+	mode := l.fileMode
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+	quarantined, err := l.getFS().OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, mode)
+	if err != nil {
+		return 0, "", err
+	}
+	_, err = quarantined.Write(append(partial, '\n'))
+	if cErr := quarantined.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	if err = file.Truncate(complete); err != nil {
+		return 0, "", err
+	}
+	return len(partial), name, nil
+}
@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const crashed = "[2024-01-02T00:00:00Z] [TEST.INFO] one\n[2024-01-02T00:00:01Z] [TEST.INFO] two\n[2024-01-02T00:00:02Z] [TEST.INF"
+
+func TestPartialRecordSkipped(t *testing.T) {
+	fs := NewMemFS()
+	f, _ := fs.OpenFile("/logs/app.log", os.O_CREATE|os.O_WRONLY, 0644)
+	f.Write([]byte(crashed))
+	pl := &Log{path: "/logs/app.log", fs: fs}
+	records, err := pl.ReadRange(time.Time{}, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil || len(records) != 2 {
+		t.Errorf("expected the incomplete record to be skipped, got %q and %v", records, err)
+	}
+	tail, err := pl.GetLog(1)
+	if err != nil || len(tail) != 1 || !strings.HasSuffix(strings.TrimSpace(tail[0]), "two") {
+		t.Errorf("expected the last complete record, got %q and %v", tail, err)
+	}
+	path := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(path, []byte(crashed), 0644)
+	var vErr *VerifyError
+	if err = Verify(path); !errors.As(err, &vErr) || vErr.Record != 3 || vErr.Reason != "is incomplete" {
+		t.Errorf("expected verification to flag the incomplete record, got %v", err)
+	}
+}
+
+func TestQuarantine(t *testing.T) {
+	fs := NewMemFS()
+	f, _ := fs.OpenFile("/logs/app.log", os.O_CREATE|os.O_WRONLY, 0644)
+	f.Write([]byte(crashed))
+	ql, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithQuarantine(), WithFileMode(0600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ql.readFile("/logs/app.log")
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[2], "quarantined incomplete record bytes=32 file=/logs/app.log.partial") {
+		t.Errorf("unexpected log file %q", string(b))
+	}
+	q, _ := ql.readFile("/logs/app.log.partial")
+	if string(q) != "[2024-01-02T00:00:02Z] [TEST.INF\n" {
+		t.Errorf("unexpected quarantine file %q", string(q))
+	}
+	if info, err := fs.Stat("/logs/app.log.partial"); err != nil || info.Mode() != 0600 {
+		t.Errorf("expected the quarantine file to have the log's mode, got %v", info.Mode())
+	}
+}
+
+func TestQuarantineFileLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(path, []byte(crashed), 0644)
+	if _, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithQuarantine(), WithFileLock()); err != nil {
+		t.Fatal(err)
+	}
+	q, _ := os.ReadFile(path + quarantineSuffix)
+	b, _ := os.ReadFile(path)
+	if string(q) != "[2024-01-02T00:00:02Z] [TEST.INF\n" || !strings.Contains(string(b), "quarantined incomplete record") {
+		t.Errorf("expected the record to be quarantined under the file lock, got %q and %q", q, b)
+	}
+}
+
+// tornFS writes each record in two halves, so a concurrent reader can see half a record
+type tornFS struct {
+	FS
+}
+
+type tornFile struct {
+	File
+}
+
+func (fs tornFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	f, err := fs.FS.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return tornFile{f}, nil
+}
+
+func (f tornFile) Write(p []byte) (int, error) {
+	n, err := f.File.Write(p[:len(p)/2])
+	if err != nil {
+		return n, err
+	}
+	time.Sleep(100 * time.Microsecond)
+	m, err := f.File.Write(p[n:])
+	return n + m, err
+}
+
+func TestConsistentReads(t *testing.T) {
+	cl, err := NewLog(filepath.Join(t.TempDir(), "consistent.log"), "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(tornFS{osFS{}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			cl.Info("first line\n" + strings.Repeat("x", 100) + "\nlast line")
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		lines, err := cl.GetLog(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, _ := cl.ReadRange(time.Time{}, time.Now().Add(time.Hour))
+		for _, record := range append(lines, records...) {
+			if strings.Contains(record, "first line") && !strings.HasSuffix(strings.TrimRight(record, "\n"), "\nlast line") {
+				t.Fatalf("expected only whole records, got '%s'", record)
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package logging
+
+import "os"
+
+// Development returns a log for local development: every entry from DEBUG up is written to
+// the file with the caller's file and line, and shown in colour on stderr. Options are applied
+// after the preset's, so they can override it
+func Development(path string, opts ...Option) (*Log, error) {
+	preset := []Option{
+		WithStandardLevels(),
+		WithCaller(),
+		WithReporter(NewWriterReporter(os.Stderr)),
+		WithReportEncoder(ConsoleEncoder{}),
+	}
+	return NewLog(path, "DEVELOPMENT", LEVEL_DEBUG, LEVEL_DEBUG, append(preset, opts...)...)
+}
+
+// Production returns a log for production: entries from INFO up are written to the file as
+// JSON, and the previous file is rotated and gzipped when the log is created. Options are
+// applied after the preset's, so they can override it
+func Production(path string, opts ...Option) (*Log, error) {
+	preset := []Option{
+		WithStandardLevels(),
+		WithEncoder(JSONEncoder{}),
+		WithRotateOnStartup(),
+		WithCompression(GzipCompressor{}),
+	}
+	return NewLog(path, "PRODUCTION", LEVEL_INFO, LEVEL_NONE, append(preset, opts...)...)
+}
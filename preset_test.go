@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPresets(t *testing.T) {
+	dir := t.TempDir()
+	var console bytes.Buffer
+	dev, err := Development(filepath.Join(dir, "dev.log"), WithReporter(NewWriterReporter(&console)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, _ := dev.Debug("connecting")
+	if !strings.Contains(result, "caller=preset_test.go:") || !strings.Contains(console.String(), "connecting") {
+		t.Errorf("expected a debug entry with its caller in the file and on the console, got '%s' and '%s'", result, console.String())
+	}
+	prod, err := Production(filepath.Join(dir, "prod.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := prod.Debug("connecting"); result != "" {
+		t.Errorf("expected debug entries not to be written in production, got '%s'", result)
+	}
+	if result, _ := prod.Info("started"); !strings.HasPrefix(result, `{"time":`) {
+		t.Errorf("expected a JSON record in production, got '%s'", result)
+	}
+	prod.Close()
+	if prod, err = Production(filepath.Join(dir, "prod.log")); err != nil {
+		t.Fatal(err)
+	}
+	defer prod.Close()
+	if matches, _ := filepath.Glob(filepath.Join(dir, "prod.log.*.gz")); len(matches) != 1 {
+		t.Errorf("expected the previous production log to be rotated and compressed, got %v", matches)
+	}
+}
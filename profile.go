@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrUnknownProfile is returned when reading through a redaction profile that is not configured
+var ErrUnknownProfile = errors.New("unknown redaction profile")
+
+// RedactionProfile is the view of the log given to a class of readers, e.g. support staff:
+// the values of sensitive fields are masked and anything matching its patterns is replaced
+// with [REDACTED] as records are read, while the file itself keeps full detail. Signatures
+// and audit chain hashes of records it changes no longer verify
+type RedactionProfile struct {
+	fields   *regexp.Regexp
+	patterns []*regexp.Regexp
+}
+
+// NewRedactionProfile returns a profile masking the values of the given field and attribute
+// keys, matched case insensitively in both the text and JSON formats, and redacting patterns
+func NewRedactionProfile(fields []string, patterns ...*regexp.Regexp) *RedactionProfile {
+	p := &RedactionProfile{patterns: patterns}
+	if len(fields) > 0 {
+		quoted := make([]string, len(fields))
+		for i, f := range fields {
+			quoted[i] = regexp.QuoteMeta(f)
+		}
+		p.fields = regexp.MustCompile(`(?i)\b((?:` + strings.Join(quoted, "|") + `)"?\s*[=:]\s*)("(?:[^"\\]|\\.)*"|[^\s,;&\]})]+)`)
+	}
+	return p
+}
+
+// Apply returns record as seen through the profile. Quoted values stay quoted, so JSON
+// records remain valid
+func (p *RedactionProfile) Apply(record string) string {
+	if p.fields != nil {
+		record = p.fields.ReplaceAllStringFunc(record, func(match string) string {
+			m := p.fields.FindStringSubmatch(match)
+			if strings.HasPrefix(m[2], `"`) {
+				return m[1] + `"` + fieldMask + `"`
+			}
+			return m[1] + fieldMask
+		})
+	}
+	for _, pattern := range p.patterns {
+		record = pattern.ReplaceAllString(record, redacted)
+	}
+	return record
+}
+
+// profile returns the named redaction profile
+func (l *Log) profile(name string) (*RedactionProfile, error) {
+	p, ok := l.profiles[name]
+	if !ok {
+		return nil, ErrUnknownProfile
+	}
+	return p, nil
+}
+
+func applyProfile(p *RedactionProfile, records []string) []string {
+	for i, record := range records {
+		records[i] = p.Apply(record)
+	}
+	return records
+}
+
+// GetLogAs returns lines of the log as seen through the named redaction profile; see
+// WithRedactionProfile and GetLog
+func (l *Log) GetLogAs(profile string, lines uint) ([]string, error) {
+	p, err := l.profile(profile)
+	if err != nil {
+		return nil, err
+	}
+	records, err := l.GetLog(lines)
+	return applyProfile(p, records), err
+}
+
+// ReadRangeAs returns the records written from from up to but not including to as seen
+// through the named redaction profile; see WithRedactionProfile and ReadRange
+func (l *Log) ReadRangeAs(profile string, from, to time.Time) ([]string, error) {
+	p, err := l.profile(profile)
+	if err != nil {
+		return nil, err
+	}
+	records, err := l.ReadRange(from, to)
+	return applyProfile(p, records), err
+}
@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedactionProfileApply(t *testing.T) {
+	p := NewRedactionProfile([]string{"email", "IP"}, regexp.MustCompile(`acct-\d+`))
+	cases := map[string]string{
+		"[2024-01-02T00:00:00Z] [TEST.INFO] login email=a@b.com ip=10.0.0.1 acct-42": "[2024-01-02T00:00:00Z] [TEST.INFO] login email=**** ip=**** [REDACTED]",
+		`{"time":"2024-01-02T00:00:00Z","message":"login","email":"a@b.com"}`:        `{"time":"2024-01-02T00:00:00Z","message":"login","email":"****"}`,
+	}
+	for record, expected := range cases {
+		if view := p.Apply(record); view != expected {
+			t.Errorf("expected %q, got %q", expected, view)
+		}
+	}
+}
+
+func TestReadAs(t *testing.T) {
+	ml, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(NewMemFS()),
+		WithRedactionProfile("support", NewRedactionProfile([]string{"email"})),
+		WithRedactionProfile("admin", NewRedactionProfile(nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ml.With(String("email", "a@b.com")).Info("signed up")
+	records, err := ml.GetLogAs("support", 1)
+	if err != nil || len(records) != 1 || !strings.Contains(records[0], "email=****") {
+		t.Errorf("expected the email to be masked, got %v and %v", records, err)
+	}
+	records, err = ml.ReadRangeAs("admin", time.Time{}, time.Now().Add(time.Hour))
+	if err != nil || len(records) != 2 || !strings.Contains(records[1], "email=a@b.com") {
+		t.Errorf("expected full detail for admin, got %v and %v", records, err)
+	}
+	if _, err = ml.GetLogAs("guest", 1); err != ErrUnknownProfile {
+		t.Errorf("expected an unknown profile to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"sync"
+	"time"
+)
+
+// Progress accumulates counters for a batch job and logs them as a single summary entry
+// every so many items or so much time, instead of an entry per item; see Log.Progress
+type Progress struct {
+	l        *Log
+	name     string
+	every    int64
+	interval time.Duration
+	mu       sync.Mutex
+	counters []string // in the order first counted
+	totals   map[string]int64
+	pending  int64 // items counted since the last summary
+	start    time.Time
+	last     time.Time
+}
+
+// Progress returns counters for the named job, logging their totals at INFO after every
+// items have been counted or interval has passed since the last summary, whichever comes
+// first; zero disables either trigger:
+//
+//	p := l.Progress("import", 10000, time.Minute)
+//	p.Inc("processed")
+//	p.Done()
+func (l *Log) Progress(name string, every int, interval time.Duration) *Progress {
+	now := l.now()
+	return &Progress{
+		l:        l,
+		name:     name,
+		every:    int64(every),
+		interval: interval,
+		totals:   make(map[string]int64),
+		start:    now,
+		last:     now,
+	}
+}
+
+// Inc adds one to the counter
+func (p *Progress) Inc(counter string) {
+	p.Add(counter, 1)
+}
+
+// Add adds n to the counter, logging a summary if one is due
+func (p *Progress) Add(counter string, n int64) {
+	now := p.l.now()
+	p.mu.Lock()
+	if _, ok := p.totals[counter]; !ok {
+		p.counters = append(p.counters, counter)
+	}
+	p.totals[counter] += n
+	p.pending += n
+	due := (p.every > 0 && p.pending >= p.every) || (p.interval > 0 && now.Sub(p.last) >= p.interval)
+	var attrs []Attr
+	if due {
+		attrs = p.summary(now)
+	}
+	p.mu.Unlock()
+	if due {
+		p.l.With(attrs...).Chain().Info(p.name + " progress")
+	}
+}
+
+// Done logs the final totals and the time taken
+func (p *Progress) Done() {
+	now := p.l.now()
+	p.mu.Lock()
+	attrs := append(p.summary(now), Duration("elapsed", now.Sub(p.start)))
+	p.mu.Unlock()
+	p.l.With(attrs...).Chain().Info(p.name + " finished")
+}
+
+// summary returns the totals as attributes and starts the next period; p.mu must be held
+func (p *Progress) summary(now time.Time) []Attr {
+	attrs := make([]Attr, 0, len(p.counters)+1)
+	for _, counter := range p.counters {
+		attrs = append(attrs, Int64(counter, p.totals[counter]))
+	}
+	p.pending = 0
+	p.last = now
+	return attrs
+}
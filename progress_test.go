@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	now := time.Now()
+	c := NewCapture(WithNow(func() time.Time { return now }))
+	p := c.Progress("import", 3, time.Minute)
+	for i := 0; i < 7; i++ {
+		if i%2 == 0 {
+			p.Inc("processed")
+		} else {
+			p.Inc("skipped")
+		}
+	}
+	now = now.Add(time.Minute)
+	p.Inc("failed")
+	p.Done()
+	entries := c.Entries()
+	if len(entries) != 4 {
+		t.Fatalf("expected 2 summaries by count, 1 by time and a final one, got %d entries", len(entries))
+	}
+	final := entries[3]
+	processed, _ := final.Field("processed")
+	skipped, _ := final.Field("skipped")
+	failed, _ := final.Field("failed")
+	if final.Message != "import finished" || processed != int64(4) || skipped != int64(3) || failed != int64(1) {
+		t.Errorf("unexpected final summary '%s' %v", final.Message, final.Attrs)
+	}
+}
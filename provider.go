@@ -0,0 +1,19 @@
+package logging
+
+// FieldProvider returns attributes to add to an entry when it is written, for context that
+// changes too often to attach with With, such as the current tenant or memory usage
+type FieldProvider func() []Attr
+
+// provide appends the attributes of the field providers to an entry that will be written or
+// reported, after the log's own attributes
+func (l *Log) provide(e Entry) Entry {
+	if len(l.providers) == 0 || !l.Enabled(e.Level) {
+		return e
+	}
+	attrs := e.Attrs[:len(e.Attrs):len(e.Attrs)]
+	for _, provider := range l.providers {
+		attrs = append(attrs, provider()...)
+	}
+	e.Attrs = attrs
+	return e
+}
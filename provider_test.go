@@ -0,0 +1,27 @@
+package logging
+
+import "testing"
+
+func TestFieldProvider(t *testing.T) {
+	calls := 0
+	tenant := "acme"
+	c := NewCapture(WithFieldProvider(func() []Attr {
+		calls++
+		return []Attr{String("tenant", tenant)}
+	}))
+	c.SetLevel(LEVEL_WARNING)
+	calls = 0
+	c.Warning("first")
+	tenant = "globex"
+	c.Warning("second")
+	c.Info("skipped")
+	entries := c.Entries()
+	for i, expected := range []string{"acme", "globex"} {
+		if v, _ := entries[i].Field("tenant"); v != expected {
+			t.Errorf("expected entry %d to have tenant %s, got %v", i, expected, v)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected the provider to be called only for written entries, got %d calls", calls)
+	}
+}
@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// DirectProxy passed to NewHTTPClient connects directly, even when a proxy is set in the
+// environment
+const DirectProxy = "direct"
+
+// NewHTTPClient returns a client for sinks and archivers sending over HTTP, such as
+// S3Archiver, making its connections with the TLS configuration if it is not nil (see
+// TLSOptions) and through the proxy at the proxy URL, e.g. "http://proxy.internal:3128".
+// With an empty proxy the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are
+// respected, as they are by http.DefaultClient; DirectProxy ignores them
+func NewHTTPClient(cfg *tls.Config, proxy string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = cfg
+	switch proxy {
+	case "":
+		transport.Proxy = http.ProxyFromEnvironment
+	case DirectProxy:
+		transport.Proxy = nil
+	default:
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("proxy '%s' is not a URL such as http://host:port", proxy)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+	return &http.Client{Transport: transport}, nil
+}
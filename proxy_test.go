@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewHTTPClientProxy(t *testing.T) {
+	proxied := make(chan string, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied <- r.URL.String()
+		io.WriteString(w, "ok")
+	}))
+	defer proxy.Close()
+	client, err := NewHTTPClient(nil, proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Post("http://collector.invalid/ingest", "text/plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if u := <-proxied; u != "http://collector.invalid/ingest" {
+		t.Errorf("expected the request to go through the proxy, got %s", u)
+	}
+	if _, err = NewHTTPClient(nil, "proxy.internal:3128"); err == nil {
+		t.Error("expected a proxy without a scheme to be refused")
+	}
+}
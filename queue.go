@@ -0,0 +1,470 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// queueSuffix names the segment files of a shipping queue, each named by the sequence
+	// number of its first line
+	queueSuffix = ".queue"
+	// queueCursor names the file recording how far a shipping queue has been sent
+	queueCursor = "cursor"
+	// queueKeepSuffix names the file beside a segment listing the lines in it that are kept
+	queueKeepSuffix = ".keep"
+	// queueSegmentSize is the size at which a new segment is started
+	queueSegmentSize = 1 << 20
+	// queueKeptLimit is how many times its cap a queue may grow to with the lines kept
+	queueKeptLimit = 2
+)
+
+// shipQueue holds the encoded lines of a sink waiting to be shipped. Lines are numbered in
+// sequence; the lines before the cursor have been shipped. Only the shipper commits, so lines
+// peeked stay valid while they are sent, even if they are dropped meanwhile. Lines pushed with
+// keep set do not count towards the cap, and are moved to the tail when the lines around them
+// are dropped, so they may be shipped out of order. They are only dropped, oldest first, once
+// the queue reaches queueKeptLimit times its cap, so that it stays bounded
+type shipQueue interface {
+	// push appends a line, returning the number of old lines dropped to make room, not
+	// counting those kept, and the number of lines kept dropped
+	push(line []byte, keep bool) (dropped, droppedKept int, err error)
+	// peek returns up to max lines from the cursor joined in one body, and the position after them
+	peek(max int) (body []byte, n int, end queuePos, err error)
+	// commit moves the cursor to end, once the lines before it are shipped
+	commit(end queuePos) error
+	// cursor and tail are the sequence numbers of the first line not shipped and the next line
+	cursor() uint64
+	tail() uint64
+	close() error
+}
+
+// oneLine escapes the line breaks in an encoded record as \n and \r, so that it is one line of
+// a queue, whose lines are counted on reopening, and of a newline-delimited body
+func oneLine(record []byte) []byte {
+	if bytes.IndexAny(record, "\r\n") < 0 {
+		return record
+	}
+	return []byte(escapeLineBreaks(string(record)))
+}
+
+// queuePos is a position in a queue: the sequence number of a line and, in a diskQueue, its
+// offset in the segment holding it
+type queuePos struct {
+	seq    uint64
+	offset int64
+}
+
+// memQueue is a shipQueue in memory holding up to max lines besides those kept, and up to
+// queueKeptLimit times max in all
+type memQueue struct {
+	lines [][]byte
+	keep  []bool
+	kept  int
+	head  uint64
+	max   int
+}
+
+func (q *memQueue) push(line []byte, keep bool) (dropped, droppedKept int, err error) {
+	q.append(append([]byte(nil), line...), keep)
+	for len(q.lines)-q.kept > q.max || len(q.lines) > queueKeptLimit*q.max {
+		full := len(q.lines) > queueKeptLimit*q.max
+		line, keep := q.lines[0], q.keep[0]
+		q.lines, q.keep = q.lines[1:], q.keep[1:]
+		q.head++
+		if keep {
+			q.kept--
+		}
+		switch {
+		case keep && !full:
+			q.append(line, true)
+		case keep:
+			droppedKept++
+		default:
+			dropped++
+		}
+	}
+	return dropped, droppedKept, nil
+}
+
+func (q *memQueue) append(line []byte, keep bool) {
+	q.lines = append(q.lines, line)
+	q.keep = append(q.keep, keep)
+	if keep {
+		q.kept++
+	}
+}
+
+func (q *memQueue) peek(max int) ([]byte, int, queuePos, error) {
+	n := len(q.lines)
+	if n > max {
+		n = max
+	}
+	var body []byte
+	for _, line := range q.lines[:n] {
+		body = append(body, line...)
+	}
+	return body, n, queuePos{seq: q.head + uint64(n)}, nil
+}
+
+func (q *memQueue) commit(end queuePos) error {
+	if end.seq <= q.head {
+		return nil
+	}
+	n := end.seq - q.head
+	if n > uint64(len(q.lines)) {
+		n = uint64(len(q.lines))
+	}
+	for _, keep := range q.keep[:n] {
+		if keep {
+			q.kept--
+		}
+	}
+	q.lines, q.keep, q.head = q.lines[n:], q.keep[n:], end.seq
+	return nil
+}
+
+func (q *memQueue) cursor() uint64 { return q.head }
+func (q *memQueue) tail() uint64   { return q.head + uint64(len(q.lines)) }
+func (q *memQueue) close() error   { return nil }
+
+// diskQueue is a shipQueue in segment files in a directory, with the cursor recorded in a file,
+// so lines not yet shipped survive restarts. The oldest segment is dropped beyond maxBytes,
+// not counting the lines kept, which are listed in a file beside their segment, or beyond
+// queueKeptLimit times maxBytes in all
+type diskQueue struct {
+	fs       FS
+	dir      string
+	maxBytes int64
+	mode     os.FileMode
+	segments []queueSegment // oldest first; lines are appended to the last
+	at       queuePos       // the cursor, with its offset in the first segment
+	file     File           // the last segment, once opened for appending
+}
+
+type queueSegment struct {
+	start uint64 // sequence number of the first line
+	lines int64
+	size  int64
+	keep  []keptLine
+}
+
+// keptLine is a line of a segment that is never dropped
+type keptLine struct {
+	seq    uint64
+	offset int64
+	size   int64
+}
+
+func (s queueSegment) name(dir string) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", s.start, queueSuffix))
+}
+
+func (s queueSegment) keepName(dir string) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", s.start, queueKeepSuffix))
+}
+
+// readKeep reads the lines kept in a segment, ignoring any beyond its end after a crash
+func (s *queueSegment) readKeep(fs FS, dir string) error {
+	b, err := readAll(fs, s.keepName(dir))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		var k keptLine
+		if n, _ := fmt.Sscan(line, &k.seq, &k.offset, &k.size); n == 3 && k.offset+k.size <= s.size {
+			s.keep = append(s.keep, k)
+		}
+	}
+	return nil
+}
+
+// openDiskQueue opens the queue in dir on fs, creating it if needed
+func openDiskQueue(fs FS, dir string, maxBytes int64, mode os.FileMode) (*diskQueue, error) {
+	if fs == nil {
+		fs = osFS{}
+	}
+	if err := fs.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	q := &diskQueue{fs: fs, dir: dir, maxBytes: maxBytes, mode: mode}
+	names, err := fs.Glob(filepath.Join(dir, "*"+queueSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		start, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(name), queueSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		lines, size, err := countLines(fs, name)
+		if err != nil {
+			return nil, err
+		}
+		segment := queueSegment{start: start, lines: lines, size: size}
+		if err = segment.readKeep(fs, dir); err != nil {
+			return nil, err
+		}
+		q.segments = append(q.segments, segment)
+	}
+	if len(q.segments) == 0 {
+		q.segments = []queueSegment{{}}
+	}
+	q.at = queuePos{seq: q.segments[0].start}
+	var cursor queuePos
+	if b, err := readAll(fs, filepath.Join(dir, queueCursor)); err == nil {
+		fmt.Sscan(string(b), &cursor.seq, &cursor.offset)
+	}
+	if cursor.seq > q.tail() {
+		cursor = queuePos{seq: q.tail(), offset: q.segments[len(q.segments)-1].size}
+	}
+	if err = q.commit(cursor); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// countLines returns the number of complete lines in a file and their size, cutting off a
+// last line left incomplete by a crash
+func countLines(fs FS, name string) (lines, size int64, err error) {
+	b, err := readAll(fs, name)
+	if err != nil {
+		return 0, 0, err
+	}
+	size = int64(bytes.LastIndexByte(b, '\n') + 1)
+	if size < int64(len(b)) {
+		file, err := fs.OpenFile(name, os.O_RDWR, 0)
+		if err != nil {
+			return 0, 0, err
+		}
+		err = file.Truncate(size)
+		if cErr := file.Close(); err == nil {
+			err = cErr
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return int64(bytes.Count(b[:size], []byte{'\n'})), size, nil
+}
+
+func (q *diskQueue) push(line []byte, keep bool) (dropped, droppedKept int, err error) {
+	if err := q.append(line, keep); err != nil {
+		return 0, 0, err
+	}
+	// drop each segment before the last at most once, as the lines kept are moved to the tail
+	for n := len(q.segments) - 1; n > 0; n-- {
+		full := q.total() > queueKeptLimit*q.maxBytes
+		if !full && q.size() <= q.maxBytes {
+			break
+		}
+		first := q.segments[0]
+		lines, err := q.keptLines(first)
+		if err != nil {
+			return dropped, droppedKept, err
+		}
+		dropped += int(first.start+uint64(first.lines)-q.at.seq) - len(lines)
+		if full {
+			droppedKept += len(lines)
+			lines = nil
+		}
+		// move the lines kept to the tail before the segment is removed
+		for _, line := range lines {
+			if err := q.append(line, true); err != nil {
+				return dropped, droppedKept, err
+			}
+		}
+		if err := q.commit(queuePos{seq: q.segments[1].start}); err != nil {
+			return dropped, droppedKept, err
+		}
+	}
+	return dropped, droppedKept, nil
+}
+
+// keptLines returns the lines kept in a segment that are not yet shipped
+func (q *diskQueue) keptLines(s queueSegment) ([][]byte, error) {
+	if len(s.keep) == 0 {
+		return nil, nil
+	}
+	b, err := readAll(q.fs, s.name(q.dir))
+	if err != nil {
+		return nil, err
+	}
+	var lines [][]byte
+	for _, k := range s.keep {
+		if k.seq >= q.at.seq {
+			lines = append(lines, b[k.offset:k.offset+k.size])
+		}
+	}
+	return lines, nil
+}
+
+// append writes a line to the last segment, starting a new one once it is full
+func (q *diskQueue) append(line []byte, keep bool) error {
+	last := &q.segments[len(q.segments)-1]
+	if last.size >= queueSegmentSize {
+		if err := q.closeFile(); err != nil {
+			return err
+		}
+		q.segments = append(q.segments, queueSegment{start: last.start + uint64(last.lines)})
+		last = &q.segments[len(q.segments)-1]
+	}
+	if q.file == nil {
+		file, err := q.fs.OpenFile(last.name(q.dir), os.O_APPEND|os.O_CREATE|os.O_WRONLY, q.mode)
+		if err != nil {
+			return err
+		}
+		q.file = file
+	}
+	n, err := q.file.Write(line)
+	if err != nil {
+		// cut off a partial line, so the segment stays a sequence of whole lines
+		q.file.Truncate(last.size)
+		return err
+	}
+	k := keptLine{seq: last.start + uint64(last.lines), offset: last.size, size: int64(n)}
+	last.lines++
+	last.size += int64(n)
+	if keep {
+		file, err := q.fs.OpenFile(last.keepName(q.dir), os.O_APPEND|os.O_CREATE|os.O_WRONLY, q.mode)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(file, "%d %d %d\n", k.seq, k.offset, k.size)
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		last.keep = append(last.keep, k)
+	}
+	return nil
+}
+
+// size returns the size of the lines not yet shipped, besides those kept
+func (q *diskQueue) size() (total int64) {
+	for _, s := range q.segments {
+		total += s.size
+		for _, k := range s.keep {
+			if k.seq >= q.at.seq {
+				total -= k.size
+			}
+		}
+	}
+	return total - q.at.offset
+}
+
+// total returns the size of the lines not yet shipped, including those kept
+func (q *diskQueue) total() (total int64) {
+	for _, s := range q.segments {
+		total += s.size
+	}
+	return total - q.at.offset
+}
+
+func (q *diskQueue) peek(max int) ([]byte, int, queuePos, error) {
+	var body []byte
+	n, at := 0, q.at
+	for i := 0; i < len(q.segments) && n < max; i++ {
+		s := q.segments[i]
+		if at.seq >= s.start+uint64(s.lines) {
+			continue
+		}
+		if at.seq == s.start {
+			at.offset = 0
+		}
+		file, err := q.fs.OpenFile(s.name(q.dir), os.O_RDONLY, 0)
+		if err != nil {
+			return nil, 0, q.at, err
+		}
+		r := bufio.NewReader(io.NewSectionReader(file, at.offset, s.size-at.offset))
+		for n < max && at.seq < s.start+uint64(s.lines) {
+			line, err := r.ReadBytes('\n')
+			if err != nil {
+				file.Close()
+				return nil, 0, q.at, err
+			}
+			body = append(body, line...)
+			n++
+			at.seq++
+			at.offset += int64(len(line))
+		}
+		file.Close()
+	}
+	return body, n, at, nil
+}
+
+// commit moves the cursor, removing the segments before it and recording it in the cursor file
+func (q *diskQueue) commit(end queuePos) error {
+	if end.seq < q.at.seq || (end.seq == q.at.seq && end.offset == q.at.offset) {
+		return nil
+	}
+	for len(q.segments) > 1 && q.segments[1].start <= end.seq {
+		for _, name := range []string{q.segments[0].name(q.dir), q.segments[0].keepName(q.dir)} {
+			if err := q.fs.Remove(name); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		q.segments = q.segments[1:]
+	}
+	if end.seq == q.segments[0].start {
+		end.offset = 0
+	}
+	q.at = end
+	tmp := filepath.Join(q.dir, queueCursor+".tmp")
+	file, err := q.fs.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, q.mode)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(file, "%d %d\n", end.seq, end.offset)
+	if cErr := file.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return err
+	}
+	return q.fs.Rename(tmp, filepath.Join(q.dir, queueCursor))
+}
+
+// check checks a file can be created in the queue's directory
+func (q *diskQueue) check() error {
+	name := filepath.Join(q.dir, ".check")
+	file, err := q.fs.OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, q.mode)
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return q.fs.Remove(name)
+}
+
+func (q *diskQueue) cursor() uint64 { return q.at.seq }
+
+func (q *diskQueue) tail() uint64 {
+	last := q.segments[len(q.segments)-1]
+	return last.start + uint64(last.lines)
+}
+
+func (q *diskQueue) closeFile() error {
+	if q.file == nil {
+		return nil
+	}
+	err := q.file.Close()
+	q.file = nil
+	return err
+}
+
+func (q *diskQueue) close() error {
+	return q.closeFile()
+}
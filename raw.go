@@ -0,0 +1,29 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+)
+
+var errRawNewline = errors.New("raw record must end with a newline")
+
+// WriteRaw appends a record formatted elsewhere to the file as it is, for bridging entries
+// produced by other systems into the log. The record must end with a newline. It is not
+// passed to the hooks, sinks or reporter, but is hashed, encrypted and indexed like any other
+// record when those are configured
+func (l *Log) WriteRaw(b []byte) error {
+	if l.isClosed() {
+		return ErrClosed
+	}
+	if len(b) == 0 || b[len(b)-1] != '\n' {
+		return errRawNewline
+	}
+	if l.muted || l.path == "" {
+		return nil
+	}
+	_, err := l.writeRecords([][]byte{bytes.TrimSuffix(b, []byte("\n"))})
+	if err != nil {
+		l.handleError(err)
+	}
+	return err
+}
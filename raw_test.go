@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteRaw(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "raw.log")
+	rl, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithHashChain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := "[" + time.Now().UTC().Format(time.RFC3339) + "] [BRIDGE.INFO] imported"
+	if err = rl.WriteRaw([]byte(record)); err != errRawNewline {
+		t.Errorf("expected a record without a newline to be rejected, got %v", err)
+	}
+	if err = rl.WriteRaw([]byte(record + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	rl.Info("after")
+	rl.Close()
+	lines, _ := rl.GetLog(3)
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], record) {
+		t.Errorf("expected the raw record between the others, got %v", lines)
+	}
+	if err = Verify(path); err != nil {
+		t.Errorf("expected the chain to cover the raw record, got %v", err)
+	}
+}
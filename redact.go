@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+type redaction struct {
+	pattern     *regexp.Regexp
+	replacement string
+	replace     func(match string) string // replaces each match instead of replacement, if set
+}
+
+var defaultRedactions = append(
+	keyRedactions([]string{"password", "passwd", "secret", "token", "api_key", "apikey", "access_key"}),
+	redaction{pattern: regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`), replacement: "${1} " + redacted},
+	redaction{pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), replacement: redacted},
+)
+
+func keyRedactions(keys []string) []redaction {
+	if len(keys) == 0 {
+		return nil
+	}
+	quoted := make([]string, len(keys))
+	for i, k := range keys {
+		quoted[i] = regexp.QuoteMeta(k)
+	}
+	pattern := regexp.MustCompile(`(?i)\b((?:` + strings.Join(quoted, "|") + `)"?\s*[=:]\s*)("[^"]*"|[^\s,;&\]})]+)`)
+	return []redaction{{pattern: pattern, replacement: "${1}" + redacted}}
+}
+
+func (l *Log) redact(message string) string {
+	for _, r := range l.redactions {
+		if r.replace != nil {
+			message = r.pattern.ReplaceAllStringFunc(message, r.replace)
+		} else {
+			message = r.pattern.ReplaceAllString(message, r.replacement)
+		}
+	}
+	return message
+}
+
+// redactValue redacts a field or attribute value. Values other than strings are formatted as
+// they would be written and replaced by the redacted text only if something was redacted
+func (l *Log) redactValue(v interface{}) (interface{}, bool) {
+	var text string
+	switch v := v.(type) {
+	case string:
+		text = v
+	case error:
+		text = v.Error()
+	case fmt.Stringer:
+		text = v.String()
+	default:
+		text = fmt.Sprint(v)
+	}
+	if result := l.redact(text); result != text {
+		return result, true
+	}
+	return v, false
+}
+
+// redactFields returns a copy of fields with their values redacted, or fields if nothing was
+func (l *Log) redactFields(fields Fields) Fields {
+	if len(l.redactions) == 0 || len(fields) == 0 {
+		return fields
+	}
+	var result Fields
+	for k, v := range fields {
+		r, changed := l.redactValue(v)
+		if !changed {
+			continue
+		}
+		if result == nil {
+			result = make(Fields, len(fields))
+			for k, v := range fields {
+				result[k] = v
+			}
+		}
+		result[k] = r
+	}
+	if result == nil {
+		return fields
+	}
+	return result
+}
+
+// redactAttrs returns a copy of attrs with string, error and other non-numeric values
+// redacted, or attrs if nothing was
+func (l *Log) redactAttrs(attrs []Attr) []Attr {
+	if len(l.redactions) == 0 || len(attrs) == 0 {
+		return attrs
+	}
+	var result []Attr
+	for i, a := range attrs {
+		var r string
+		switch a.kind {
+		case kindString:
+			if r = l.redact(a.str); r == a.str {
+				continue
+			}
+		case kindAny:
+			v, changed := l.redactValue(a.any)
+			if !changed {
+				continue
+			}
+			r = v.(string)
+		default:
+			continue
+		}
+		if result == nil {
+			result = append([]Attr{}, attrs...)
+		}
+		result[i] = String(a.Key, r)
+	}
+	if result == nil {
+		return attrs
+	}
+	return result
+}
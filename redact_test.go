@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestDefaultRedaction(t *testing.T) {
+	rl := &Log{}
+	WithDefaultRedaction()(rl)
+	cases := map[string]string{
+		"login password=hunter2 ok":                "login password=[REDACTED] ok",
+		`config {"api_key": "abc123"}`:             `config {"api_key": [REDACTED]}`,
+		"Authorization: Bearer eyJhbGciOi.x-y_z==": "Authorization: Bearer [REDACTED]",
+		"card 4111 1111 1111 1111 declined":        "card [REDACTED] declined",
+		"nothing to see here":                      "nothing to see here",
+	}
+	for in, expected := range cases {
+		if result := rl.redact(in); result != expected {
+			t.Errorf("expected '%s' to be redacted to '%s', got '%s'", in, expected, result)
+		}
+	}
+}
+
+func TestCustomRedaction(t *testing.T) {
+	rl := &Log{}
+	WithRedaction(regexp.MustCompile(`sk_live_\w+`))(rl)
+	WithRedactedKeys("session")(rl)
+	result := rl.redact("charging with sk_live_abc123, session: s3cr3t")
+	if strings.Contains(result, "abc123") || strings.Contains(result, "s3cr3t") {
+		t.Errorf("expected secrets to be redacted, got '%s'", result)
+	}
+}
+
+func TestRedactionOfAttributes(t *testing.T) {
+	c := NewCapture(WithDefaultRedaction())
+	c.WithError(errors.New("auth failed password=hunter2")).
+		WithFields(Fields{"creds": map[string]string{"password": "hunter2"}, "n": 1}).
+		With(String("hdr", "Bearer abc.def")).
+		Error("login failed")
+	e := c.Entries()[0]
+	if v, _ := e.Field("error"); v != "auth failed password=[REDACTED]" {
+		t.Errorf("expected the error attribute to be redacted, got %v", v)
+	}
+	if v, _ := e.Field("hdr"); v != "Bearer [REDACTED]" {
+		t.Errorf("expected the string attribute to be redacted, got %v", v)
+	}
+	if v, _ := e.Field("creds"); v != "map[password:[REDACTED]]" {
+		t.Errorf("expected the field to be redacted keeping the closing bracket, got %v", v)
+	}
+	if v, _ := e.Field("n"); v != 1 {
+		t.Errorf("expected other fields to be kept, got %v", v)
+	}
+}
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownSinkType is returned for a ShipperConfig whose type has not been registered
+var ErrUnknownSinkType = errors.New("unknown sink type")
+
+// SinkFactory builds the sink of a ShipperConfig of the type it is registered for. It reads
+// the fields of the config that apply to it and its own settings from Options
+type SinkFactory func(c ShipperConfig) (Sink, error)
+
+var sinkFactories = struct {
+	mu sync.RWMutex
+	m  map[string]SinkFactory
+}{m: map[string]SinkFactory{"http": newHTTPShipperSink}}
+
+// RegisterSink makes a sink type available by name to ShipperConfig, so that modules
+// providing sinks with heavyweight dependencies, such as a Kafka client, can be plugged in
+// from their init functions without the core package depending on them. It panics if the
+// name is already registered or the factory is nil, as registering is a programming error
+func RegisterSink(name string, factory SinkFactory) {
+	sinkFactories.mu.Lock()
+	defer sinkFactories.mu.Unlock()
+	if factory == nil {
+		panic("logging: RegisterSink factory is nil")
+	}
+	if _, ok := sinkFactories.m[name]; ok {
+		panic("logging: RegisterSink called twice for " + name)
+	}
+	sinkFactories.m[name] = factory
+}
+
+// SinkTypes returns the names of the registered sink types, sorted
+func SinkTypes() []string {
+	sinkFactories.mu.RLock()
+	defer sinkFactories.mu.RUnlock()
+	names := make([]string, 0, len(sinkFactories.m))
+	for name := range sinkFactories.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newRegisteredSink builds the sink of a config with the factory of its type
+func newRegisteredSink(c ShipperConfig) (Sink, error) {
+	sinkFactories.mu.RLock()
+	factory, ok := sinkFactories.m[c.Type]
+	sinkFactories.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSinkType, c.Type)
+	}
+	return factory(c)
+}
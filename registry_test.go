@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+var (
+	// registerRing registers the ring sink type once, however many times the tests run
+	registerRing   sync.Once
+	registeredRing *RingBuffer
+)
+
+func TestRegisterSink(t *testing.T) {
+	registerRing.Do(func() {
+		RegisterSink("ring", func(c ShipperConfig) (Sink, error) {
+			size, err := strconv.Atoi(c.Options["size"])
+			if err != nil {
+				return nil, err
+			}
+			registeredRing = NewRingBuffer(size)
+			return registeredRing, nil
+		})
+	})
+	rl, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithShipper(ShipperConfig{Type: "ring", Options: map[string]string{"size": "1"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rl.Close()
+	rl.Info("shipped")
+	if entries := registeredRing.Entries(); len(entries) != 1 || entries[0].Message != "shipped" {
+		t.Errorf("expected the registered sink to receive the entry, got %v", entries)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected registering a type twice to panic")
+			}
+		}()
+		RegisterSink("ring", func(ShipperConfig) (Sink, error) { return nil, nil })
+	}()
+	if _, err = NewShipper(ShipperConfig{Type: "kafka"}); !errors.Is(err, ErrUnknownSinkType) {
+		t.Errorf("expected ErrUnknownSinkType, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// reopenCheckInterval is how often the path is checked for a replaced or deleted file
+const reopenCheckInterval = time.Second
+
+// fileWriter holds the log file open for writing between entries
+type fileWriter struct {
+	file    File
+	info    os.FileInfo
+	size    int64 // of the file after the log's last write to it, to detect external truncation
+	checked time.Time
+	locked  bool       // the file lock is already held for the write in progress; see syncChain
+	stalled bool       // a write is waiting to be retried with the write lock released; see stall
+	resume  *sync.Cond // on the write lock, broadcast when a stalled write resumes
+}
+
+func (w *fileWriter) close() {
+	if w != nil && w.file != nil {
+		w.file.Close()
+		w.file, w.info = nil, nil
+	}
+}
+
+// outputFile returns the open log file, reopening it at the configured path if it has been
+// deleted, renamed or replaced since it was opened. The path is checked at most once every
+// reopenCheckInterval. It is called with the write lock held
+func (l *Log) outputFile() (File, error) {
+	if l.writer == nil {
+		l.writer = &fileWriter{}
+	}
+	if l.writer.file != nil && !l.fileReplaced() {
+		return l.writer.file, nil
+	}
+	l.writer.close()
+	if err := l.openLogForWrite(); err != nil {
+		return nil, err
+	}
+	info, err := l.writer.file.Stat()
+	if err != nil {
+		l.writer.close()
+		return nil, err
+	}
+	l.writer.info, l.writer.size = info, info.Size()
+	l.writer.checked = l.now()
+	return l.writer.file, nil
+}
+
+func (l *Log) fileReplaced() bool {
+	now := l.now()
+	if now.Sub(l.writer.checked) < reopenCheckInterval {
+		return false
+	}
+	l.writer.checked = now
+	info, err := l.getFS().Stat(l.path)
+	if err != nil {
+		return true
+	}
+	return !sameFile(l.writer.info, info)
+}
+
+func sameFile(a, b os.FileInfo) bool {
+	if node, ok := a.Sys().(*memNode); ok {
+		return node == b.Sys()
+	}
+	return os.SameFile(a, b)
+}
@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReopenAfterRemoval(t *testing.T) {
+	rl := NewTestLog(t, WithNow(steppingClock(reopenCheckInterval)))
+	rl.Info("before rotation")
+	if err := os.Rename(rl.Path(), rl.Path()+".1"); err != nil {
+		t.Fatal(err)
+	}
+	rl.Info("after rotation")
+	os.Remove(rl.Path())
+	rl.Info("after deletion")
+	b, err := os.ReadFile(rl.Path())
+	if err != nil {
+		t.Fatalf("expected the log file to be recreated, got %s", err.Error())
+	}
+	if strings.Contains(string(b), "after rotation") || !strings.Contains(string(b), "after deletion") {
+		t.Errorf("expected only the entry after deletion in the new file, got '%s'", string(b))
+	}
+	b, _ = os.ReadFile(rl.Path() + ".1")
+	if !strings.Contains(string(b), "before rotation") || strings.Contains(string(b), "after rotation") {
+		t.Errorf("expected the renamed file to stop receiving entries, got '%s'", string(b))
+	}
+}
+
+// steppingClock returns a clock advancing by step on every call
+func steppingClock(step time.Duration) func() time.Time {
+	now := time.Now()
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"io"
+	"time"
+)
+
+// Replay reads records from r and writes the entries written from from up to but not
+// including to to the sink, keeping their original times, e.g. to backfill a new aggregator
+// with history. A zero from or to leaves that end of the range open. Records that cannot be
+// parsed are skipped. It stops at the first error of the sink, returning the number of
+// entries replayed before it, so the rest can be replayed from the time of the next one
+func Replay(r io.Reader, from, to time.Time, sink Sink) (replayed int, err error) {
+	scanner := newRecordScanner(r)
+	for {
+		record, err := scanner.next()
+		if err == io.EOF {
+			return replayed, nil
+		}
+		if err != nil {
+			return replayed, err
+		}
+		if replayed, err = replayRecord(record, from, to, sink, replayed); err != nil {
+			return replayed, err
+		}
+	}
+}
+
+// Replay writes the entries of the log file between from and to to the sink; see Replay
+func (l *Log) Replay(from, to time.Time, sink Sink) (replayed int, err error) {
+	if to.IsZero() {
+		to = time.Unix(1<<62, 0)
+	}
+	records, err := l.ReadRange(from, to)
+	if err != nil {
+		return 0, err
+	}
+	for _, record := range records {
+		if replayed, err = replayRecord(record, from, to, sink, replayed); err != nil {
+			return replayed, err
+		}
+	}
+	return replayed, nil
+}
+
+// replayRecord writes the record's entry to the sink if it parses and is in range, returning
+// the count of entries replayed
+func replayRecord(record string, from, to time.Time, sink Sink, replayed int) (int, error) {
+	e, err := ParseEntry(record)
+	if err != nil || e.Time.Before(from) || (!to.IsZero() && !e.Time.Before(to)) {
+		return replayed, nil
+	}
+	if err = sink.WriteEntry(e); err != nil {
+		return replayed, err
+	}
+	return replayed + 1, nil
+}
@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplay(t *testing.T) {
+	start := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	rl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(NewMemFS()), WithNow(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 4; i++ {
+		now = start.Add(time.Duration(i) * time.Minute)
+		rl.Infof("request %d", i)
+	}
+	sink := NewRingBuffer(10)
+	replayed, err := rl.Replay(start.Add(2*time.Minute), start.Add(4*time.Minute), sink)
+	if err != nil || replayed != 2 {
+		t.Fatalf("expected 2 entries replayed, got %d and %v", replayed, err)
+	}
+	entries := sink.Entries()
+	if entries[0].Message != "request 2" || !entries[0].Time.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("expected the original time to be kept, got %s at %s", entries[0].Message, entries[0].Time)
+	}
+
+	b, _ := rl.readFile("/logs/app.log")
+	replayed, err = Replay(strings.NewReader(string(b)), start.Add(time.Minute), time.Time{}, &failingSink{err: errors.New("down")})
+	if err == nil || replayed != 0 {
+		t.Errorf("expected the sink's error to stop the replay, got %d and %v", replayed, err)
+	}
+}
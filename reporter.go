@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"io"
+	"sync"
+)
+
+// Reporter receives the entries at or above the report level, along with the record as
+// formatted for the file, which must not be retained after Report returns. By default reports
+// go to the standard library logger
+type Reporter interface {
+	Report(e Entry, msg []byte)
+}
+
+// ReporterFunc adapts a function to a Reporter, for example to format reports differently
+type ReporterFunc func(e Entry, msg []byte)
+
+func (f ReporterFunc) Report(e Entry, msg []byte) {
+	f(e, msg)
+}
+
+// NewWriterReporter returns a reporter writing each record on its own line to w, such as
+// os.Stdout or os.Stderr
+func NewWriterReporter(w io.Writer) Reporter {
+	return &writerReporter{w: w}
+}
+
+type writerReporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (r *writerReporter) Report(e Entry, msg []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.w.Write(append(msg[:len(msg):len(msg)], '\n'))
+}
+
+// NewLogReporter returns a reporter writing reported entries through another log, such as a
+// console-only or errors-only log. The entries keep their time, env, fields and attributes,
+// and are subject to the target's level, transformations and sinks
+func NewLogReporter(target *Log) Reporter {
+	return logReporter{target: target}
+}
+
+type logReporter struct {
+	target *Log
+}
+
+func (r logReporter) Report(e Entry, msg []byte) {
+	if r.target.isClosed() {
+		return
+	}
+	r.target.writeEntry(e)
+}
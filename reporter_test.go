@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriterReporter(t *testing.T) {
+	var out bytes.Buffer
+	rl, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_WARNING, WithReporter(NewWriterReporter(&out)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl.Info("not reported")
+	result, _ := rl.Warning("disk filling")
+	if out.String() != result+"\n" {
+		t.Errorf("expected only the warning to be reported, got '%s'", out.String())
+	}
+}
+
+func TestReporterFunc(t *testing.T) {
+	reported := make([]string, 0)
+	rl, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_ERROR, WithReporter(ReporterFunc(func(e Entry, msg []byte) {
+		reported = append(reported, e.Level+": "+e.Message)
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl.Error("payment failed")
+	if len(reported) != 1 || reported[0] != "ERROR: payment failed" {
+		t.Errorf("expected the reporter to format the entry itself, got %v", reported)
+	}
+}
+
+func TestLogReporter(t *testing.T) {
+	errorsOnly := NewCapture()
+	errorsOnly.SetLevel(LevelError)
+	rl, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_WARNING, WithReportLog(errorsOnly.Log))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl.WithFields(Fields{"order": 7}).Warning("retrying")
+	rl.Error("gave up")
+	entries := errorsOnly.Entries()
+	if len(entries) != 1 || entries[0].Message != "gave up" || entries[0].Env != "TEST" {
+		t.Errorf("expected only the error to pass the secondary log's level, got %v", entries)
+	}
+	errorsOnly.SetLevel(LevelWarning)
+	rl.WithFields(Fields{"order": 7}).Warning("retrying again")
+	if v, _ := errorsOnly.Entries()[1].Field("order"); v != 7 {
+		t.Errorf("expected reported entries to keep their fields, got %v", errorsOnly.Entries())
+	}
+}
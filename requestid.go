@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+type requestIDKey struct{}
+
+// NewRequestID returns a random 128-bit correlation ID in hex
+func NewRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithContext returns a child log attaching the request ID carried by ctx to every entry
+func (l *Log) WithContext(ctx context.Context) *Log {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return l.With(String("request_id", id))
+	}
+	return l
+}
+
+func (l *Log) WriteContext(ctx context.Context, message, level string) (string, error) {
+	return l.WithContext(ctx).Write(message, level)
+}
+
+func (l *Log) ErrorContext(ctx context.Context, message string) (string, error) {
+	return l.WriteContext(ctx, message, ERROR)
+}
+
+func (l *Log) SuccessContext(ctx context.Context, message string) (string, error) {
+	return l.WriteContext(ctx, message, SUCCESS)
+}
+
+func (l *Log) WarningContext(ctx context.Context, message string) (string, error) {
+	return l.WriteContext(ctx, message, WARNING)
+}
+
+func (l *Log) DebugContext(ctx context.Context, message string) (string, error) {
+	return l.WriteContext(ctx, message, DEBUG)
+}
+
+func (l *Log) InfoContext(ctx context.Context, message string) (string, error) {
+	return l.WriteContext(ctx, message, INFO)
+}
+
+// maxRequestIDLength is the longest inbound request ID RequestID accepts
+const maxRequestIDLength = 128
+
+// validRequestID reports whether an inbound request ID is safe to log and echo: at most
+// maxRequestIDLength letters, digits and the punctuation of common ID formats
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.IndexByte("-_.:/+=@", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// RequestID returns middleware that takes the request ID from the X-Request-ID header,
+// generating one if it is missing or not a valid ID (too long, or holding characters other
+// than letters, digits and -_.:/+=@), stashes it in the request context and echoes it on
+// the response. The request passed on carries the ID in its header; the caller's request is
+// not modified
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = NewRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		r = r.WithContext(ContextWithRequestID(r.Context(), id))
+		r.Header = r.Header.Clone()
+		r.Header.Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	c := NewCapture()
+	var handlerID string
+	handler := RequestID(c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerID, _ = RequestIDFromContext(r.Context())
+		c.InfoContext(r.Context(), "handling")
+	})))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if len(handlerID) != 32 || rec.Header().Get(RequestIDHeader) != handlerID {
+		t.Fatalf("expected a generated ID in the context and response, got '%s' and '%s'", handlerID, rec.Header().Get(RequestIDHeader))
+	}
+	for _, e := range c.Entries() {
+		if v, _ := e.Field("request_id"); v != handlerID {
+			t.Errorf("expected every entry to carry the request ID, got %s", e)
+		}
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(RequestIDHeader, "upstream-7")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if handlerID != "upstream-7" {
+		t.Errorf("expected the incoming ID to be propagated, got '%s'", handlerID)
+	}
+	for _, id := range []string{"forged\r\nSet-Cookie: x=1", "has space", strings.Repeat("a", maxRequestIDLength+1)} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestIDHeader, id)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if handlerID == id || len(handlerID) != 32 || rec.Header().Get(RequestIDHeader) != handlerID {
+			t.Errorf("expected an invalid ID to be replaced, got '%s'", handlerID)
+		}
+		if req.Header.Get(RequestIDHeader) != id {
+			t.Errorf("expected the caller's request to be left unmodified, got '%s'", req.Header.Get(RequestIDHeader))
+		}
+	}
+}
+
+func TestWithContextWithoutID(t *testing.T) {
+	c := NewCapture()
+	if c.WithContext(context.Background()) != c.Log {
+		t.Error("expected a context without an ID to return the log itself")
+	}
+}
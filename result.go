@@ -0,0 +1,32 @@
+package logging
+
+import "time"
+
+// WriteResult describes what became of an entry written with WriteDetailed
+type WriteResult struct {
+	// Entry is the entry after hooks, redaction and masking; it is empty if the entry was
+	// sampled or suppressed as a duplicate before being created
+	Entry Entry
+	// Record is the record as written to the file, or queued when the log is asynchronous
+	Record string
+	// Filtered is set if the entry was not written: sampled, suppressed as a duplicate,
+	// dropped by a hook or below the log level
+	Filtered bool
+	// Queued is set if the entry was queued for an asynchronous write; see WithAsync
+	Queued bool
+	// Sinks are the sinks that accepted the entry, in the order they were configured
+	Sinks []Sink
+	// Fallback is set if the file write failed and the entry went to the fallback sink
+	Fallback bool
+	// Err is the error Write would have returned
+	Err error
+}
+
+// WriteDetailed writes an entry as Write does, returning a description of what happened to
+// it instead of only the record, for wrappers and tests making precise assertions
+func (l *Log) WriteDetailed(message, level string) WriteResult {
+	var res WriteResult
+	res.Record, res.Err = l.write(time.Time{}, message, level, &res)
+	res.Filtered = res.Record == "" && res.Err == nil
+	return res
+}
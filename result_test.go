@@ -0,0 +1,23 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWriteDetailed(t *testing.T) {
+	failing := &failingSink{}
+	c := NewCapture(WithSink(failing), WithMaskedFields("card"))
+	failing.err = errors.New("unavailable")
+	c.SetLevel(LevelWarning)
+	res := c.WithFields(Fields{"card": "4111"}).WriteDetailed("declined", WARNING)
+	if res.Filtered || res.Entry.Fields["card"] != "****" || res.Record == "" {
+		t.Errorf("expected the masked entry to be written, got %+v", res)
+	}
+	if len(res.Sinks) != 1 || res.Sinks[0] != Sink(c.sink) || res.Err == nil {
+		t.Errorf("expected only the capture sink to accept the entry and the failure to be returned, got %v and %v", res.Sinks, res.Err)
+	}
+	if res = c.WriteDetailed("polling", INFO); !res.Filtered || res.Entry.Message != "polling" || len(res.Sinks) != 0 {
+		t.Errorf("expected the entry to be filtered by level, got %+v", res)
+	}
+}
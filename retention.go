@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// compressedExtensions are the extensions of files compressed by the package's compressors
+var compressedExtensions = []string{GzipCompressor{}.Extension(), ZstdCompressor{}.Extension()}
+
+// Retention says which rotated files to keep and whether to compress them; see ApplyRetention
+type Retention struct {
+	MaxBackups int           // newest rotated files kept, or 0 for no limit
+	MaxAge     time.Duration // rotated files last modified longer ago are removed, or 0 for no limit
+	Compress   Compressor    // compresses the files kept that are not compressed yet, if set
+}
+
+// RetentionResult lists the rotated files removed and the files compressed by ApplyRetention
+type RetentionResult struct {
+	Removed    []string
+	Compressed []string
+}
+
+// ParseRetention parses a retention spec of comma separated settings: keep=N for MaxBackups,
+// age=D for MaxAge, where D is a duration or a number of days such as 30d, and compress=gzip
+// or compress=zstd to compress with gzip or zstd, e.g. "keep=10,age=30d,compress=gzip"
+func ParseRetention(spec string) (Retention, error) {
+	var r Retention
+	for _, setting := range strings.Split(spec, ",") {
+		setting = strings.TrimSpace(setting)
+		if setting == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(setting, "=")
+		var err error
+		switch key {
+		case "keep":
+			r.MaxBackups, err = strconv.Atoi(value)
+			if err == nil && r.MaxBackups < 0 {
+				err = fmt.Errorf("negative count")
+			}
+		case "age":
+			r.MaxAge, err = parseAge(value)
+		case "compress":
+			var ok bool
+			if r.Compress, ok = compressors[value]; !ok {
+				err = fmt.Errorf("unsupported compression")
+			}
+		default:
+			err = fmt.Errorf("unknown setting")
+		}
+		if err != nil {
+			return Retention{}, fmt.Errorf("invalid retention setting '%s': %w", setting, err)
+		}
+	}
+	return r, nil
+}
+
+// parseAge parses a duration, allowing a whole number of days such as 30d
+func parseAge(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		n, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		return time.Duration(n) * 24 * time.Hour, err
+	}
+	return time.ParseDuration(value)
+}
+
+// ApplyRetention removes the rotated files of the log file at path that r does not keep, then
+// compresses the rest if r says to. Rotated files are those named <path>.<suffix>, as the log
+// names them unless WithRotationName is used, compressed or not
+func ApplyRetention(path string, r Retention) (RetentionResult, error) {
+	fs := osFS{}
+	matches, err := fs.Glob(filepath.Join(filepath.Dir(path), filepath.Base(path)+".*"))
+	if err != nil {
+		return RetentionResult{}, err
+	}
+	backups := matches[:0]
+	for _, name := range matches {
+		if !strings.HasSuffix(name, quarantineSuffix) && !strings.HasSuffix(name, queueSuffix) {
+			backups = append(backups, name)
+		}
+	}
+	return applyRetention(fs, backups, r, time.Now(), 0)
+}
+
+// ApplyRetention removes the rotated files r does not keep, then compresses the rest if r says
+// to; see ApplyRetention
+func (l *Log) ApplyRetention(r Retention) (RetentionResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	backups, err := l.backups()
+	if err != nil {
+		return RetentionResult{}, err
+	}
+	return applyRetention(l.getFS(), backups, r, l.now(), l.fileMode)
+}
+
+func applyRetention(fs FS, backups []string, r Retention, now time.Time, mode os.FileMode) (RetentionResult, error) {
+	var result RetentionResult
+	modTimes := make(map[string]time.Time, len(backups))
+	for _, name := range backups {
+		if info, err := fs.Stat(name); err == nil {
+			modTimes[name] = info.ModTime()
+		}
+	}
+	// newest first
+	sort.Slice(backups, func(i, j int) bool {
+		return modTimes[backups[i]].After(modTimes[backups[j]])
+	})
+	kept := make([]string, 0, len(backups))
+	for i, name := range backups {
+		expired := r.MaxAge > 0 && now.Sub(modTimes[name]) > r.MaxAge
+		if (r.MaxBackups > 0 && i >= r.MaxBackups) || expired {
+			if err := fs.Remove(name); err != nil {
+				return result, err
+			}
+			result.Removed = append(result.Removed, name)
+			continue
+		}
+		kept = append(kept, name)
+	}
+	if r.Compress == nil {
+		return result, nil
+	}
+	for _, name := range kept {
+		if isCompressed(name) {
+			continue
+		}
+		compressed, err := compressFile(fs, name, r.Compress, mode)
+		if err != nil {
+			return result, err
+		}
+		result.Compressed = append(result.Compressed, compressed)
+	}
+	return result, nil
+}
+
+func isCompressed(name string) bool {
+	for _, ext := range compressedExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseRetention(t *testing.T) {
+	r, err := ParseRetention("keep=5, age=30d,compress=gzip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.MaxBackups != 5 || r.MaxAge != 30*24*time.Hour || r.Compress == nil {
+		t.Errorf("unexpected retention %+v", r)
+	}
+	for _, spec := range []string{"keep=-1", "age=soon", "compress=lz4", "size=10"} {
+		if _, err = ParseRetention(spec); err == nil {
+			t.Errorf("expected '%s' to be rejected", spec)
+		}
+	}
+}
+
+func TestApplyRetention(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	now := time.Now()
+	files := map[string]time.Duration{
+		"app.log":                    0,
+		"app.log.20240105T000000":    time.Hour,
+		"app.log.20240104T000000.gz": 2 * time.Hour,
+		"app.log.20240103T000000":    3 * time.Hour,
+		"app.log.20240101T000000":    100 * time.Hour,
+		"app.log.partial":            time.Hour,
+	}
+	for name, age := range files {
+		os.WriteFile(filepath.Join(dir, name), []byte("[2024-01-01T00:00:00Z] [TEST.INFO] entry\n"), 0644)
+		os.Chtimes(filepath.Join(dir, name), now.Add(-age), now.Add(-age))
+	}
+	result, err := ApplyRetention(path, Retention{MaxBackups: 2, MaxAge: 48 * time.Hour, Compress: GzipCompressor{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Removed) != 2 || len(result.Compressed) != 1 || result.Compressed[0] != path+".20240105T000000.gz" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	remaining, _ := filepath.Glob(filepath.Join(dir, "*"))
+	expected := []string{path, path + ".20240104T000000.gz", path + ".20240105T000000.gz", path + ".partial"}
+	if len(remaining) != len(expected) {
+		t.Fatalf("expected %v to remain, got %v", expected, remaining)
+	}
+	for i := range expected {
+		if remaining[i] != expected[i] {
+			t.Errorf("expected %v to remain, got %v", expected, remaining)
+		}
+	}
+}
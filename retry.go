@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy configures retries of failed writes. The delay before each retry starts at
+// Backoff and doubles on every attempt, up to MaxDelay if it is set
+type RetryPolicy struct {
+	Attempts int
+	Backoff  time.Duration
+	MaxDelay time.Duration
+}
+
+// do calls fn until it succeeds or the attempts are exhausted, returning the last error
+func (p *RetryPolicy) do(fn func() error) error {
+	return p.retry(fn, func(delay time.Duration) error {
+		time.Sleep(delay)
+		return nil
+	})
+}
+
+// doContext is do, giving up with ctx's error if ctx is done while waiting to retry
+func (p *RetryPolicy) doContext(ctx context.Context, fn func() error) error {
+	return p.retry(fn, func(delay time.Duration) error {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	})
+}
+
+// retry is do, waiting between attempts with wait, which gives up by returning an error
+func (p *RetryPolicy) retry(fn func() error, wait func(time.Duration) error) error {
+	err := fn()
+	if p == nil {
+		return err
+	}
+	delay := p.Backoff
+	for attempt := 1; err != nil && attempt < p.Attempts; attempt++ {
+		if p.MaxDelay > 0 && delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+		if wErr := wait(delay); wErr != nil {
+			return wErr
+		}
+		delay *= 2
+		err = fn()
+	}
+	return err
+}
+
+// stall waits for delay with the write lock released, so that reads, health checks and Close
+// are not held up while a write to the file is retried. Other writes and rotations wait for
+// the stalled write to resume, so records stay in order and the audit chain unbroken. It
+// returns ErrClosed if the log was closed meanwhile. It is called with the write lock held
+func (l *Log) stall(delay time.Duration) error {
+	w := l.writer
+	if w.resume == nil {
+		time.Sleep(delay)
+		return nil
+	}
+	w.stalled = true
+	l.mu.Unlock()
+	time.Sleep(delay)
+	l.mu.Lock()
+	w.stalled = false
+	w.resume.Broadcast()
+	if l.isClosed() {
+		return ErrClosed
+	}
+	return nil
+}
+
+// awaitStall waits for a stalled write to resume; see stall. It is called with the write
+// lock held
+func (l *Log) awaitStall() {
+	for w := l.writer; w != nil && w.stalled; {
+		w.resume.Wait()
+	}
+}
+
+type retrySink struct {
+	sink   Sink
+	policy RetryPolicy
+}
+
+// RetrySink wraps a sink so that failed writes are retried according to the policy
+func RetrySink(sink Sink, policy RetryPolicy) Sink {
+	return &retrySink{sink: sink, policy: policy}
+}
+
+func (s *retrySink) WriteEntry(e Entry) error {
+	return s.policy.do(func() error {
+		return s.sink.WriteEntry(e)
+	})
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type flakySink struct {
+	failures, calls int
+}
+
+func (s *flakySink) WriteEntry(e Entry) error {
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("transient failure")
+	}
+	return nil
+}
+
+func TestRetrySink(t *testing.T) {
+	flaky := &flakySink{failures: 2}
+	policy := RetryPolicy{Attempts: 3, Backoff: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	if err := RetrySink(flaky, policy).WriteEntry(Entry{}); err != nil {
+		t.Errorf("expected the write to succeed on the third attempt, got %s", err.Error())
+	}
+	flaky = &flakySink{failures: 5}
+	if err := RetrySink(flaky, policy).WriteEntry(Entry{}); err == nil {
+		t.Error("expected an error once attempts are exhausted")
+	}
+	if flaky.calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", flaky.calls)
+	}
+}
+
+func TestRetryReleasesLock(t *testing.T) {
+	fs := &fullFS{MemFS: NewMemFS(), limit: 1}
+	rl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithRetry(RetryPolicy{Attempts: 2, Backoff: 300 * time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs.limit = 0
+	done := make(chan error)
+	go func() {
+		_, err := rl.Info("retried")
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	// reading the log does not wait for the write being retried
+	start := time.Now()
+	if lines, err := rl.GetLog(1); err != nil || len(lines) != 1 {
+		t.Errorf("expected the log to be read during the retry, got %v and %v", lines, err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("expected the read not to wait for the retry, took %s", elapsed)
+	}
+	if err = <-done; err == nil {
+		t.Error("expected the write to fail once the attempts are exhausted")
+	}
+}
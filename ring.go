@@ -0,0 +1,74 @@
+package logging
+
+import "sync"
+
+// RingBuffer is a sink that keeps the last N entries in memory
+type RingBuffer struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+// NewRingBuffer returns a ring buffer holding up to size entries
+func NewRingBuffer(size int) *RingBuffer {
+	if size < 1 {
+		size = 1
+	}
+	return &RingBuffer{entries: make([]Entry, size)}
+}
+
+func (r *RingBuffer) WriteEntry(e Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = e
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+	return nil
+}
+
+// Entries returns the buffered entries, oldest first
+func (r *RingBuffer) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]Entry{}, r.entries[:r.next]...)
+	}
+	result := make([]Entry, 0, len(r.entries))
+	result = append(result, r.entries[r.next:]...)
+	return append(result, r.entries[:r.next]...)
+}
+
+// recordError keeps a WARNING or more severe entry in the recent errors buffer
+func (l *Log) recordError(e Entry) {
+	if l.recentErrors == nil {
+		return
+	}
+	if severity, ok := Severity(e.Level); ok && severity <= syslogSeverities[WARNING] {
+		l.recentErrors.WriteEntry(e)
+	}
+}
+
+// LastErrors returns up to the last n WARNING or more severe entries, oldest first, kept by
+// WithRecentErrors whether or not they were written to the file or sinks
+func (l *Log) LastErrors(n int) []Entry {
+	if l.recentErrors == nil || n <= 0 {
+		return nil
+	}
+	entries := l.recentErrors.Entries()
+	if len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries
+}
+
+// RecentEntries returns the entries held by the log's ring buffer (see WithRingBuffer), oldest first
+func (l *Log) RecentEntries() []Entry {
+	if l.ring == nil {
+		return nil
+	}
+	return l.ring.Entries()
+}
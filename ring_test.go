@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	r := NewRingBuffer(3)
+	for i := 0; i < 5; i++ {
+		r.WriteEntry(Entry{Level: INFO, Message: fmt.Sprintf("entry %d", i)})
+	}
+	entries := r.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected ring buffer to hold 3 entries, got %d", len(entries))
+	}
+	for i, e := range entries {
+		expected := fmt.Sprintf("entry %d", i+2)
+		if e.Message != expected {
+			t.Errorf("expected entry %d to be '%s', got '%s'", i, expected, e.Message)
+		}
+	}
+}
+
+func TestRecentEntries(t *testing.T) {
+	rl, err := NewLog(filepath.Join(t.TempDir(), "ring.log"), "TEST", LEVEL_INFO, LEVEL_NONE, WithRingBuffer(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl.Warning("ring warning")
+	entries := rl.RecentEntries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 recent entries, got %d", len(entries))
+	}
+	last := entries[len(entries)-1]
+	if last.Level != WARNING || last.Message != "ring warning" {
+		t.Errorf("expected last recent entry to be the warning, got '%s'", last.String())
+	}
+}
+
+func TestLastErrors(t *testing.T) {
+	c := NewCapture(WithRecentErrors(3))
+	c.SetLevel(LevelNone)
+	c.Error("one")
+	c.Info("ignored")
+	c.Warning("two")
+	c.Critical("three")
+	c.Error("four")
+	errors := c.LastErrors(10)
+	if len(errors) != 3 || errors[0].Message != "two" || errors[2].Message != "four" {
+		t.Errorf("expected the last 3 errors and warnings, got %v", errors)
+	}
+	if last := c.LastErrors(1); len(last) != 1 || last[0].Message != "four" {
+		t.Errorf("expected the last error, got %v", last)
+	}
+	if len(c.Entries()) != 0 {
+		t.Errorf("expected nothing to be written below the log level, got %v", c.Entries())
+	}
+}
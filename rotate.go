@@ -0,0 +1,263 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// rotatedLayout is the timestamp appended to the path of a rotated file
+	rotatedLayout = "20060102T150405"
+	// dailyDirLayout names the per-day directories rotated files are moved into
+	dailyDirLayout = "2006-01-02"
+	// defaultDirMode is used for per-day directories when no directory mode is set
+	defaultDirMode os.FileMode = 0755
+)
+
+// rotationNaming controls where rotated files are placed; see WithRotationName
+type rotationNaming struct {
+	pattern   string
+	dailyDirs bool
+}
+
+// Rotate renames the log file, so that the next write starts a new file at the path, and
+// returns the name of the rotated file, or "" if the file is missing or empty. Rotated files
+// are named <path>.<timestamp> unless set by WithRotationName. The rotated file is then
+// compressed and archived if configured; see WithCompression and WithArchive
+func (l *Log) Rotate() (string, error) {
+	if l.isClosed() {
+		return "", ErrClosed
+	}
+	if l.path == "" {
+		return "", nil
+	}
+	l.mu.Lock()
+	l.awaitStall()
+	rotated, err := l.rotate()
+	l.mu.Unlock()
+	if err != nil || rotated == "" {
+		return "", err
+	}
+	if rotated, err = l.finishRotation(rotated); err != nil {
+		return rotated, err
+	}
+	if l.budget != nil {
+		l.mu.Lock()
+		err = l.enforceDiskBudget()
+		l.mu.Unlock()
+		if err != nil {
+			l.handleError(err)
+		}
+	}
+	return rotated, err
+}
+
+// finishRotation compresses and archives a rotated file if configured, returning its name
+// once compressed
+func (l *Log) finishRotation(rotated string) (string, error) {
+	if l.compressor != nil {
+		compressed, err := l.compressFile(rotated)
+		if err != nil {
+			l.handleError(err)
+			return "", err
+		}
+		rotated = compressed
+	}
+	if l.archive != nil {
+		if err := l.archiveFile(rotated); err != nil {
+			l.handleError(err)
+			return rotated, err
+		}
+	}
+	return rotated, nil
+}
+
+// rotate closes and renames the log file. It is called with the write lock held
+func (l *Log) rotate() (string, error) {
+	l.writer.close()
+	if info, err := l.getFS().Stat(l.path); os.IsNotExist(err) || (err == nil && info.Size() == 0) {
+		return "", nil
+	}
+	t := l.now().UTC()
+	if l.naming != nil && l.naming.dailyDirs {
+		mode := l.dirMode
+		if mode == 0 {
+			mode = defaultDirMode
+		}
+		if err := l.getFS().MkdirAll(l.rotationDir(t), mode); err != nil {
+			return "", err
+		}
+	}
+	for seq := 1; ; seq++ {
+		name := l.rotatedName(t, seq)
+		_, err := l.getFS().Stat(name)
+		if os.IsNotExist(err) {
+			err = l.getFS().Rename(l.path, name)
+			if err != nil && isSharingViolation(err) {
+				// another process has the file open without allowing it to be renamed
+				err = l.copyTruncate(name)
+			}
+			if err != nil {
+				return "", err
+			}
+			l.resetIndex()
+			return name, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// copyTruncate rotates by copying the log file to name and truncating it, for when the file
+// cannot be renamed. Entries written by other processes during the copy may be lost
+func (l *Log) copyTruncate(name string) error {
+	b, err := l.readFile(l.path)
+	if err != nil {
+		return err
+	}
+	mode := l.fileMode
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+	dst, err := l.getFS().OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err = dst.Write(b); err == nil {
+		err = dst.Sync()
+	}
+	if cErr := dst.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return err
+	}
+	src, err := l.getFS().OpenFile(l.path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	return src.Truncate(0)
+}
+
+// rotationDir returns the directory files rotated at t are placed in
+func (l *Log) rotationDir(t time.Time) string {
+	dir := filepath.Dir(l.path)
+	if l.naming != nil && l.naming.dailyDirs {
+		dir = filepath.Join(dir, t.Format(dailyDirLayout))
+	}
+	return dir
+}
+
+// rotatedName returns the name of the seq'th candidate for a file rotated at t. Where the
+// name has no sequence number, candidates after the first are suffixed -1, -2 and so on
+func (l *Log) rotatedName(t time.Time, seq int) string {
+	var name string
+	if l.naming == nil || l.naming.pattern == "" {
+		name = filepath.Base(l.path) + "." + t.Format(rotatedLayout)
+		if seq > 1 {
+			name = fmt.Sprintf("%s-%d", name, seq-1)
+		}
+		return filepath.Join(l.rotationDir(t), name)
+	}
+	name, numbered := expandRotationPattern(l.naming.pattern, t, seq)
+	if !numbered && seq > 1 {
+		ext := filepath.Ext(name)
+		name = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), seq-1, ext)
+	}
+	return filepath.Join(l.rotationDir(t), name)
+}
+
+// errRotationPrefix is returned when the rotated files of a log cannot be told apart from other
+// files, so that they are not removed by mistake
+var errRotationPrefix = errors.New("rotation name must start with text rather than a verb to find rotated files by")
+
+// rotatedGlob returns a pattern matching the log's rotated files, compressed or not. It is
+// built from the literal text of the rotation name, with * for each verb, and fails if the
+// name starts with a verb, which would match unrelated files such as *.log
+func (l *Log) rotatedGlob() (string, error) {
+	dir := filepath.Dir(l.path)
+	if l.naming != nil && l.naming.dailyDirs {
+		dir = filepath.Join(dir, "*")
+	}
+	if l.naming == nil || l.naming.pattern == "" {
+		return filepath.Join(dir, filepath.Base(l.path)+".*"), nil
+	}
+	var b strings.Builder
+	walkRotationPattern(l.naming.pattern, func(literal string, verb byte, width int) {
+		if verb == 0 {
+			b.WriteString(literal)
+		} else if !strings.HasSuffix(b.String(), "*") {
+			b.WriteByte('*')
+		}
+	})
+	if strings.HasPrefix(b.String(), "*") {
+		return "", errRotationPrefix
+	}
+	return filepath.Join(dir, b.String()+"*"), nil
+}
+
+// expandRotationPattern expands the verbs in a rotation pattern, reporting whether it
+// contains a sequence number
+func expandRotationPattern(pattern string, t time.Time, seq int) (name string, numbered bool) {
+	var b strings.Builder
+	walkRotationPattern(pattern, func(literal string, verb byte, width int) {
+		switch verb {
+		case 0:
+			b.WriteString(literal)
+		case 'Y':
+			b.WriteString(t.Format("2006"))
+		case 'm':
+			b.WriteString(t.Format("01"))
+		case 'd':
+			b.WriteString(t.Format("02"))
+		case 'H':
+			b.WriteString(t.Format("15"))
+		case 'M':
+			b.WriteString(t.Format("04"))
+		case 'S':
+			b.WriteString(t.Format("05"))
+		case 'n':
+			fmt.Fprintf(&b, "%0*d", width, seq)
+			numbered = true
+		}
+	})
+	return b.String(), numbered
+}
+
+// walkRotationPattern splits a rotation pattern into literals and verbs. %Y, %m, %d, %H,
+// %M and %S are the parts of the rotation time, %% is a percent sign and a width followed
+// by d, as in %03d, is a sequence number, which is passed as the verb n
+func walkRotationPattern(pattern string, fn func(literal string, verb byte, width int)) {
+	for {
+		i := strings.IndexByte(pattern, '%')
+		if i < 0 || i == len(pattern)-1 {
+			fn(pattern, 0, 0)
+			return
+		}
+		fn(pattern[:i], 0, 0)
+		pattern = pattern[i+1:]
+		digits := len(pattern) - len(strings.TrimLeft(pattern, "0123456789"))
+		if digits > 0 && digits < len(pattern) && pattern[digits] == 'd' {
+			width, _ := strconv.Atoi(pattern[:digits])
+			fn("", 'n', width)
+			pattern = pattern[digits+1:]
+			continue
+		}
+		switch pattern[0] {
+		case 'Y', 'm', 'd', 'H', 'M', 'S':
+			fn("", pattern[0], 0)
+		case '%':
+			fn("%", 0, 0)
+		default:
+			fn("%"+pattern[:1], 0, 0)
+		}
+		pattern = pattern[1:]
+	}
+}
@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotate(t *testing.T) {
+	fs := NewMemFS()
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	rl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithNow(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl.Info("before rotation")
+	rotated, err := rl.Rotate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated != "/logs/app.log.20240102T150405" {
+		t.Errorf("unexpected rotated file name %s", rotated)
+	}
+	rl.Info("after rotation")
+	again, err := rl.Rotate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != rotated+"-1" {
+		t.Errorf("expected a second rotation in the same second to be named %s-1, got %s", rotated, again)
+	}
+	rl.Info("current")
+	b, _ := rl.readFile(rotated)
+	if !strings.Contains(string(b), "before rotation") || strings.Contains(string(b), "after rotation") {
+		t.Errorf("unexpected rotated file contents %q", string(b))
+	}
+	b, _ = rl.readFile("/logs/app.log")
+	if strings.Contains(string(b), "rotation") || !strings.Contains(string(b), "current") {
+		t.Errorf("unexpected log file contents %q", string(b))
+	}
+	fs.Remove("/logs/app.log")
+	if rotated, err = rl.Rotate(); rotated != "" || err != nil {
+		t.Errorf("expected nothing to rotate without a file, got %q and %v", rotated, err)
+	}
+}
+
+func TestRotationName(t *testing.T) {
+	fs := NewMemFS()
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	rl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs),
+		WithNow(func() time.Time { return now }), WithRotationName("app-%Y%m%d-%03d.log", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"/logs/2024-01-02/app-20240102-001.log", "/logs/2024-01-02/app-20240102-002.log"} {
+		rl.Info("rotated")
+		rotated, err := rl.Rotate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rotated != expected {
+			t.Errorf("expected %s, got %s", expected, rotated)
+		}
+	}
+	glob, err := rl.rotatedGlob()
+	if err != nil || glob != "/logs/*/app-*-*.log*" {
+		t.Errorf("unexpected glob %s and %v", glob, err)
+	}
+	matches, _ := fs.Glob(glob)
+	if len(matches) != 2 {
+		t.Errorf("expected the glob to match both rotated files, got %v", matches)
+	}
+}
+
+func TestExpandRotationPattern(t *testing.T) {
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	for pattern, expected := range map[string]string{
+		"app-%Y%m%d-%03d.log":  "app-20240102-007.log",
+		"app.%H%M%S.log":       "app.150405.log",
+		"100%%-%1d.log":        "100%-7.log",
+		"app-%q.log":           "app-%q.log",
+		"app-%Y-%m-%d-%2d.log": "app-2024-01-02-07.log",
+	} {
+		if name, _ := expandRotationPattern(pattern, at, 7); name != expected {
+			t.Errorf("expected %s to expand to %s, got %s", pattern, expected, name)
+		}
+	}
+	if name, numbered := expandRotationPattern("app-%Y%m%d.log", at, 1); numbered || name != "app-20240102.log" {
+		t.Errorf("unexpected expansion %s", name)
+	}
+}
+
+func TestRotateOnStartup(t *testing.T) {
+	fs := NewMemFS()
+	for _, run := range []string{"first run", "second run"} {
+		rl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithRotateOnStartup())
+		if err != nil {
+			t.Fatal(err)
+		}
+		rl.Info(run)
+		rl.Close()
+	}
+	b, _ := (&Log{fs: fs}).readFile("/logs/app.log")
+	if strings.Contains(string(b), "first run") || !strings.Contains(string(b), "second run") {
+		t.Errorf("expected the second run to start a new file, got %q", string(b))
+	}
+	if rotated, _ := fs.Glob("/logs/app.log.*"); len(rotated) != 1 {
+		t.Errorf("expected the first run's file to be rotated, got %v", rotated)
+	}
+}
+
+func TestRotateHandler(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	rl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(NewMemFS()), WithNow(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := rl.RotateHandler()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/rotate", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected GET to be refused, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/rotate", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "/logs/app.log.20240102T150405\n" {
+		t.Errorf("expected the rotated file name, got %d and %q", rec.Code, rec.Body.String())
+	}
+}
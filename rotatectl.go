@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// RotateOnSignal rotates the log whenever the process receives one of the signals; with none,
+// SIGHUP where the platform has it, which ReloadLevelsOnSignal also handles. Errors are passed to the error handler. The returned
+// function stops handling the signals
+func (l *Log) RotateOnSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = rotateSignals
+	}
+	return onSignal(sigs, func(os.Signal) {
+		if _, err := l.Rotate(); err != nil {
+			l.handleError(err)
+		}
+	})
+}
+
+// RotateHandler returns an admin handler that rotates the log on a POST request and responds
+// with the name of the rotated file, empty if there was nothing to rotate. Protect it with
+// TokenAuth or BasicAuth
+func (l *Log) RotateHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		rotated, err := l.Rotate()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, rotated)
+	})
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"fmt"
+	"time"
+)
+
+// RPC describes a completed remote procedure call, as passed to LogRPC. The interceptors of
+// package grpclogging fill it in from gRPC calls; other RPC frameworks can do the same
+type RPC struct {
+	Method  string
+	Code    string // the status code name, such as "OK" or "NotFound"
+	Latency time.Duration
+	Peer    string
+	// Request and Response are logged only when DEBUG entries are enabled
+	Request, Response interface{}
+	Err               error
+}
+
+// rpcClientCodes are the status codes caused by the caller, logged at WARNING
+var rpcClientCodes = map[string]bool{
+	"Canceled":           true,
+	"InvalidArgument":    true,
+	"NotFound":           true,
+	"AlreadyExists":      true,
+	"PermissionDenied":   true,
+	"Unauthenticated":    true,
+	"FailedPrecondition": true,
+	"OutOfRange":         true,
+}
+
+// LogRPC logs a completed call with its method, status code, latency and peer. Failed calls
+// are logged at ERROR, or WARNING for codes caused by the caller; successful calls at INFO
+func (l *Log) LogRPC(rpc RPC) {
+	code := rpc.Code
+	if code == "" {
+		code = "OK"
+	}
+	attrs := []Attr{
+		String("method", rpc.Method),
+		String("code", code),
+		Duration("latency", rpc.Latency),
+	}
+	if rpc.Peer != "" {
+		attrs = append(attrs, String("peer", rpc.Peer))
+	}
+	if rpc.Err != nil {
+		attrs = append(attrs, Err(rpc.Err))
+	}
+	if l.DebugEnabled() {
+		if rpc.Request != nil {
+			attrs = append(attrs, Any("request", rpc.Request))
+		}
+		if rpc.Response != nil {
+			attrs = append(attrs, Any("response", rpc.Response))
+		}
+	}
+	level := INFO
+	switch {
+	case rpcClientCodes[code]:
+		level = WARNING
+	case code != "OK":
+		level = ERROR
+	}
+	l.With(attrs...).Chain().write(fmt.Sprintf("%s %s", rpc.Method, code), level)
+}
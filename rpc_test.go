@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLogRPC(t *testing.T) {
+	c := NewCapture()
+	c.LogRPC(RPC{Method: "/orders.Orders/Get", Latency: time.Millisecond, Peer: "10.0.0.1:5000", Request: "id=1"})
+	c.LogRPC(RPC{Method: "/orders.Orders/Get", Code: "NotFound", Err: errors.New("no order")})
+	c.LogRPC(RPC{Method: "/orders.Orders/Put", Code: "Internal"})
+	entries := c.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected three entries, got %v", entries)
+	}
+	if entries[0].Level != INFO || entries[0].Message != "/orders.Orders/Get OK" {
+		t.Errorf("expected a successful call at info, got %s", entries[0])
+	}
+	if v, _ := entries[0].Field("peer"); v != "10.0.0.1:5000" {
+		t.Errorf("expected the peer to be logged, got %v", v)
+	}
+	if entries[1].Level != WARNING || entries[2].Level != ERROR {
+		t.Errorf("expected caller errors at warning and server errors at error, got %s and %s", entries[1].Level, entries[2].Level)
+	}
+}
+
+func TestLogRPCPayloads(t *testing.T) {
+	quiet := NewCapture()
+	quiet.SetLevel(LevelWarning)
+	quiet.LogRPC(RPC{Method: "/m", Code: "Internal", Request: "secret"})
+	if _, ok := quiet.Entries()[0].Field("request"); ok {
+		t.Error("expected payloads to be left out when debug is disabled")
+	}
+}
@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// suppressionReportInterval is the minimum time between entries reporting suppressed counts
+const suppressionReportInterval = 10 * time.Second
+
+type sampler struct {
+	mu         sync.Mutex
+	every      map[string]uint64
+	seen       map[string]uint64
+	buckets    map[string]*tokenBucket
+	suppressed uint64
+	total      uint64
+	lastReport time.Time
+}
+
+type tokenBucket struct {
+	rate, burst, tokens float64
+	last                time.Time
+}
+
+func (l *Log) getSampler() *sampler {
+	if l.sampler == nil {
+		l.sampler = &sampler{
+			every:   make(map[string]uint64),
+			seen:    make(map[string]uint64),
+			buckets: make(map[string]*tokenBucket),
+		}
+	}
+	return l.sampler
+}
+
+// Suppressed returns the total number of entries dropped by sampling and rate limiting
+func (l *Log) Suppressed() uint64 {
+	if l.sampler == nil {
+		return 0
+	}
+	l.sampler.mu.Lock()
+	defer l.sampler.mu.Unlock()
+	return l.sampler.total
+}
+
+// allow reports whether an entry at the given level should be written. If entries have been
+// suppressed since the last report and the report interval has passed, a summary message is returned
+func (s *sampler) allow(level string, now time.Time) (bool, string) {
+	if s == nil {
+		return true, ""
+	}
+	level = strings.ToUpper(level)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.sample(level) || !s.take(level, now) {
+		s.suppressed++
+		s.total++
+		return false, ""
+	}
+	return true, s.summary(now, false)
+}
+
+// report returns a summary of the entries suppressed since the last report, if there are
+// any and the report interval has passed or force is set
+func (s *sampler) report(now time.Time, force bool) string {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.summary(now, force)
+}
+
+// summary is report with the lock held
+func (s *sampler) summary(now time.Time, force bool) string {
+	if s.suppressed == 0 || (!force && now.Sub(s.lastReport) < suppressionReportInterval) {
+		return ""
+	}
+	summary := fmt.Sprintf("suppressed %d entries due to sampling or rate limiting", s.suppressed)
+	s.suppressed = 0
+	s.lastReport = now
+	return summary
+}
+
+// watchSuppressed reports suppressed entries every report interval until the log is closed,
+// so that a summary is written even when nothing else is allowed through
+func (l *Log) watchSuppressed() {
+	ticker := time.NewTicker(suppressionReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-l.done:
+			return
+		}
+		if summary := l.sampler.report(l.now(), false); summary != "" {
+			l.writeEntry(l.newEntry(WARNING, summary))
+		}
+	}
+}
+
+func (s *sampler) sample(level string) bool {
+	n, ok := s.every[level]
+	if !ok || n <= 1 {
+		return true
+	}
+	s.seen[level]++
+	return (s.seen[level]-1)%n == 0
+}
+
+func (s *sampler) take(level string, now time.Time) bool {
+	b, ok := s.buckets[level]
+	if !ok {
+		return true
+	}
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSampling(t *testing.T) {
+	sl, err := NewLog(filepath.Join(t.TempDir(), "sampling.log"), "TEST", LEVEL_INFO, LEVEL_NONE, WithRingBuffer(20), WithSampling(DEBUG, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 9; i++ {
+		sl.Debug("sampled")
+	}
+	written := 0
+	for _, e := range sl.RecentEntries() {
+		if e.Level == DEBUG {
+			written++
+		}
+	}
+	if written != 3 {
+		t.Errorf("expected 3 sampled debug entries to be written, got %d", written)
+	}
+	if sl.Suppressed() != 6 {
+		t.Errorf("expected 6 suppressed entries, got %d", sl.Suppressed())
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s := &sampler{
+		every:   map[string]uint64{},
+		seen:    map[string]uint64{},
+		buckets: map[string]*tokenBucket{INFO: {rate: 1, burst: 2, tokens: 2}},
+	}
+	now := time.Now()
+	for i, expected := range []bool{true, true, false, false} {
+		if ok, _ := s.allow(INFO, now); ok != expected {
+			t.Errorf("expected call %d to be allowed=%t, got %t", i, expected, ok)
+		}
+	}
+	ok, summary := s.allow(INFO, now.Add(suppressionReportInterval))
+	if !ok {
+		t.Fatal("expected the bucket to have refilled")
+	}
+	if summary == "" {
+		t.Error("expected a summary of suppressed entries")
+	}
+}
+
+func TestSamplingSummaryOnClose(t *testing.T) {
+	c := NewCapture(WithSampling(DEBUG, 10))
+	for i := 0; i < 4; i++ {
+		c.Debug("sampled")
+	}
+	c.Close()
+	entries := c.Entries()
+	if last := entries[len(entries)-1]; last.Message != "suppressed 3 entries due to sampling or rate limiting" {
+		t.Errorf("expected the suppressed entries to be reported on close, got %q", last.Message)
+	}
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ansiEscape matches ANSI CSI sequences, such as colours and cursor movement, and OSC
+// sequences, such as terminal titles and hyperlinks
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+
+// validUTF8 replaces invalid UTF-8 sequences with U+FFFD
+func validUTF8(message string) string {
+	if utf8.ValidString(message) {
+		return message
+	}
+	return strings.ToValidUTF8(message, "\uFFFD")
+}
+
+// stripANSI removes ANSI escape sequences
+func stripANSI(message string) string {
+	if strings.IndexByte(message, 0x1b) < 0 {
+		return message
+	}
+	return ansiEscape.ReplaceAllString(message, "")
+}
+
+// lineBreaks escapes the line breaks in single-line mode
+var lineBreaks = strings.NewReplacer("\n", `\n`, "\r", `\r`)
+
+// escapeLineBreaks escapes newlines and carriage returns, leaving other characters untouched
+func escapeLineBreaks(message string) string {
+	if !strings.ContainsAny(message, "\n\r") {
+		return message
+	}
+	return lineBreaks.Replace(message)
+}
+
+// escapeControl replaces control characters with their escaped representation
+func escapeControl(message string) string {
+	if strings.IndexFunc(message, unicode.IsControl) < 0 {
+		return message
+	}
+	var b strings.Builder
+	for _, r := range message {
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x100 && unicode.IsControl(r):
+			fmt.Fprintf(&b, `\x%02x`, r)
+		case unicode.IsControl(r):
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	injected := "user input\n[2020-01-01T00:00:00Z] [TEST.INFO] forged entry\x1b[31m"
+	expected := `user input\n[2020-01-01T00:00:00Z] [TEST.INFO] forged entry\x1b[31m`
+	if result := escapeControl(injected); result != expected {
+		t.Errorf("expected '%s', got '%s'", expected, result)
+	}
+	sl, err := NewLog(filepath.Join(t.TempDir(), "sanitize.log"), "TEST", LEVEL_INFO, LEVEL_NONE, WithSanitize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sl.Info(injected)
+	result, err := sl.GetLog(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 {
+		t.Errorf("expected the injected newline not to create an extra record, got %d records", len(result))
+	}
+}
+
+func TestSingleLine(t *testing.T) {
+	c := NewCapture(WithSingleLine())
+	c.WithFields(Fields{"body": "a\r\nb"}).Info("stack:\n\tmain.go:12\r\n\x1b[0m")
+	record := c.Entries()[0].String()
+	if !strings.HasSuffix(record, `stack:\n`+"\t"+`main.go:12\r\n`+"\x1b[0m"+` body="a\r\nb"`) {
+		t.Errorf("expected only line breaks to be escaped, got %q", record)
+	}
+	if strings.ContainsAny(record, "\r\n") {
+		t.Errorf("expected a single physical line, got %q", record)
+	}
+}
+
+func TestValidUTF8(t *testing.T) {
+	c := NewCapture()
+	c.Info("binary \xff\xfe tail")
+	if message := c.Entries()[0].Message; message != "binary � tail" {
+		t.Errorf("expected invalid UTF-8 to be replaced, got %q", message)
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	c := NewCapture(WithStripANSI())
+	c.Info("\x1b[1;31mfailed\x1b[0m see \x1b]8;;https://example.com\x07docs\x1b]8;;\x07")
+	if message := c.Entries()[0].Message; message != "failed see docs" {
+		t.Errorf("expected escape sequences to be stripped, got %q", message)
+	}
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// fieldScope holds the fields pushed onto a log by PushFields, shared with its children
+type fieldScope struct {
+	mu     sync.Mutex
+	depth  atomic.Int32 // len(frames), read without the lock on every write
+	frames []scopeFrame
+	next   uint64
+}
+
+type scopeFrame struct {
+	id     uint64
+	fields Fields
+}
+
+// PushFields adds fields to every entry written by the log and its children until the
+// returned function is called, without creating a child log:
+//
+//	defer l.PushFields(Fields{"job": id})()
+//
+// The fields apply to every goroutine writing to the log, so scope per-goroutine context with
+// WithFields instead. Later pushes override earlier ones and the log's own fields
+func (l *Log) PushFields(fields Fields) (pop func()) {
+	s := l.scope
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	id := s.next
+	s.frames = append(s.frames, scopeFrame{id: id, fields: fields})
+	s.depth.Store(int32(len(s.frames)))
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			for i, f := range s.frames {
+				if f.id == id {
+					s.frames = append(s.frames[:i], s.frames[i+1:]...)
+					break
+				}
+			}
+			s.depth.Store(int32(len(s.frames)))
+		})
+	}
+}
+
+// scopedFields returns the log's fields merged with those pushed onto its scope
+func (l *Log) scopedFields() Fields {
+	if l.scope == nil || l.scope.depth.Load() == 0 {
+		return l.fields
+	}
+	l.scope.mu.Lock()
+	defer l.scope.mu.Unlock()
+	fields := make(Fields, len(l.fields))
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	for _, f := range l.scope.frames {
+		for k, v := range f.fields {
+			fields[k] = v
+		}
+	}
+	return fields
+}
@@ -0,0 +1,34 @@
+package logging
+
+import "testing"
+
+func TestPushFields(t *testing.T) {
+	c := NewCapture()
+	l := c.WithFields(Fields{"region": "eu-west-1"})
+	func() {
+		defer l.PushFields(Fields{"job": "nightly"})()
+		pop := l.PushFields(Fields{"step": 1, "region": "us-east-1"})
+		l.Info("first")
+		pop()
+		pop()
+		l.WithFields(Fields{"table": "orders"}).Info("second")
+	}()
+	l.Info("third")
+	entries := c.Entries()
+	expected := []Fields{
+		{"region": "us-east-1", "job": "nightly", "step": 1},
+		{"region": "eu-west-1", "job": "nightly", "table": "orders"},
+		{"region": "eu-west-1"},
+	}
+	for i, fields := range expected {
+		if len(entries[i].Fields) != len(fields) {
+			t.Errorf("expected entry %d to have fields %v, got %v", i, fields, entries[i].Fields)
+			continue
+		}
+		for k, v := range fields {
+			if entries[i].Fields[k] != v {
+				t.Errorf("expected entry %d to have fields %v, got %v", i, fields, entries[i].Fields)
+			}
+		}
+	}
+}
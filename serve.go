@@ -0,0 +1,196 @@
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultServeLimit is the number of records returned when a request gives no limit
+const defaultServeLimit = 100
+
+// RecordQuery selects records from a log file; see QueryRecords
+type RecordQuery struct {
+	Level    string    // only entries at this level, case insensitive, if set
+	Contains string    // only records containing this text, case insensitive, if set
+	From, To time.Time // only entries written from From up to but not including To, if set
+	Limit    int       // at most this many of the newest matching records, if positive
+}
+
+func (q RecordQuery) matches(record string, e Entry) bool {
+	if q.Level != "" && !strings.EqualFold(e.Level, q.Level) {
+		return false
+	}
+	if q.Contains != "" && !strings.Contains(strings.ToLower(record), strings.ToLower(q.Contains)) {
+		return false
+	}
+	if !q.From.IsZero() && e.Time.Before(q.From) {
+		return false
+	}
+	return q.To.IsZero() || e.Time.Before(q.To)
+}
+
+// QueryRecords reads records from r and returns the entries matching q, oldest first.
+// Records that cannot be parsed are skipped
+func QueryRecords(r io.Reader, q RecordQuery) ([]Entry, error) {
+	return queryRecords(r, q, nil)
+}
+
+// queryRecords is QueryRecords, seeing each record through profile if it is not nil
+func queryRecords(r io.Reader, q RecordQuery, profile *RedactionProfile) ([]Entry, error) {
+	result := make([]Entry, 0)
+	scanner := newRecordScanner(r)
+	for {
+		record, err := scanner.next()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+		if profile != nil {
+			record = profile.Apply(record)
+		}
+		e, err := ParseEntry(record)
+		if err != nil || !q.matches(record, e) {
+			continue
+		}
+		result = append(result, e)
+		if q.Limit > 0 && len(result) > q.Limit {
+			result = append(result[:0], result[1:]...)
+		}
+	}
+}
+
+// servedEntry is an entry as returned by FileHandler
+type servedEntry struct {
+	Time    time.Time `json:"time"`
+	Env     string    `json:"env,omitempty"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	Fields  Fields    `json:"fields,omitempty"`
+}
+
+// FileHandler returns a handler serving a small web UI over the log file at path at /, and
+// the newest matching records as a JSON array at /records. The records endpoint takes the
+// query parameters level, q (text to search for), from and to (RFC 3339 times) and limit,
+// 100 by default; see QueryRecords. The file is read afresh on each request. If profile is not
+// nil, records are served, and searched, as seen through it, so that a support profile can
+// mask personal data. Protect the handler with TokenAuth or BasicAuth if it is reachable by
+// others
+func FileHandler(path string, profile *RedactionProfile) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/records", func(w http.ResponseWriter, r *http.Request) {
+		q, err := parseRecordQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+		entries, err := queryRecords(file, q, profile)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		served := make([]servedEntry, len(entries))
+		for i, e := range entries {
+			served[i] = servedEntry{Time: e.Time, Env: e.Env, Level: e.Level, Message: e.Message, Fields: e.Fields}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(served)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, serveUI)
+	})
+	return mux
+}
+
+func parseRecordQuery(r *http.Request) (RecordQuery, error) {
+	values := r.URL.Query()
+	q := RecordQuery{Level: values.Get("level"), Contains: values.Get("q"), Limit: defaultServeLimit}
+	var err error
+	if limit := values.Get("limit"); limit != "" {
+		if q.Limit, err = strconv.Atoi(limit); err != nil {
+			return q, err
+		}
+	}
+	if from := values.Get("from"); from != "" {
+		if q.From, err = time.Parse(time.RFC3339Nano, from); err != nil {
+			return q, err
+		}
+	}
+	if to := values.Get("to"); to != "" {
+		if q.To, err = time.Parse(time.RFC3339Nano, to); err != nil {
+			return q, err
+		}
+	}
+	return q, nil
+}
+
+const serveUI = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Log</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+form { margin-bottom: 1em; }
+pre { margin: 0; white-space: pre-wrap; }
+td { vertical-align: top; padding: 0 .5em; font-family: monospace; }
+.ERROR, .CRITICAL, .ALERT, .EMERGENCY { color: #b00; }
+.WARNING { color: #a60; }
+</style>
+</head>
+<body>
+<form id="query">
+<input name="q" placeholder="search">
+<input name="level" placeholder="level" size="8">
+<input name="from" placeholder="from (RFC 3339)">
+<input name="to" placeholder="to (RFC 3339)">
+<input name="limit" value="100" size="5">
+<button>Show</button>
+<label><input type="checkbox" id="follow"> follow</label>
+</form>
+<table id="records"></table>
+<script>
+const form = document.getElementById("query");
+async function load() {
+	const params = new URLSearchParams(new FormData(form));
+	const res = await fetch("records?" + params);
+	const table = document.getElementById("records");
+	table.textContent = "";
+	if (!res.ok) {
+		table.insertRow().insertCell().textContent = await res.text();
+		return;
+	}
+	for (const e of await res.json()) {
+		const row = table.insertRow();
+		row.className = e.level;
+		row.insertCell().textContent = e.time;
+		row.insertCell().textContent = e.level;
+		const pre = document.createElement("pre");
+		pre.textContent = e.fields ? e.message + " " + JSON.stringify(e.fields) : e.message;
+		row.insertCell().appendChild(pre);
+	}
+}
+form.addEventListener("submit", ev => { ev.preventDefault(); load(); });
+setInterval(() => { if (document.getElementById("follow").checked) load(); }, 2000);
+load();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(path, []byte(strings.Join([]string{
+		"[2024-01-02T00:00:00Z] [TEST.INFO] started",
+		"[2024-01-02T00:01:00Z] [TEST.ERROR] disk full on /var",
+		"[2024-01-02T00:02:00Z] [TEST.ERROR] disk full on /tmp",
+		"[2024-01-02T00:03:00Z] [TEST.INFO] disk freed",
+	}, "\n")+"\n"), 0644)
+	handler := FileHandler(path, nil)
+	get := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", url, nil))
+		return rec
+	}
+	rec := get("/records?level=error&q=DISK&limit=1")
+	var entries []servedEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Message != "disk full on /tmp" {
+		t.Errorf("expected the newest matching error, got %v", entries)
+	}
+	rec = get("/records?from=2024-01-02T00:01:00Z&to=2024-01-02T00:03:00Z")
+	json.Unmarshal(rec.Body.Bytes(), &entries)
+	if len(entries) != 2 || entries[0].Level != ERROR {
+		t.Errorf("expected the entries in range, got %v", entries)
+	}
+	if rec = get("/records?from=yesterday"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected an invalid time to be refused, got %d", rec.Code)
+	}
+	if rec = get("/"); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<table") {
+		t.Errorf("expected the UI, got %d", rec.Code)
+	}
+}
+
+func TestFileHandlerProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(path, []byte(strings.Join([]string{
+		"[2024-01-02T00:00:00Z] [TEST.INFO] signed up email=ann@example.com",
+		`{"time":"2024-01-02T00:01:00Z","level":"INFO","message":"paid","email":"bob@example.com","amount":5}`,
+	}, "\n")+"\n"), 0644)
+	handler := FileHandler(path, NewRedactionProfile([]string{"email"}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/records")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var entries []servedEntry
+	if err = json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Message != "signed up email="+fieldMask || entries[1].Fields["email"] != fieldMask {
+		t.Errorf("expected the email fields to be masked, got %+v", entries)
+	}
+	if entries[1].Fields["amount"] != 5.0 {
+		t.Errorf("expected other fields to be served, got %v", entries[1].Fields)
+	}
+	// masked values cannot be searched for either
+	resp, err = http.Get(server.URL + "/records?q=ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err = json.NewDecoder(resp.Body).Decode(&entries); err != nil || len(entries) != 0 {
+		t.Errorf("expected no match on a masked value, got %v and %v", entries, err)
+	}
+}
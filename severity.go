@@ -0,0 +1,35 @@
+package logging
+
+import "fmt"
+
+func (l *Log) Notice(message string) (string, error) {
+	return l.Write(message, NOTICE)
+}
+
+func (l *Log) Critical(message string) (string, error) {
+	return l.Write(message, CRITICAL)
+}
+
+func (l *Log) Alert(message string) (string, error) {
+	return l.Write(message, ALERT)
+}
+
+func (l *Log) Emergency(message string) (string, error) {
+	return l.Write(message, EMERGENCY)
+}
+
+func (l *Log) Noticef(message string, vars ...interface{}) (string, error) {
+	return l.Notice(fmt.Sprintf(message, vars...))
+}
+
+func (l *Log) Criticalf(message string, vars ...interface{}) (string, error) {
+	return l.Critical(fmt.Sprintf(message, vars...))
+}
+
+func (l *Log) Alertf(message string, vars ...interface{}) (string, error) {
+	return l.Alert(fmt.Sprintf(message, vars...))
+}
+
+func (l *Log) Emergencyf(message string, vars ...interface{}) (string, error) {
+	return l.Emergency(fmt.Sprintf(message, vars...))
+}
@@ -0,0 +1,273 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+var (
+	errShipperURL    = errors.New("shipper has no url")
+	errShipperFormat = errors.New("unknown shipper format")
+)
+
+// shipperFormats are the encoders a ShipperConfig can name, with the content type of each
+var shipperFormats = map[string]struct {
+	encoder     Encoder
+	contentType string
+}{
+	"json":   {JSONEncoder{}, "application/x-ndjson"},
+	"logfmt": {LogfmtEncoder{}, "text/plain"},
+	"text":   {TextEncoder{}, "text/plain"},
+}
+
+// ShipperConfig declares a remote destination for entries in one block: how they are
+// formatted, queued, batched, retried and secured, whether entries missed are caught up from
+// the log's files, and when its backlog is warned about. Adding a destination is then a
+// config entry rather than code. It decodes from JSON, with durations written as "5s".
+// Type selects the sink: http, the default, is an HTTPSink; others are provided by modules
+// with RegisterSink and read the fields that apply to them
+type ShipperConfig struct {
+	// Name names the shipper in backpressure warnings (default its URL, or its type)
+	Name string `json:"name"`
+	// Type is the registered type of the sink (default http)
+	Type string `json:"type"`
+	// URL is the endpoint entries are POSTed to; see HTTPSink
+	URL string `json:"url"`
+	// Options holds settings particular to the sink type, such as a Kafka topic
+	Options map[string]string `json:"options"`
+	// Header is added to every request, e.g. for an API key
+	Header map[string]string `json:"header"`
+	// Format is json (default, sent as newline-delimited JSON), logfmt or text
+	Format string `json:"format"`
+	// Proxy is the proxy URL, "direct" for none (default from the environment); see NewHTTPClient
+	Proxy string `json:"proxy"`
+	// TLS configures the connections, e.g. for mutual TLS
+	TLS TLSOptions `json:"tls"`
+	// BatchSize, BatchAge, MaxBuffered, QueueDir, QueueMaxBytes and Uncompressed are as in
+	// HTTPSinkOptions
+	BatchSize     int           `json:"batch_size"`
+	BatchAge      time.Duration `json:"batch_age"`
+	MaxBuffered   int           `json:"max_buffered"`
+	QueueDir      string        `json:"queue_dir"`
+	QueueMaxBytes int64         `json:"queue_max_bytes"`
+	Uncompressed  bool          `json:"uncompressed"`
+	// Retries is the number of attempts made at each request, waiting RetryBackoff, doubling
+	// up to RetryMaxDelay, between them (default one attempt); see RetryPolicy
+	Retries       int           `json:"retries"`
+	RetryBackoff  time.Duration `json:"retry_backoff"`
+	RetryMaxDelay time.Duration `json:"retry_max_delay"`
+	// CatchUpMark, if set, is the mark file of a CatchUpSink, so that entries missed while the
+	// shipper was disabled or failing are sent from the log's files, retried every
+	// CatchUpInterval; see WithCatchUp
+	CatchUpMark     string        `json:"catch_up_mark"`
+	CatchUpInterval time.Duration `json:"catch_up_interval"`
+	// BackpressureDepth and BackpressureAge are the thresholds at which the shipper's backlog is
+	// warned about (default those of WithBackpressureWarning)
+	BackpressureDepth int64         `json:"backpressure_depth"`
+	BackpressureAge   time.Duration `json:"backpressure_age"`
+	// FS is the filesystem QueueDir is on (default the operating system's). WithShipper sets
+	// it to the log's
+	FS FS `json:"-"`
+}
+
+// UnmarshalJSON decodes the config, reading durations as strings such as "500ms"
+func (c *ShipperConfig) UnmarshalJSON(data []byte) error {
+	type plain ShipperConfig
+	aux := struct {
+		*plain
+		BatchAge        string `json:"batch_age"`
+		RetryBackoff    string `json:"retry_backoff"`
+		RetryMaxDelay   string `json:"retry_max_delay"`
+		CatchUpInterval string `json:"catch_up_interval"`
+		BackpressureAge string `json:"backpressure_age"`
+	}{plain: (*plain)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	for _, d := range []struct {
+		text string
+		dst  *time.Duration
+	}{
+		{aux.BatchAge, &c.BatchAge},
+		{aux.RetryBackoff, &c.RetryBackoff},
+		{aux.RetryMaxDelay, &c.RetryMaxDelay},
+		{aux.CatchUpInterval, &c.CatchUpInterval},
+		{aux.BackpressureAge, &c.BackpressureAge},
+	} {
+		if d.text == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(d.text)
+		if err != nil {
+			return err
+		}
+		*d.dst = duration
+	}
+	return nil
+}
+
+// Shipper is the sink built from a ShipperConfig by the factory of its type, wrapped in a
+// CatchUpSink if the config has a mark file
+type Shipper struct {
+	name    string
+	config  ShipperConfig
+	base    Sink
+	sink    Sink
+	catchUp *CatchUpSink
+}
+
+// NewShipper builds the sink a config declares
+func NewShipper(c ShipperConfig) (*Shipper, error) {
+	if c.Type == "" {
+		c.Type = "http"
+	}
+	base, err := newRegisteredSink(c)
+	if err != nil {
+		return nil, err
+	}
+	s := &Shipper{name: c.Name, config: c, base: base, sink: base}
+	if s.name == "" {
+		s.name = c.URL
+	}
+	if s.name == "" {
+		s.name = c.Type
+	}
+	if c.CatchUpMark != "" {
+		if s.catchUp, err = NewCatchUpSink(base, c.CatchUpMark); err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.sink = s.catchUp
+	}
+	return s, nil
+}
+
+// newHTTPShipperSink is the factory of the http sink type, an HTTPSink
+func newHTTPShipperSink(c ShipperConfig) (Sink, error) {
+	if c.URL == "" {
+		return nil, errShipperURL
+	}
+	if c.Format == "" {
+		c.Format = "json"
+	}
+	format, ok := shipperFormats[c.Format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errShipperFormat, c.Format)
+	}
+	tlsConfig, err := c.TLS.Config()
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewHTTPClient(tlsConfig, c.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	opts := HTTPSinkOptions{
+		Client:        client,
+		Encoder:       format.encoder,
+		ContentType:   format.contentType,
+		Header:        http.Header{},
+		BatchSize:     c.BatchSize,
+		BatchAge:      c.BatchAge,
+		MaxBuffered:   c.MaxBuffered,
+		QueueDir:      c.QueueDir,
+		QueueMaxBytes: c.QueueMaxBytes,
+		Uncompressed:  c.Uncompressed,
+		FS:            c.FS,
+	}
+	for key, value := range c.Header {
+		opts.Header.Set(key, value)
+	}
+	if c.Retries > 1 {
+		opts.Retry = &RetryPolicy{Attempts: c.Retries, Backoff: c.RetryBackoff, MaxDelay: c.RetryMaxDelay}
+	}
+	return NewHTTPSink(c.URL, opts)
+}
+
+// WriteEntry queues the entry to be shipped
+func (s *Shipper) WriteEntry(e Entry) error {
+	return s.sink.WriteEntry(e)
+}
+
+// QueueStats returns the backlog of the sink's queue, if it has one
+func (s *Shipper) QueueStats() QueueStats {
+	if q, ok := s.base.(QueueStatter); ok {
+		return q.QueueStats()
+	}
+	return QueueStats{}
+}
+
+// Check checks the sink; see Checker
+func (s *Shipper) Check() error {
+	return checkSink(s.base)
+}
+
+// Drain sends every entry waiting, if the sink buffers them; see Drainer
+func (s *Shipper) Drain(ctx context.Context) (flushed, dropped int, err error) {
+	if d, ok := s.base.(Drainer); ok {
+		return d.Drain(ctx)
+	}
+	return 0, 0, nil
+}
+
+// Close closes the sink, if it is an io.Closer
+func (s *Shipper) Close() error {
+	if c, ok := s.base.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (s *Shipper) backpressureLimits() (string, int64, time.Duration) {
+	return s.name, s.config.BackpressureDepth, s.config.BackpressureAge
+}
+
+// WithShipper adds the sink a config declares, after the sinks of WithSink, catching it up
+// from the log's files if it has a mark file and watching its backlog against its own
+// thresholds. The sink is built by NewLog, on the log's FS, so that a clone builds none and
+// a log that fails to open leaves none running
+func WithShipper(c ShipperConfig) Option {
+	return func(l *Log) {
+		l.shippers = append(l.shippers, c)
+		if (c.BackpressureDepth > 0 || c.BackpressureAge > 0) && l.backpressure == nil {
+			WithBackpressureWarning(0, 0, 0)(l)
+		}
+	}
+}
+
+// startShippers builds the sinks of the shipper configs, returning them so that they can be
+// closed if the log then fails to open. On error those already built are closed
+func (l *Log) startShippers() ([]*Shipper, error) {
+	started := make([]*Shipper, 0, len(l.shippers))
+	for _, c := range l.shippers {
+		if c.FS == nil {
+			c.FS = l.fs
+		}
+		s, err := NewShipper(c)
+		if err != nil {
+			closeShippers(started)
+			return nil, err
+		}
+		started = append(started, s)
+		l.sinks = append(l.sinks, s)
+		if s.catchUp != nil {
+			interval := c.CatchUpInterval
+			if interval <= 0 {
+				interval = defaultCatchUpInterval
+			}
+			l.catchUps = append(l.catchUps, catchUp{sink: s.catchUp, interval: interval})
+		}
+	}
+	return started, nil
+}
+
+func closeShippers(shippers []*Shipper) {
+	for _, s := range shippers {
+		s.Close()
+	}
+}
@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShipper(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	var config ShipperConfig
+	err := json.Unmarshal([]byte(`{
+		"name": "collector",
+		"url": "`+server.URL+`",
+		"header": {"Authorization": "Bearer key"},
+		"batch_size": 10,
+		"batch_age": "1h",
+		"retries": 3,
+		"retry_backoff": "10ms",
+		"backpressure_depth": 2
+	}`), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.BatchAge != time.Hour || config.RetryBackoff != 10*time.Millisecond {
+		t.Fatalf("expected the durations to be decoded, got %+v", config)
+	}
+	sl, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithShipper(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sl.Info("one")
+	sl.Info("two")
+	// the shipper's own threshold applies without WithBackpressureWarning thresholds
+	sl.checkBackpressure()
+	if err = sl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, messages := c.received(); strings.Join(messages, ",") != "initialising log,one,two,write queue backlog exceeds threshold" {
+		t.Errorf("expected the entries to be shipped on close, got %v", messages)
+	}
+
+	if _, err = NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithShipper(ShipperConfig{URL: server.URL, Format: "xml"})); err == nil {
+		t.Error("expected an unknown format to be rejected")
+	}
+}
+
+func TestShipperClone(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	fs := NewMemFS()
+	config := ShipperConfig{URL: server.URL, QueueDir: "/queue", BatchAge: time.Hour, CatchUpMark: filepath.Join(t.TempDir(), "mark")}
+	sl, err := NewLog("/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithShipper(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sl.Close()
+	if names := fs.Names(); !strings.Contains(strings.Join(names, ","), "/queue/") {
+		t.Errorf("expected the shipper's queue to be on the log's FS, got %v", names)
+	}
+	clone, err := sl.Clone(WithPath("/job.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clone.Close()
+	// a clone building its own shipper would open a second queue on the same directory
+	if len(clone.sinks) != 0 || len(clone.catchUps) != 0 {
+		t.Errorf("expected the clone to have no shipper, got %d sinks and %d catch-ups", len(clone.sinks), len(clone.catchUps))
+	}
+}
+
+func TestShipperDrainContext(t *testing.T) {
+	c := &collector{down: true}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	s, err := NewShipper(ShipperConfig{URL: server.URL, BatchAge: time.Hour, Retries: 5, RetryBackoff: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.WriteEntry(Entry{Time: time.Now(), Level: INFO, Message: "one"})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, _, err = s.Drain(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected Drain to stop at its deadline, got %v", err)
+	}
+	// the retry waiting an hour is abandoned rather than holding up Close
+	s.Close()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected Drain and Close to return promptly, took %s", elapsed)
+	}
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+)
+
+var signatureSuffix = newRecordSuffix("sig")
+
+func (l *Log) sign(msg []byte) []byte {
+	if len(l.signingKey) == 0 {
+		return msg
+	}
+	return signatureSuffix.append(msg, signature(l.signingKey, string(msg)))
+}
+
+func signature(key []byte, record string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(record))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the HMAC signature of a single record written with WithSigning.
+// A trailing audit chain hash, if present, is ignored
+func VerifySignature(record string, key []byte) error {
+	record, _, _ = chainSuffix.split(record)
+	record, sig, ok := signatureSuffix.split(record)
+	if !ok {
+		return fmt.Errorf("record has no signature")
+	}
+	expected := signature(key, record)
+	if !hmac.Equal([]byte(expected), []byte(sig)) {
+		return fmt.Errorf("record signature is invalid")
+	}
+	return nil
+}
+
+// VerifySignatures checks the signature of every record in a file, returning an error
+// identifying the first record that fails
+func VerifySignatures(path string, key []byte) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for i, record := range splitRecords(string(b)) {
+		if err = VerifySignature(record, key); err != nil {
+			return fmt.Errorf("record %d: %s", i+1, err.Error())
+		}
+	}
+	return nil
+}
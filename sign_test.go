@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSigning(t *testing.T) {
+	key := []byte("signing key")
+	path := filepath.Join(t.TempDir(), "signed.log")
+	sl, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithSigning(key), WithHashChain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, _ := sl.Info("payment accepted")
+	if err = VerifySignature(result, key); err != nil {
+		t.Errorf("expected record to verify, got %s", err.Error())
+	}
+	if err = VerifySignature(result, []byte("wrong key")); err == nil {
+		t.Error("expected verification with the wrong key to fail")
+	}
+	if err = VerifySignatures(path, key); err != nil {
+		t.Errorf("expected file to verify, got %s", err.Error())
+	}
+	b, _ := os.ReadFile(path)
+	os.WriteFile(path, []byte(strings.Replace(string(b), "accepted", "refunded", 1)), 0644)
+	err = VerifySignatures(path, key)
+	if err == nil || !strings.Contains(err.Error(), "record 2") {
+		t.Errorf("expected verification to fail at record 2, got %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"os"
+	"os/signal"
+)
+
+// onSignal calls handle for each of the signals received until the returned function is
+// called. It does nothing if there are no signals, rather than relaying every signal
+func onSignal(sigs []os.Signal, handle func(os.Signal)) (stop func()) {
+	if len(sigs) == 0 {
+		return func() {}
+	}
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+	go func() {
+		for {
+			select {
+			case sig := <-ch:
+				handle(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
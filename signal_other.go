@@ -0,0 +1,14 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package logging
+
+import "os"
+
+// There are no default signals on platforms without SIGQUIT, SIGHUP, SIGUSR1 and SIGUSR2;
+// pass the signals to handle explicitly
+var (
+	dumpSignals      = []os.Signal{}
+	reloadSignals    = []os.Signal{}
+	rotateSignals    = []os.Signal{}
+	verbositySignals = []os.Signal{}
+)
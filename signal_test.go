@@ -0,0 +1,105 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package logging
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestDumpOnSignal(t *testing.T) {
+	c := NewCapture()
+	stop := c.DumpOnSignal(syscall.SIGUSR1)
+	defer stop()
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(c.Entries()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	entries := c.Entries()
+	if len(entries) != 1 || entries[0].Level != ERROR {
+		t.Fatalf("expected a single error entry, got %v", entries)
+	}
+	if !strings.Contains(entries[0].Message, "goroutine dump on user defined signal 1\ngoroutine ") ||
+		!strings.Contains(entries[0].Message, "TestDumpOnSignal") {
+		t.Errorf("expected a dump of every goroutine, got %s", entries[0].Message)
+	}
+}
+
+func TestReloadLevelsOnSignal(t *testing.T) {
+	rl, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handled := make(chan error, 1)
+	rl.SetErrorHandler(func(err error) {
+		handled <- err
+	})
+	stop := rl.ReloadLevelsOnSignal(syscall.SIGHUP)
+	defer stop()
+	t.Setenv(EnvLevel, "error")
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	deadline := time.Now().Add(5 * time.Second)
+	for rl.Level() != LevelError && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if rl.Level() != LevelError {
+		t.Fatalf("expected the level to be reloaded on SIGHUP, got %s", rl.Level())
+	}
+	t.Setenv(EnvLevel, "loud")
+	rl.LoadEnvLevels()
+	if err := <-handled; err == nil || rl.Level() != LevelError {
+		t.Errorf("expected an invalid level to be reported and ignored, got %v", err)
+	}
+}
+
+func TestVerbosityOnSignal(t *testing.T) {
+	c := NewCapture()
+	c.SetLevel(LevelWarning)
+	stop := c.VerbosityOnSignal(nil, nil)
+	defer stop()
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Level() != LevelError && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if c.Level() != LevelError {
+		t.Errorf("expected SIGUSR2 to lower the verbosity, got %s", c.Level())
+	}
+}
+
+func TestVerbosityOnChosenSignal(t *testing.T) {
+	c := NewCapture()
+	c.SetLevel(LevelWarning)
+	stop := c.VerbosityOnSignal(syscall.SIGWINCH, nil)
+	defer stop()
+	syscall.Kill(syscall.Getpid(), syscall.SIGWINCH)
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Level() == LevelWarning && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if c.Level() != LevelDebug {
+		t.Errorf("expected SIGWINCH to raise the verbosity, got %s", c.Level())
+	}
+}
+
+func TestRotateOnSignal(t *testing.T) {
+	fs := NewMemFS()
+	rl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := rl.RotateOnSignal()
+	defer stop()
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if backups, _ := fs.Glob("/logs/app.log.*"); len(backups) == 1 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("expected the log to be rotated on SIGHUP")
+}
@@ -0,0 +1,20 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package logging
+
+import (
+	"os"
+	"syscall"
+)
+
+// The default signals of the *OnSignal methods, allocated here so that no two purposes clash:
+// SIGQUIT dumps the goroutines, SIGHUP reloads the levels and rotates the log, as daemons
+// reload their config and reopen their logs on it, and SIGUSR1 and SIGUSR2 raise and lower
+// the verbosity
+var (
+	dumpSignals   = []os.Signal{syscall.SIGQUIT}
+	reloadSignals = []os.Signal{syscall.SIGHUP}
+	rotateSignals = []os.Signal{syscall.SIGHUP}
+	// verbositySignals raise then lower the verbosity
+	verbositySignals = []os.Signal{syscall.SIGUSR1, syscall.SIGUSR2}
+)
@@ -0,0 +1,115 @@
+package logging
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// Sink is a destination for log entries in addition to the log file
+type Sink interface {
+	WriteEntry(e Entry) error
+}
+
+// writeSinks passes the entry to every sink, returning the first error encountered and
+// recording the sinks that accepted it in res if it is not nil
+func (l *Log) writeSinks(e Entry, res *WriteResult) (err error) {
+	for _, s := range l.sinks {
+		sErr := s.WriteEntry(e)
+		if sErr == nil && res != nil {
+			res.Sinks = append(res.Sinks, s)
+		}
+		if sErr != nil && err == nil {
+			err = sErr
+		}
+	}
+	return err
+}
+
+type writerSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink returns a sink writing each entry as a line to w, e.g. os.Stderr
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{w: w}
+}
+
+func (s *writerSink) WriteEntry(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := io.WriteString(s.w, e.String()+"\n")
+	return err
+}
+
+type encoderSink struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc Encoder
+}
+
+// NewEncoderSink returns a sink writing each entry to w as a line formatted by enc, such as a
+// LevelEncoder to format some levels differently
+func NewEncoderSink(w io.Writer, enc Encoder) Sink {
+	return &encoderSink{w: w, enc: enc}
+}
+
+func (s *encoderSink) WriteEntry(e Entry) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	*buf = append(s.enc.AppendEntry((*buf)[:0], e), '\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(*buf)
+	return err
+}
+
+type fileSink struct {
+	mu   sync.Mutex
+	path string
+	mode os.FileMode
+}
+
+// NewFileSink returns a sink appending each entry to the file at path, creating it if needed
+// with the file mode of the log it is added to (see WithFileMode)
+func NewFileSink(path string) Sink {
+	return &fileSink{path: path}
+}
+
+func (s *fileSink) WriteEntry(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mode := s.mode
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.WriteString(e.String() + "\n")
+	return err
+}
+
+type fallbackSink struct {
+	primary, fallback Sink
+}
+
+// FallbackSink returns a sink writing to primary, and to fallback when primary fails.
+// An error is only returned if both fail
+func FallbackSink(primary, fallback Sink) Sink {
+	return &fallbackSink{primary: primary, fallback: fallback}
+}
+
+func (s *fallbackSink) WriteEntry(e Entry) error {
+	err := s.primary.WriteEntry(e)
+	if err == nil {
+		return nil
+	}
+	if s.fallback.WriteEntry(e) == nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFallback(t *testing.T) {
+	var buf bytes.Buffer
+	dir := t.TempDir()
+	fl, err := NewLog(filepath.Join(dir, "primary.log"), "TEST", LEVEL_INFO, LEVEL_NONE, WithFallback(NewWriterSink(&buf)), WithNow(steppingClock(reopenCheckInterval)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// make the primary path unwritable by replacing the file with a directory
+	os.Remove(fl.Path())
+	os.Mkdir(fl.Path(), 0755)
+	result, err := fl.Error("disk went read-only")
+	if err != nil {
+		t.Fatalf("expected the fallback to absorb the failure, got %s", err.Error())
+	}
+	if !strings.Contains(result, "disk went read-only") || !strings.Contains(buf.String(), "disk went read-only") {
+		t.Errorf("expected the entry to be written to the fallback, got '%s'", buf.String())
+	}
+}
+
+func TestFallbackSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secondary.log")
+	s := FallbackSink(&failingSink{err: errors.New("down")}, NewFileSink(path))
+	if err := s.WriteEntry(Entry{Env: "TEST", Level: INFO, Message: "rerouted"}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "[TEST.INFO] rerouted") {
+		t.Errorf("expected the secondary file to contain the entry, got '%s'", string(b))
+	}
+}
+
+func TestFileSinkMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sink.log")
+	if _, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithSink(NewFileSink(path)), WithFileMode(0600)); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected the sink file to be created with the log's mode, got %v", info.Mode())
+	}
+}
+
+func TestEncoderSinkLevelEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc := LevelEncoder{
+		Levels: map[string]Encoder{ERROR: JSONEncoder{}, DEBUG: ConsoleEncoder{NoColor: true}},
+	}
+	el := NewTestLog(t, WithSink(NewEncoderSink(&buf, enc)))
+	buf.Reset()
+	el.Error("charge failed")
+	el.Debug("cache miss")
+	el.Info("started")
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "{") || !strings.Contains(lines[0], `"message":"charge failed"`) {
+		t.Errorf("expected the error as JSON, got %s", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], " DEBUG cache miss") {
+		t.Errorf("expected the debug entry as a console line, got %s", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "[") || !strings.HasSuffix(lines[2], "INFO] started") {
+		t.Errorf("expected the info entry in the default format, got %s", lines[2])
+	}
+}
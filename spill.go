@@ -0,0 +1,202 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultSpillMaxBytes caps a spill queue when SpillOptions.MaxBytes is zero
+	defaultSpillMaxBytes = 64 << 20
+	// defaultSpillRetryInterval is how often replay is attempted when SpillOptions.RetryInterval is zero
+	defaultSpillRetryInterval = 10 * time.Second
+)
+
+// SpillOptions configures a SpillSink
+type SpillOptions struct {
+	// MaxBytes caps the size of the queue on disk; the oldest entries are dropped beyond it.
+	// Zero means 64MB
+	MaxBytes int64
+	// MaxAge discards queued entries older than this instead of replaying them. Zero keeps them
+	MaxAge time.Duration
+	// RetryInterval is how often replay is attempted. Zero means 10s
+	RetryInterval time.Duration
+	// FileMode is the mode of the queue's files. Zero means 0666
+	FileMode os.FileMode
+	// FS is the filesystem the queue is on, such as the one given to WithFS. Nil means the
+	// operating system's
+	FS FS
+}
+
+// SpillSink wraps a sink, typically a remote one, spilling entries to a queue on disk while
+// it fails and replaying them in order from the background once it recovers. Entries written
+// while the queue is not empty join the end of it, so order is kept. The queue is the one an
+// HTTPSink keeps in its QueueDir, with the position replayed to recorded in a cursor file, so
+// it survives restarts: entries left in the directory are replayed by the next SpillSink
+// using it. Attributes of queued entries are replayed as fields
+type SpillSink struct {
+	mu        sync.Mutex
+	sink      Sink
+	opts      SpillOptions
+	queue     *diskQueue
+	dropped   atomic.Uint64
+	expired   atomic.Uint64
+	malformed atomic.Uint64
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewSpillSink returns a SpillSink queueing entries for sink in dir, which is created if needed
+func NewSpillSink(sink Sink, dir string, opts SpillOptions) (*SpillSink, error) {
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = defaultSpillMaxBytes
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = defaultSpillRetryInterval
+	}
+	if opts.FileMode == 0 {
+		opts.FileMode = defaultFileMode
+	}
+	queue, err := openDiskQueue(opts.FS, dir, opts.MaxBytes, opts.FileMode)
+	if err != nil {
+		return nil, err
+	}
+	s := &SpillSink{
+		sink:  sink,
+		opts:  opts,
+		queue: queue,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// WriteEntry writes the entry to the wrapped sink, or spills it to disk if the sink fails or
+// earlier entries are still queued. An error is only returned if the entry cannot be spilled
+func (s *SpillSink) WriteEntry(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queue.cursor() == s.queue.tail() && s.sink.WriteEntry(e) == nil {
+		return nil
+	}
+	dropped, _, err := s.queue.push(append(JSONEncoder{}.AppendEntry(nil, e), '\n'), false)
+	s.dropped.Add(uint64(dropped))
+	return err
+}
+
+// Dropped returns the number of queued entries dropped because the queue reached MaxBytes
+func (s *SpillSink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Expired returns the number of queued entries discarded because they were older than MaxAge
+func (s *SpillSink) Expired() uint64 {
+	return s.expired.Load()
+}
+
+// Malformed returns the number of queued entries discarded because they could not be parsed
+func (s *SpillSink) Malformed() uint64 {
+	return s.malformed.Load()
+}
+
+// Queued returns the size in bytes of the queue on disk
+func (s *SpillSink) Queued() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queue.size()
+}
+
+// QueueStats returns the number of entries queued, the age of the oldest and the number dropped
+func (s *SpillSink) QueueStats() QueueStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := QueueStats{Depth: int64(s.queue.tail() - s.queue.cursor()), Dropped: s.dropped.Load()}
+	if stats.Depth == 0 {
+		return stats
+	}
+	if record, _, _, err := s.queue.peek(1); err == nil {
+		if e, err := ParseEntry(string(record)); err == nil {
+			stats.OldestAge = time.Since(e.Time)
+		}
+	}
+	return stats
+}
+
+// Drain replays queued entries until the queue is empty or ctx is done, stopping at the first
+// entry the wrapped sink fails to take so that Close does not wait on a sink that is down.
+// Entries still queued are kept on disk for the next SpillSink, so none are counted as dropped
+func (s *SpillSink) Drain(ctx context.Context) (flushed, dropped int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flushed, err = s.replay(ctx)
+	return flushed, 0, err
+}
+
+// Close stops replaying in the background, closes the queue and closes the wrapped sink if
+// it is an io.Closer
+func (s *SpillSink) Close() error {
+	close(s.stop)
+	<-s.done
+	s.mu.Lock()
+	err := s.queue.close()
+	s.mu.Unlock()
+	if c, ok := s.sink.(io.Closer); ok {
+		if cErr := c.Close(); err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+func (s *SpillSink) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.opts.RetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			s.replay(context.Background())
+			s.mu.Unlock()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// replay writes queued entries to the sink in order until it fails, ctx is done or the queue
+// is empty, returning the number written. Each entry is committed once it is written, so the
+// position reached survives a restart. It is called with the lock held
+func (s *SpillSink) replay(ctx context.Context) (int, error) {
+	replayed := 0
+	for s.queue.cursor() < s.queue.tail() {
+		if err := ctx.Err(); err != nil {
+			return replayed, err
+		}
+		record, _, end, err := s.queue.peek(1)
+		if err != nil {
+			return replayed, err
+		}
+		e, err := ParseEntry(string(record))
+		switch {
+		case err != nil:
+			s.malformed.Add(1)
+		case s.opts.MaxAge > 0 && time.Since(e.Time) > s.opts.MaxAge:
+			s.expired.Add(1)
+		default:
+			if err = s.sink.WriteEntry(e); err != nil {
+				return replayed, err
+			}
+			replayed++
+		}
+		if err = s.queue.commit(end); err != nil {
+			return replayed, err
+		}
+	}
+	return replayed, nil
+}
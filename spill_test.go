@@ -0,0 +1,212 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// downSink records entries while up and fails while down
+type downSink struct {
+	mu      sync.Mutex
+	down    bool
+	entries []Entry
+}
+
+func (s *downSink) WriteEntry(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.down {
+		return errors.New("sink is down")
+	}
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+func (s *downSink) setDown(down bool) {
+	s.mu.Lock()
+	s.down = down
+	s.mu.Unlock()
+}
+
+func (s *downSink) messages() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := make([]string, 0, len(s.entries))
+	for _, e := range s.entries {
+		messages = append(messages, e.Message)
+	}
+	return strings.Join(messages, ",")
+}
+
+func TestSpillSink(t *testing.T) {
+	dir := t.TempDir()
+	remote := &downSink{down: true}
+	s, err := NewSpillSink(remote, dir, SpillOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []string{"one", "two\nlines"} {
+		if err = s.WriteEntry(Entry{Time: time.Now(), Level: ERROR, Message: m, Attrs: []Attr{Int("n", 1)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.Queued() == 0 {
+		t.Error("expected the entries to be queued on disk")
+	}
+	s.Close()
+	// a new sink on the same directory resumes the queue
+	s, err = NewSpillSink(remote, dir, SpillOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	remote.setDown(false)
+	// entries written while the queue is not empty join it rather than being replayed inline
+	if err = s.WriteEntry(Entry{Time: time.Now(), Level: ERROR, Message: "three"}); err != nil {
+		t.Fatal(err)
+	}
+	if m := remote.messages(); m != "" {
+		t.Errorf("expected nothing to be replayed by WriteEntry, got %q", m)
+	}
+	if _, _, err = s.Drain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if m := remote.messages(); m != "one,two\nlines,three" {
+		t.Errorf("expected the queue to be replayed in order, got %q", m)
+	}
+	if v, _ := remote.entries[0].Field("n"); fmt.Sprint(v) != "1" {
+		t.Errorf("expected attributes to be replayed, got %v", v)
+	}
+	if s.Queued() != 0 {
+		t.Errorf("expected the queue to be empty, got %d bytes", s.Queued())
+	}
+}
+
+func TestSpillSinkExpiry(t *testing.T) {
+	remote := &downSink{down: true}
+	s, err := NewSpillSink(remote, t.TempDir(), SpillOptions{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.WriteEntry(Entry{Time: time.Now().Add(-2 * time.Hour), Level: ERROR, Message: "stale"})
+	s.WriteEntry(Entry{Time: time.Now(), Level: ERROR, Message: "fresh"})
+	remote.setDown(false)
+	if flushed, _, err := s.Drain(context.Background()); flushed != 1 || err != nil || s.Expired() != 1 {
+		t.Errorf("expected the stale entry to expire, got %d flushed, %d expired and %v", flushed, s.Expired(), err)
+	}
+	if m := remote.messages(); m != "fresh" {
+		t.Errorf("unexpected replayed entries %q", m)
+	}
+}
+
+func TestSpillSinkCap(t *testing.T) {
+	remote := &downSink{down: true}
+	s, err := NewSpillSink(remote, t.TempDir(), SpillOptions{MaxBytes: queueSegmentSize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	large := strings.Repeat("x", queueSegmentSize/2)
+	for i := 0; i < 4; i++ {
+		s.WriteEntry(Entry{Time: time.Now(), Level: ERROR, Message: large})
+	}
+	if s.Dropped() == 0 {
+		t.Error("expected the oldest entries to be dropped beyond MaxBytes")
+	}
+	remote.setDown(false)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	flushed, _, err := s.Drain(ctx)
+	if err != nil || flushed != 2 || s.Dropped() != 2 {
+		t.Errorf("expected the remaining entries to be replayed, got %d flushed, %d dropped and %v", flushed, s.Dropped(), err)
+	}
+}
+
+func TestSpillSinkDrainDown(t *testing.T) {
+	dir := t.TempDir()
+	remote := &downSink{down: true}
+	s, err := NewSpillSink(remote, dir, SpillOptions{FileMode: 0600})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.WriteEntry(Entry{Time: time.Now(), Level: ERROR, Message: "queued"})
+	if flushed, _, err := s.Drain(context.Background()); flushed != 0 || err == nil {
+		t.Errorf("expected Drain to stop at the failed replay, got %d flushed and %v", flushed, err)
+	}
+	if s.Queued() == 0 {
+		t.Error("expected the entry to be kept on disk")
+	}
+	segments, _ := filepath.Glob(filepath.Join(dir, "*"+queueSuffix))
+	if info, err := os.Stat(segments[0]); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected the segment to have the configured mode, got %v", info.Mode())
+	}
+	s.Close()
+	f, _ := os.OpenFile(segments[0], os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"time":"2026-01-01T00:00:00Z","level":` + "\n")
+	f.Close()
+	if s, err = NewSpillSink(remote, dir, SpillOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	remote.setDown(false)
+	if flushed, _, err := s.Drain(context.Background()); flushed != 1 || err != nil || s.Malformed() != 1 {
+		t.Errorf("expected the malformed record to be counted, got %d flushed, %d malformed and %v", flushed, s.Malformed(), err)
+	}
+}
+
+// failAfterSink takes n entries and then fails
+type failAfterSink struct {
+	downSink
+	n int
+}
+
+func (s *failAfterSink) WriteEntry(e Entry) error {
+	if s.n == 0 {
+		return errors.New("sink is down")
+	}
+	s.n--
+	return s.downSink.WriteEntry(e)
+}
+
+func TestSpillSinkResume(t *testing.T) {
+	fs := NewMemFS()
+	remote := &failAfterSink{}
+	s, err := NewSpillSink(remote, "/spill", SpillOptions{FS: fs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []string{"one", "two", "three"} {
+		s.WriteEntry(Entry{Time: time.Now(), Level: ERROR, Message: m})
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err = s.Drain(ctx); err != context.Canceled {
+		t.Errorf("expected Drain to stop when its context is done, got %v", err)
+	}
+	remote.n = 1
+	if flushed, _, _ := s.Drain(context.Background()); flushed != 1 {
+		t.Errorf("expected one entry to be replayed, got %d", flushed)
+	}
+	s.Close()
+	if len(fs.Names()) == 0 {
+		t.Fatal("expected the queue to be kept on the FS given")
+	}
+	// the position replayed to is kept, so nothing is sent twice after a restart
+	remote.n = 2
+	if s, err = NewSpillSink(remote, "/spill", SpillOptions{FS: fs}); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.Drain(context.Background())
+	if m := remote.messages(); m != "one,two,three" {
+		t.Errorf("expected each entry to be replayed once, got %q", m)
+	}
+}
@@ -0,0 +1,134 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultBackpressureInterval is how often queues are checked against the backpressure
+// thresholds when no interval is given
+const defaultBackpressureInterval = 10 * time.Second
+
+// QueueStats reports the backlog of a queue of entries waiting to be written
+type QueueStats struct {
+	Depth     int64         // entries waiting
+	OldestAge time.Duration // how long the oldest waiting entry has waited
+	Dropped   uint64        // entries dropped because the queue was full
+	// DroppedErrors counts the entries dropped that were ERROR or more severe, by sinks that
+	// keep them when others are dropped, such as HTTPSink
+	DroppedErrors uint64
+}
+
+// QueueStatter is implemented by sinks that queue entries, such as SpillSink, so that their
+// backlog is reported by Stats
+type QueueStatter interface {
+	QueueStats() QueueStats
+}
+
+// Stats reports the state of a log's queues and, with WithLatencyStats, how long writes take
+type Stats struct {
+	Async QueueStats   // the queue of WithAsync
+	Sinks []QueueStats // one for each sink implementing QueueStatter, in the order they were added
+	Write LatencyStats // delivering an entry to the sinks and the file
+	Flush LatencyStats // syncing the file to disk; see WithSyncInterval
+}
+
+// Stats returns the backlog of the log's async queue and queueing sinks, and the latency of
+// writes and flushes
+func (l *Log) Stats() Stats {
+	var stats Stats
+	if l.async != nil {
+		stats.Async = l.async.stats()
+	}
+	if l.latency != nil {
+		stats.Write = l.latency.write.stats()
+		stats.Flush = l.latency.flush.stats()
+	}
+	for _, s := range l.sinks {
+		if q, ok := s.(QueueStatter); ok {
+			stats.Sinks = append(stats.Sinks, q.QueueStats())
+		}
+	}
+	return stats
+}
+
+type backpressure struct {
+	depth    int64
+	age      time.Duration
+	interval time.Duration
+	mu       sync.Mutex
+	warned   map[string]bool // queues over a threshold when last checked
+}
+
+// backpressureLimiter is implemented by queueing sinks with their own name and thresholds,
+// such as a Shipper, which replace the log's where they are set
+type backpressureLimiter interface {
+	backpressureLimits() (name string, depth int64, age time.Duration)
+}
+
+// queueLimits are a queue's stats and the thresholds they are checked against
+type queueLimits struct {
+	stats QueueStats
+	depth int64
+	age   time.Duration
+}
+
+func (q queueLimits) exceeded() bool {
+	return (q.depth > 0 && q.stats.Depth >= q.depth) || (q.age > 0 && q.stats.OldestAge >= q.age)
+}
+
+// watchBackpressure checks the log's queues against the thresholds until the log is closed
+func (l *Log) watchBackpressure() {
+	ticker := time.NewTicker(l.backpressure.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-l.done:
+			return
+		}
+		l.checkBackpressure()
+	}
+}
+
+// checkBackpressure writes a warning for each queue that has crossed a threshold since it
+// was last checked
+func (l *Log) checkBackpressure() {
+	b := l.backpressure
+	queues := map[string]queueLimits{}
+	if l.async != nil {
+		queues["async"] = queueLimits{l.async.stats(), b.depth, b.age}
+	}
+	i := 0
+	for _, s := range l.sinks {
+		q, ok := s.(QueueStatter)
+		if !ok {
+			continue
+		}
+		i++
+		name, limits := fmt.Sprintf("sink %d", i), queueLimits{q.QueueStats(), b.depth, b.age}
+		if bl, ok := s.(backpressureLimiter); ok {
+			var depth int64
+			var age time.Duration
+			if name, depth, age = bl.backpressureLimits(); depth > 0 {
+				limits.depth = depth
+			}
+			if age > 0 {
+				limits.age = age
+			}
+		}
+		queues[name] = limits
+	}
+	for name, q := range queues {
+		exceeded := q.exceeded()
+		b.mu.Lock()
+		warn := exceeded && !b.warned[name]
+		b.warned[name] = exceeded
+		b.mu.Unlock()
+		if warn {
+			l.With(String("queue", name), Int64("depth", q.stats.Depth), Duration("oldest", q.stats.OldestAge)).
+				Write("write queue backlog exceeds threshold", WARNING)
+		}
+	}
+}
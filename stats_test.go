@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// blockingSink blocks every write until released
+type blockingSink struct {
+	release chan struct{}
+}
+
+func (s *blockingSink) WriteEntry(e Entry) error {
+	<-s.release
+	return nil
+}
+
+func TestStats(t *testing.T) {
+	blocked := &blockingSink{release: make(chan struct{})}
+	spill, err := NewSpillSink(&downSink{down: true}, t.TempDir(), SpillOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := NewMemFS()
+	sl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(fs), WithSink(blocked), WithSink(spill),
+		WithAsync(10, DropNewest), WithBackpressureWarning(3, 0, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sl.Info("one")
+	sl.Info("two")
+	time.Sleep(10 * time.Millisecond)
+	stats := sl.Stats()
+	if stats.Async.Depth != 3 || stats.Async.OldestAge < 10*time.Millisecond {
+		t.Errorf("expected 3 entries waiting for at least 10ms, got %+v", stats.Async)
+	}
+	if len(stats.Sinks) != 1 || stats.Sinks[0].Depth != 0 {
+		t.Errorf("expected the spill sink's stats, got %+v", stats.Sinks)
+	}
+	sl.checkBackpressure()
+	sl.checkBackpressure()
+	close(blocked.release)
+	sl.Close()
+	b, _ := sl.readFile("/logs/app.log")
+	if strings.Count(string(b), "write queue backlog exceeds threshold queue=async depth=3") != 1 {
+		t.Errorf("expected a single backpressure warning, got %q", string(b))
+	}
+	if stats = sl.Stats(); stats.Async.Depth != 0 || stats.Sinks[0].Depth != 4 || stats.Sinks[0].OldestAge <= 0 {
+		t.Errorf("expected the queue to be empty and the entries spilled, got %+v", stats)
+	}
+}
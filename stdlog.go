@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// The Print, Fatal and Panic families match the method set of the standard library's
+// *log.Logger, so that a *Log can be used where code expects it. Write errors go to the
+// error handler
+
+func (l *Log) Print(v ...interface{}) {
+	l.print(fmt.Sprint(v...))
+}
+
+func (l *Log) Printf(format string, v ...interface{}) {
+	l.print(fmt.Sprintf(format, v...))
+}
+
+func (l *Log) Println(v ...interface{}) {
+	l.print(sprintln(v...))
+}
+
+// Fatal writes the message at FATAL, closes the log and exits with status 1
+func (l *Log) Fatal(v ...interface{}) {
+	l.fatal(fmt.Sprint(v...))
+}
+
+func (l *Log) Fatalf(format string, v ...interface{}) {
+	l.fatal(fmt.Sprintf(format, v...))
+}
+
+func (l *Log) Fatalln(v ...interface{}) {
+	l.fatal(sprintln(v...))
+}
+
+// Panic writes the message at ERROR, then panics with it
+func (l *Log) Panic(v ...interface{}) {
+	l.panic(fmt.Sprint(v...))
+}
+
+func (l *Log) Panicf(format string, v ...interface{}) {
+	l.panic(fmt.Sprintf(format, v...))
+}
+
+func (l *Log) Panicln(v ...interface{}) {
+	l.panic(sprintln(v...))
+}
+
+func (l *Log) print(message string) {
+	level := l.printLevel
+	if level == "" {
+		level = INFO
+	}
+	l.Chain().write(message, level)
+}
+
+func (l *Log) fatal(message string) {
+	l.writeFatal(message)
+	os.Exit(1)
+}
+
+func (l *Log) panic(message string) {
+	l.Chain().write(message, ERROR)
+	panic(message)
+}
+
+// sprintln formats as fmt.Sprintln without the trailing newline
+func sprintln(v ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(v...), "\n")
+}
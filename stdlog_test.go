@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+// stdLogger is the print and panic method set of the standard library's *log.Logger
+type stdLogger interface {
+	Print(v ...interface{})
+	Printf(format string, v ...interface{})
+	Println(v ...interface{})
+	Panic(v ...interface{})
+	Panicf(format string, v ...interface{})
+	Panicln(v ...interface{})
+	Fatal(v ...interface{})
+	Fatalf(format string, v ...interface{})
+	Fatalln(v ...interface{})
+}
+
+var _ stdLogger = (*Log)(nil)
+
+func TestPrint(t *testing.T) {
+	c := NewCapture()
+	c.Print("jobs:", 3)
+	c.Printf("job %d done", 1)
+	c.Println("jobs", "remaining", 2)
+	for _, message := range []string{"jobs:3", "job 1 done", "jobs remaining 2"} {
+		if !c.HasEntry(INFO, message) {
+			t.Errorf("expected an info entry '%s', got %v", message, c.Entries())
+		}
+	}
+	w := NewCapture(WithPrintLevel("warning"))
+	w.Print("deprecated")
+	if !w.HasEntry(WARNING, "deprecated") {
+		t.Errorf("expected print to use the configured level, got %v", w.Entries())
+	}
+}
+
+func TestPanic(t *testing.T) {
+	c := NewCapture()
+	defer func() {
+		if r := recover(); r != "bad state 7" {
+			t.Errorf("expected a panic with the message, got %v", r)
+		}
+		if !c.HasEntry(ERROR, "bad state 7") {
+			t.Error("expected the panic message to be logged")
+		}
+	}()
+	c.Panicf("bad state %d", 7)
+}
+
+func TestWriteFatal(t *testing.T) {
+	var sink captureSink
+	fl := NewTestLog(t, WithSink(&sink), WithCrashDump(), WithAsync(16, DropNewest))
+	fl.writeFatal("out of disk")
+	if !fl.isClosed() {
+		t.Error("expected the log to be closed after a fatal entry")
+	}
+	if len(sink.entries) != 3 {
+		t.Fatalf("expected the queued fatal and crash dump entries to be flushed, got %v", sink.entries)
+	}
+	dump := sink.entries[2]
+	if dump.Level != "FATAL" || !strings.HasPrefix(dump.Message, "crash dump\ngoroutine ") {
+		t.Errorf("expected a crash dump entry, got %s", dump)
+	}
+	if v, ok := dump.Field("heap_alloc"); !ok || v.(int64) <= 0 {
+		t.Errorf("expected memory statistics, got %v", v)
+	}
+}
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+)
+
+// subscriptionBuffer is the number of entries a subscriber can fall behind by before entries
+// are dropped for it
+const subscriptionBuffer = 64
+
+// subscriptions are the channels of Subscribe, shared with child logs
+type subscriptions struct {
+	mu   sync.RWMutex
+	subs map[*subscription]struct{}
+}
+
+type subscription struct {
+	ch     chan Entry
+	levels map[string]bool // nil for every level
+}
+
+// Subscribe returns a channel receiving the entries written at the given levels (case
+// insensitive), or at every level if none are given, so other parts of the process can react
+// to them, such as a status bar showing the last error. Entries are sent after they are
+// written, without waiting: a subscriber more than 64 entries behind misses entries rather
+// than slowing the log down. Call the returned function to unsubscribe, which closes the
+// channel. Subscriptions are shared with child logs
+func (l *Log) Subscribe(levels ...string) (<-chan Entry, func()) {
+	s := &subscription{ch: make(chan Entry, subscriptionBuffer)}
+	if len(levels) > 0 {
+		s.levels = make(map[string]bool, len(levels))
+		for _, level := range levels {
+			s.levels[strings.ToUpper(level)] = true
+		}
+	}
+	subs := l.subs
+	subs.mu.Lock()
+	if subs.subs == nil {
+		subs.subs = make(map[*subscription]struct{})
+	}
+	subs.subs[s] = struct{}{}
+	subs.mu.Unlock()
+	var once sync.Once
+	return s.ch, func() {
+		once.Do(func() {
+			subs.mu.Lock()
+			delete(subs.subs, s)
+			subs.mu.Unlock()
+			close(s.ch)
+		})
+	}
+}
+
+// publish sends an entry written to the subscribers of its level
+func (subs *subscriptions) publish(e Entry) {
+	if subs == nil {
+		return
+	}
+	subs.mu.RLock()
+	defer subs.mu.RUnlock()
+	for s := range subs.subs {
+		if s.levels != nil && !s.levels[e.Level] {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+		}
+	}
+}
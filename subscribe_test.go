@@ -0,0 +1,38 @@
+package logging
+
+import "testing"
+
+func TestSubscribe(t *testing.T) {
+	sl, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sl.Close()
+	errs, unsubscribe := sl.Subscribe("error")
+	all, unsubscribeAll := sl.Subscribe()
+	defer unsubscribeAll()
+	sl.Info("started")
+	sl.With(String("component", "db")).Error("connection lost")
+	e := <-errs
+	if e.Message != "connection lost" || field(e, "component") != "db" {
+		t.Errorf("expected the error of the child log, got %s", e)
+	}
+	if len(errs) != 0 || len(all) != 2 {
+		t.Errorf("expected only errs to be received, got %d and %d", len(errs), len(all))
+	}
+
+	unsubscribe()
+	unsubscribe()
+	sl.Error("again")
+	if _, ok := <-errs; ok {
+		t.Error("expected the channel to be closed")
+	}
+
+	// a subscriber that does not keep up misses entries rather than blocking the log
+	for i := 0; i < subscriptionBuffer+10; i++ {
+		sl.Info("filling")
+	}
+	if len(all) != subscriptionBuffer {
+		t.Errorf("expected the channel to be full, got %d", len(all))
+	}
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"io"
+	"sort"
+	"time"
+)
+
+// SizedRecord is a record and its size in bytes
+type SizedRecord struct {
+	Entry Entry
+	Size  int
+}
+
+// RateBucket is the number of entries written in the interval starting at Start
+type RateBucket struct {
+	Start time.Time
+	Count int
+}
+
+// Summary describes the contents of a log file; see Summarize
+type Summary struct {
+	Entries     int
+	Unparsed    int // records ParseEntry could not read
+	Levels      map[string]int
+	First, Last time.Time
+	Largest     []SizedRecord // largest first
+	Rate        []RateBucket  // oldest first, including empty intervals
+}
+
+// Span returns the time covered by the entries
+func (s Summary) Span() time.Duration {
+	return s.Last.Sub(s.First)
+}
+
+// Summarize reads records from r and counts them by level, finds the time span covered and
+// the largest records, and counts the entries written in each interval
+func Summarize(r io.Reader, interval time.Duration, largest int) (Summary, error) {
+	s := Summary{Levels: make(map[string]int), Largest: make([]SizedRecord, 0, largest)}
+	counts := make(map[time.Time]int)
+	scanner := newRecordScanner(r)
+	for {
+		record, err := scanner.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return s, err
+		}
+		e, err := ParseEntry(record)
+		if err != nil {
+			s.Unparsed++
+			continue
+		}
+		s.Entries++
+		s.Levels[e.Level]++
+		if s.First.IsZero() || e.Time.Before(s.First) {
+			s.First = e.Time
+		}
+		if e.Time.After(s.Last) {
+			s.Last = e.Time
+		}
+		if interval > 0 {
+			counts[e.Time.Truncate(interval)]++
+		}
+		s.Largest = keepLargest(s.Largest, SizedRecord{Entry: e, Size: len(record)}, largest)
+	}
+	if interval > 0 && s.Entries > 0 {
+		for start := s.First.Truncate(interval); !start.After(s.Last); start = start.Add(interval) {
+			s.Rate = append(s.Rate, RateBucket{Start: start, Count: counts[start]})
+		}
+	}
+	return s, nil
+}
+
+// keepLargest adds r to records, largest first, keeping at most n
+func keepLargest(records []SizedRecord, r SizedRecord, n int) []SizedRecord {
+	if n <= 0 || (len(records) == n && records[n-1].Size >= r.Size) {
+		return records
+	}
+	i := sort.Search(len(records), func(i int) bool { return records[i].Size < r.Size })
+	if len(records) < n {
+		records = append(records, SizedRecord{})
+	}
+	copy(records[i+1:], records[i:])
+	records[i] = r
+	return records
+}
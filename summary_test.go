@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	records := strings.Join([]string{
+		"[2024-01-02T00:00:00Z] [TEST.INFO] started",
+		"[2024-01-02T00:10:00Z] [TEST.ERROR] a much longer failure message",
+		"not a record",
+		"[2024-01-02T00:20:00Z] [TEST.INFO] working",
+		"[2024-01-02T02:05:00Z] [TEST.WARNING] slow",
+	}, "\n") + "\n"
+	s, err := Summarize(strings.NewReader(records), time.Hour, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Entries != 4 || s.Levels[INFO] != 2 || s.Levels[ERROR] != 1 || s.Levels[WARNING] != 1 {
+		t.Errorf("unexpected counts %v %v", s.Entries, s.Levels)
+	}
+	if s.Span() != 2*time.Hour+5*time.Minute {
+		t.Errorf("unexpected span %s", s.Span())
+	}
+	if len(s.Largest) != 2 || s.Largest[0].Entry.Level != ERROR || s.Largest[1].Entry.Message != "started" {
+		t.Errorf("unexpected largest records %v", s.Largest)
+	}
+	if len(s.Rate) != 3 || s.Rate[0].Count != 3 || s.Rate[1].Count != 0 || s.Rate[2].Count != 1 {
+		t.Errorf("unexpected rate %v", s.Rate)
+	}
+}
@@ -0,0 +1,162 @@
+package logging
+
+import (
+	"crypto/tls"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// syslogTimeout bounds connecting to the syslog server and writing a message
+	syslogTimeout = 5 * time.Second
+	// maxRFC3164Message is the largest message RFC 3164 allows, in bytes
+	maxRFC3164Message = 1024
+	// defaultSyslogFacility is the user-level facility
+	defaultSyslogFacility = 1
+)
+
+// SyslogFormat is the message format written by a SyslogSink
+type SyslogFormat int
+
+const (
+	// RFC5424 is the current syslog protocol. Over TCP messages are framed by octet counting
+	RFC5424 SyslogFormat = iota
+	// RFC3164 is the legacy BSD syslog format, still the only one accepted by many
+	// appliances. Messages are cut to 1024 bytes, line breaks are escaped and over TCP
+	// messages are terminated by a newline
+	RFC3164
+)
+
+// SyslogSink sends each entry to a syslog server, with the priority derived from the level's
+// severity; custom levels are sent as NOTICE. The connection is made on the first entry and
+// remade on the next entry after a write fails
+type SyslogSink struct {
+	// Facility is the syslog facility, 1 (user-level) unless set before the first entry
+	Facility int
+	// TLS, if set before the first entry, secures the connection to a server over "tcp"
+	// as RFC 5425 describes; see TLSOptions
+	TLS *tls.Config
+
+	mu       sync.Mutex
+	network  string
+	addr     string
+	tag      string
+	format   SyslogFormat
+	hostname string
+	conn     net.Conn
+}
+
+// NewSyslogSink returns a sink sending entries to the syslog server at addr over network,
+// "udp" or "tcp", in the given format, with tag as the application name
+func NewSyslogSink(network, addr, tag string, format SyslogFormat) *SyslogSink {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "localhost"
+	}
+	return &SyslogSink{Facility: defaultSyslogFacility, network: network, addr: addr, tag: tag, format: format, hostname: hostname}
+}
+
+func (s *SyslogSink) WriteEntry(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.connect(); err != nil {
+		return err
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	*buf = s.appendMessage((*buf)[:0], e)
+	s.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+	if _, err := s.conn.Write(*buf); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+// connect connects to the server if not already connected. It is called with the lock held
+func (s *SyslogSink) connect() error {
+	if s.conn != nil {
+		return nil
+	}
+	dialer := &net.Dialer{Timeout: syslogTimeout}
+	var conn net.Conn
+	var err error
+	if s.TLS != nil {
+		conn, err = tls.DialWithDialer(dialer, s.network, s.addr, s.TLS)
+	} else {
+		conn, err = dialer.Dial(s.network, s.addr)
+	}
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+// appendMessage appends the entry as a syslog message framed for the sink's network
+func (s *SyslogSink) appendMessage(dst []byte, e Entry) []byte {
+	severity, ok := Severity(e.Level)
+	if !ok {
+		severity = syslogSeverities[NOTICE]
+	}
+	stream := s.network != "udp" && s.network != "udp4" && s.network != "udp6" && s.network != "unixgram"
+	msg := make([]byte, 0, 256)
+	msg = append(msg, '<')
+	msg = strconv.AppendInt(msg, int64(s.Facility*8+severity), 10)
+	msg = append(msg, '>')
+	if s.format == RFC3164 {
+		msg = e.Time.Local().AppendFormat(msg, time.Stamp)
+		msg = append(msg, ' ')
+		msg = append(msg, s.hostname...)
+		msg = append(msg, ' ')
+		msg = append(msg, s.tag...)
+		msg = append(msg, '[')
+		msg = strconv.AppendInt(msg, int64(os.Getpid()), 10)
+		msg = append(msg, "]: "...)
+		e.Message = escapeLineBreaks(e.Message)
+		msg = appendMessage(msg, e)
+		if len(msg) > maxRFC3164Message {
+			msg = msg[:maxRFC3164Message]
+		}
+		if stream {
+			msg = append(msg, '\n')
+		}
+		return append(dst, msg...)
+	}
+	msg = append(msg, "1 "...)
+	msg = e.Time.UTC().AppendFormat(msg, "2006-01-02T15:04:05.000000Z")
+	msg = append(msg, ' ')
+	msg = append(msg, s.hostname...)
+	msg = append(msg, ' ')
+	if s.tag == "" {
+		msg = append(msg, '-')
+	}
+	msg = append(msg, s.tag...)
+	msg = append(msg, ' ')
+	msg = strconv.AppendInt(msg, int64(os.Getpid()), 10)
+	msg = append(msg, ' ')
+	msg = append(msg, e.Level...)
+	msg = append(msg, " - "...)
+	msg = appendMessage(msg, e)
+	if stream {
+		dst = strconv.AppendInt(dst, int64(len(msg)), 10)
+		dst = append(dst, ' ')
+	}
+	return append(dst, msg...)
+}
+
+// Close closes the connection to the syslog server, if any
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
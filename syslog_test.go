@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogSinkRFC5424(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	sink := NewSyslogSink("udp", conn.LocalAddr().String(), "app", RFC5424)
+	defer sink.Close()
+	e := Entry{Time: time.Date(2024, 1, 2, 15, 4, 5, 123456000, time.UTC), Env: "prod", Level: WARNING, Message: "disk low", Attrs: []Attr{Int("free", 3)}}
+	if err = sink.WriteEntry(e); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 2048)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := fmt.Sprintf("<12>1 2024-01-02T15:04:05.123456Z %s app %d WARNING - disk low free=3", sink.hostname, os.Getpid())
+	if string(buf[:n]) != expected {
+		t.Errorf("expected %q, got %q", expected, buf[:n])
+	}
+}
+
+func TestSyslogSinkRFC3164(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	lines := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	sink := NewSyslogSink("tcp", listener.Addr().String(), "app", RFC3164)
+	sink.Facility = 16
+	defer sink.Close()
+	e := Entry{Time: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), Level: ERROR, Message: "failed\n\tat main.go:3"}
+	if err = sink.WriteEntry(e); err != nil {
+		t.Fatal(err)
+	}
+	e.Message = strings.Repeat("x", 2000)
+	if err = sink.WriteEntry(e); err != nil {
+		t.Fatal(err)
+	}
+	prefix := fmt.Sprintf("<131>%s %s app[%d]: ", e.Time.Local().Format(time.Stamp), sink.hostname, os.Getpid())
+	select {
+	case line := <-lines:
+		if line != prefix+"failed\\n\tat main.go:3" {
+			t.Errorf("expected %q, got %q", prefix+"failed\\n\tat main.go:3", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a message")
+	}
+	select {
+	case line := <-lines:
+		if len(line) != maxRFC3164Message || !strings.HasPrefix(line, prefix+"xxx") {
+			t.Errorf("expected a message cut to %d bytes, got %d", maxRFC3164Message, len(line))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a second message")
+	}
+}
@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"io"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// templateVars matches the parts of a message that usually vary between entries written by
+// the same line of code: quoted strings, UUIDs, hex and decimal numbers
+var templateVars = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|\b[0-9a-fA-F]{8}(?:-[0-9a-fA-F]{4}){3}-[0-9a-fA-F]{12}\b|\b0x[0-9a-fA-F]+\b|\d+(?:\.\d+)?`)
+
+// MessageCount is the number of entries whose message matched Template
+type MessageCount struct {
+	Template string
+	Count    int
+}
+
+// MessageTemplate returns message with its variable parts replaced by *, so that
+// "user 42 logged in" and "user 7 logged in" share the template "user * logged in"
+func MessageTemplate(message string) string {
+	return templateVars.ReplaceAllString(message, "*")
+}
+
+type templateCounts map[string]int
+
+func (c templateCounts) add(record string) {
+	e, err := ParseEntry(record)
+	if err != nil {
+		return
+	}
+	c[MessageTemplate(e.Message)]++
+}
+
+// top returns the n most frequent templates, most frequent first, or all of them when n is not positive
+func (c templateCounts) top(n int) []MessageCount {
+	result := make([]MessageCount, 0, len(c))
+	for template, count := range c {
+		result = append(result, MessageCount{Template: template, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Template < result[j].Template
+	})
+	if n > 0 && len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// TopMessages reads records from r and returns the n most frequent message templates (see
+// MessageTemplate) of the entries written from from up to but not including to, most
+// frequent first. A zero from or to leaves that end of the range open; n <= 0 returns every
+// template. Records that cannot be parsed are skipped
+func TopMessages(r io.Reader, from, to time.Time, n int) ([]MessageCount, error) {
+	counts := make(templateCounts)
+	scanner := newRecordScanner(r)
+	for {
+		record, err := scanner.next()
+		if err == io.EOF {
+			return counts.top(n), nil
+		}
+		if err != nil {
+			return counts.top(n), err
+		}
+		if !from.IsZero() || !to.IsZero() {
+			t, ok := recordTime([]byte(record))
+			if !ok || t.Before(from) || (!to.IsZero() && !t.Before(to)) {
+				continue
+			}
+		}
+		counts.add(record)
+	}
+}
+
+// TopMessages returns the n most frequent message templates in the log file between from and
+// to; see TopMessages and ReadRange
+func (l *Log) TopMessages(from, to time.Time, n int) ([]MessageCount, error) {
+	if to.IsZero() {
+		to = time.Unix(1<<62, 0)
+	}
+	records, err := l.ReadRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(templateCounts)
+	for _, record := range records {
+		counts.add(record)
+	}
+	return counts.top(n), nil
+}
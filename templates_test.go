@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessageTemplate(t *testing.T) {
+	cases := map[string]string{
+		"user 42 logged in":                                 "user * logged in",
+		`opened "/tmp/a.txt" in 1.5s`:                       "opened * in *s",
+		"request 123e4567-e89b-12d3-a456-426614174000 done": "request * done",
+		"pointer 0xc000010000":                              "pointer *",
+	}
+	for message, expected := range cases {
+		if template := MessageTemplate(message); template != expected {
+			t.Errorf("expected template of %q to be %q, got %q", message, expected, template)
+		}
+	}
+}
+
+func TestTopMessages(t *testing.T) {
+	records := strings.Join([]string{
+		"[2024-01-02T00:00:00Z] [TEST.INFO] user 1 logged in",
+		"[2024-01-02T00:01:00Z] [TEST.ERROR] connection refused",
+		"[2024-01-02T00:02:00Z] [TEST.INFO] user 2 logged in",
+		"[2024-01-02T00:03:00Z] [TEST.ERROR] connection refused",
+		"[2024-01-02T00:04:00Z] [TEST.INFO] user 3 logged in",
+		"[2024-01-02T00:05:00Z] [TEST.INFO] shutting down",
+	}, "\n")
+	top, err := TopMessages(strings.NewReader(records), time.Time{}, time.Time{}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 2 || top[0] != (MessageCount{"user * logged in", 3}) || top[1] != (MessageCount{"connection refused", 2}) {
+		t.Errorf("unexpected top messages %v", top)
+	}
+	from := time.Date(2024, 1, 2, 0, 1, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 2, 0, 4, 0, 0, time.UTC)
+	top, err = TopMessages(strings.NewReader(records), from, to, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 2 || top[0] != (MessageCount{"connection refused", 2}) || top[1] != (MessageCount{"user * logged in", 1}) {
+		t.Errorf("unexpected top messages in range %v", top)
+	}
+}
+
+func TestLogTopMessages(t *testing.T) {
+	tl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(NewMemFS()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		tl.Infof("retrying in %d seconds", i)
+	}
+	top, err := tl.TopMessages(time.Time{}, time.Time{}, 1)
+	if err != nil || len(top) != 1 || top[0] != (MessageCount{"retrying in * seconds", 3}) {
+		t.Errorf("expected the retry template, got %v and %v", top, err)
+	}
+}
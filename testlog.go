@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+type testSink struct {
+	t testing.TB
+}
+
+func (s testSink) WriteEntry(e Entry) error {
+	s.t.Log(e.String())
+	return nil
+}
+
+// NewTestLog returns a log writing to a file in the test's temporary directory, which is removed
+// when the test completes. Every entry is also passed to t.Log
+func NewTestLog(t testing.TB, opts ...Option) *Log {
+	t.Helper()
+	opts = append([]Option{WithSink(testSink{t: t})}, opts...)
+	l, err := NewLog(filepath.Join(t.TempDir(), "test.log"), "TEST", LEVEL_INFO, LEVEL_NONE, opts...)
+	if err != nil {
+		t.Fatalf("could not create test log: %s", err.Error())
+	}
+	return l
+}
@@ -0,0 +1,22 @@
+package logging
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewTestLog(t *testing.T) {
+	tl := NewTestLog(t)
+	tl.Warning("written to a temporary file")
+	if !strings.HasPrefix(tl.Path(), os.TempDir()) {
+		t.Errorf("expected test log to be in the temporary directory, got %s", tl.Path())
+	}
+	result, err := tl.GetLog(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 || !strings.Contains(result[0], "written to a temporary file") {
+		t.Errorf("expected test log to contain the warning, got %v", result)
+	}
+}
@@ -0,0 +1,19 @@
+package logging
+
+// Timed logs the start of an operation at DEBUG and returns a function logging its
+// completion with the elapsed time, at INFO or, above the WithSlowThreshold, WARNING:
+//
+//	done := l.Timed("rebuild index")
+//	defer done()
+func (l *Log) Timed(operation string) func() {
+	start := l.now()
+	l.Chain().Debug(operation + " started")
+	return func() {
+		elapsed := l.now().Sub(start)
+		level := INFO
+		if l.slow > 0 && elapsed > l.slow {
+			level = WARNING
+		}
+		l.With(Duration("elapsed", elapsed)).Chain().write(operation+" finished", level)
+	}
+}
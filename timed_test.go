@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimed(t *testing.T) {
+	c := NewCapture(WithNow(steppingClock(time.Second)), WithSlowThreshold(10*time.Second))
+	c.Timed("rebuild index")()
+	entries := c.Entries()
+	if len(entries) != 2 || entries[0].Level != DEBUG || entries[0].Message != "rebuild index started" {
+		t.Fatalf("expected a debug start entry, got %v", entries)
+	}
+	done := entries[1]
+	if done.Level != INFO || done.Message != "rebuild index finished" {
+		t.Errorf("expected an info completion entry, got %s", done)
+	}
+	if v, _ := done.Field("elapsed"); v.(time.Duration) <= 0 {
+		t.Errorf("expected a positive elapsed time, got %v", v)
+	}
+	c.Reset()
+	slow := c.Timed("compaction")
+	for i := 0; i < 10; i++ {
+		c.now()
+	}
+	slow()
+	if !c.HasEntry(WARNING, "compaction finished") {
+		t.Errorf("expected a slow operation to be logged at warning, got %v", c.Entries())
+	}
+}
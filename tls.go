@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+var errNoCertificates = errors.New("no certificates found in CA bundle")
+
+// TLSOptions configures TLS for the connections of network sinks, e.g. to collectors that
+// require mutual TLS
+type TLSOptions struct {
+	// CAFile is a PEM bundle of the certificate authorities trusted to sign the server's
+	// certificate, replacing the system's (default the system's)
+	CAFile string `json:"ca_file"`
+	// CertFile and KeyFile are the PEM client certificate and key presented to the server
+	// for mutual TLS (default none)
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+	// ServerName is checked against the server's certificate (default the host dialled)
+	ServerName string `json:"server_name"`
+}
+
+// Config loads the files of the options into a TLS configuration requiring TLS 1.2 or later
+func (o TLSOptions) Config() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: o.ServerName}
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: %w", o.CAFile, errNoCertificates)
+		}
+	}
+	if o.CertFile != "" || o.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// NewTLSClient returns an HTTP client making its connections with the TLS configuration and
+// through the proxy set in the environment, if any; see NewHTTPClient
+func NewTLSClient(cfg *tls.Config) *http.Client {
+	client, _ := NewHTTPClient(cfg, "")
+	return client
+}
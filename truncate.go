@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+)
+
+var errNegativeMessageLength = errors.New("maximum message length must not be negative")
+
+type truncation struct {
+	max int
+	dir string
+}
+
+// truncate shortens messages longer than the configured maximum, marking how many bytes were
+// cut and, with an overflow directory, where the full message was written
+func (l *Log) truncate(message string) string {
+	if l.truncation == nil || l.truncation.max <= 0 || len(message) <= l.truncation.max {
+		return message
+	}
+	cut := l.truncation.max
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	suffix := fmt.Sprintf("…[truncated %d bytes]", len(message)-cut)
+	if l.truncation.dir != "" {
+		name, err := l.writeOverflow(message)
+		if err != nil {
+			l.handleError(err)
+		} else {
+			suffix += " full=" + name
+		}
+	}
+	return message[:cut] + suffix
+}
+
+// writeOverflow writes a full message to a file in the overflow directory named by its hash
+func (l *Log) writeOverflow(message string) (string, error) {
+	sum := sha256.Sum256([]byte(message))
+	name := filepath.Join(l.truncation.dir, hex.EncodeToString(sum[:8])+".txt")
+	mode := l.fileMode
+	if mode == 0 {
+		mode = defaultFileMode
+	}
+	file, err := l.getFS().OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return "", err
+	}
+	_, err = file.Write([]byte(message))
+	if cErr := file.Close(); err == nil {
+		err = cErr
+	}
+	return name, err
+}
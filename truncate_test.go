@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaxMessageLength(t *testing.T) {
+	c := NewCapture(WithMaxMessageLength(10))
+	c.Info("short")
+	c.Info("response body: ünïcode")
+	entries := c.Entries()
+	if entries[0].Message != "short" {
+		t.Errorf("expected a short message to be kept, got %s", entries[0].Message)
+	}
+	if entries[1].Message != "response b…[truncated 14 bytes]" {
+		t.Errorf("expected the message to be truncated, got %s", entries[1].Message)
+	}
+	c.Reset()
+	c.Info("123456789ü")
+	if message := c.Entries()[0].Message; message != "123456789…[truncated 2 bytes]" {
+		t.Errorf("expected truncation on a character boundary, got %s", message)
+	}
+}
+
+func TestOverflowDir(t *testing.T) {
+	fs := NewMemFS()
+	full := strings.Repeat("payload ", 20)
+	c := NewCapture(WithFS(fs), WithMaxMessageLength(16), WithOverflowDir("overflow"))
+	c.Info(full)
+	message := c.Entries()[0].Message
+	i := strings.Index(message, " full=")
+	if i < 0 {
+		t.Fatalf("expected the overflow file to be named, got %s", message)
+	}
+	content, err := c.readFile(message[i+len(" full="):])
+	if err != nil || string(content) != full {
+		t.Errorf("expected the full message in the overflow file, got '%s' (%v)", content, err)
+	}
+}
+
+func TestMaxMessageLengthLimits(t *testing.T) {
+	c := NewCapture(WithOverflowDir(t.TempDir()))
+	c.Info("kept in full")
+	if message := c.Entries()[0].Message; message != "kept in full" {
+		t.Errorf("expected no limit without WithMaxMessageLength, got %s", message)
+	}
+	if _, err := NewLog("", "TEST", LEVEL_INFO, LEVEL_NONE, WithMaxMessageLength(-1)); err != errNegativeMessageLength {
+		t.Errorf("expected a negative length to be rejected, got %v", err)
+	}
+}
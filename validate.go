@@ -0,0 +1,209 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Checker is implemented by sinks that can check whether they are able to accept entries
+// without writing one, such as SyslogSink. Sinks wrapping another sink check it in turn
+type Checker interface {
+	Check() error
+}
+
+// ConfigError lists the problems found by ValidateConfig
+type ConfigError struct {
+	Problems []error
+}
+
+func (e *ConfigError) Error() string {
+	messages := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		messages[i] = p.Error()
+	}
+	return "invalid log configuration: " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns the problems, so errors.Is and errors.As find each of them
+func (e *ConfigError) Unwrap() []error {
+	return e.Problems
+}
+
+// ValidateConfig checks, as a dry run of NewLog with the same arguments, that the options
+// are valid, the log file can be created or appended to, the rotated file names are sane
+// and the sinks implementing Checker can be reached, for deploy pre-flight checks. No entry
+// is written; a missing log file is created and removed again to prove it can be, leaving
+// the directories made for it by WithCreateDirs, and the shippers built are closed again.
+// Every problem found is returned in a *ConfigError
+func ValidateConfig(path, env string, logLevel, reportLevel int, opts ...Option) error {
+	l := newLog(path, env, logLevel, reportLevel, opts)
+	var problems []error
+	if l.configErr != nil {
+		problems = append(problems, l.configErr)
+	}
+	if l.path != "" {
+		if err := l.checkPath(); err != nil {
+			problems = append(problems, err)
+		}
+		problems = append(problems, l.checkRotation()...)
+		if !readableEncoder(l.encoder) {
+			problems = append(problems, ErrUnreadableEncoder)
+		}
+	}
+	if l.budget != nil && l.budget.max <= 0 {
+		problems = append(problems, fmt.Errorf("disk budget must be positive, got %d", l.budget.max))
+	}
+	shippers, err := l.startShippers()
+	if err != nil {
+		problems = append(problems, err)
+	}
+	defer closeShippers(shippers)
+	for i, s := range l.sinks {
+		if c, ok := s.(Checker); ok {
+			if err := c.Check(); err != nil {
+				problems = append(problems, fmt.Errorf("sink %d (%T): %w", i+1, s, err))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &ConfigError{Problems: problems}
+	}
+	return nil
+}
+
+// checkPath checks the log file can be appended to, or created if it does not exist
+func (l *Log) checkPath() error {
+	info, err := l.getFS().Stat(l.path)
+	if err == nil {
+		if info.IsDir() {
+			return fmt.Errorf("log path %s is a directory", l.path)
+		}
+		file, err := l.getFS().OpenFile(l.path, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		return file.Close()
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+	dir := filepath.Dir(l.path)
+	if _, err = l.getFS().Stat(dir); os.IsNotExist(err) && l.dirMode == 0 {
+		return fmt.Errorf("log directory %s does not exist and WithCreateDirs is not set", dir)
+	}
+	if err = l.createLog(); err != nil {
+		return err
+	}
+	return l.getFS().Remove(l.path)
+}
+
+// checkRotation checks that rotated files are named apart from the log file and are found
+// again by the disk budget and retention
+func (l *Log) checkRotation() []error {
+	var problems []error
+	if l.naming != nil && l.naming.pattern != "" {
+		walkRotationPattern(l.naming.pattern, func(literal string, verb byte, width int) {
+			if verb == 0 && strings.HasPrefix(literal, "%") && literal != "%" {
+				problems = append(problems, fmt.Errorf("unknown verb %s in rotation pattern", literal))
+			}
+		})
+	}
+	name := l.rotatedName(l.now(), 1)
+	if name == l.path {
+		problems = append(problems, fmt.Errorf("rotated files would be named %s, the log file itself", name))
+	} else if glob, err := l.rotatedGlob(); err != nil {
+		problems = append(problems, err)
+	} else if ok, _ := filepath.Match(glob, name); !ok {
+		problems = append(problems, fmt.Errorf("rotated file %s would not be found again by the disk budget", name))
+	}
+	return problems
+}
+
+// Check connects to the syslog server if not already connected, closing the connection
+// again if it was only opened to check
+func (s *SyslogSink) Check() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		return nil
+	}
+	if err := s.connect(); err != nil {
+		return err
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// Check opens the named pipe if it is not open, failing with ErrNoReader if nothing reads it
+func (s *FIFOSink) Check() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file != nil {
+		return nil
+	}
+	file, err := openFIFO(s.path)
+	if err != nil {
+		return err
+	}
+	s.file = file
+	return nil
+}
+
+// Check checks the sink's file can be appended to or its directory written
+func (s *fileSink) Check() error {
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0)
+	if err == nil {
+		return file.Close()
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+	dir := filepath.Dir(s.path)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return nil
+}
+
+func (s *filterSink) Check() error { return checkSink(s.sink) }
+func (s *retrySink) Check() error  { return checkSink(s.sink) }
+
+// Check returns ErrCircuitOpen while the circuit is open, and checks the wrapped sink otherwise
+func (s *CircuitBreakerSink) Check() error {
+	if s.Stats().State == BreakerOpen {
+		return ErrCircuitOpen
+	}
+	return checkSink(s.sink)
+}
+
+// Check checks the queue directory is writable. The wrapped sink failing is not a problem
+// since entries are spilled until it recovers
+func (s *SpillSink) Check() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queue.check()
+}
+
+// Check succeeds if either the primary or the fallback sink passes its check
+func (s *fallbackSink) Check() error {
+	err := checkSink(s.primary)
+	if err == nil || checkSink(s.fallback) == nil {
+		return nil
+	}
+	return err
+}
+
+// checkSink checks a wrapped sink if it implements Checker
+func checkSink(s Sink) error {
+	if c, ok := s.(Checker); ok {
+		return c.Check()
+	}
+	return nil
+}
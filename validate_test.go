@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs", "app.log")
+	if err := ValidateConfig(path, "test", 3, 3); err == nil {
+		t.Error("expected a missing directory to be reported")
+	}
+	if err := ValidateConfig(path, "test", 3, 3, WithCreateDirs(0o755)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no log file to be left behind, got %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	err = ValidateConfig(dir, "test", 3, 3,
+		WithRateAnomalyAlert(0.5, nil),
+		WithRotationName("app-%q.log", false),
+		WithSink(NewSyslogSink("tcp", addr, "app", RFC5424)),
+	)
+	var config *ConfigError
+	if !errors.As(err, &config) {
+		t.Fatalf("expected a ConfigError, got %v", err)
+	}
+	if len(config.Problems) != 4 {
+		t.Errorf("expected 4 problems, got %d: %v", len(config.Problems), err)
+	}
+	if !errors.Is(err, errAnomalyFactor) {
+		t.Errorf("expected the option error to be reported, got %v", err)
+	}
+}
+
+func TestValidateConfigClosesConnections(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	sink := NewSyslogSink("tcp", listener.Addr().String(), "app", RFC5424)
+	if err = ValidateConfig("", "test", 3, 3, WithSink(sink)); err != nil {
+		t.Fatal(err)
+	}
+	if sink.conn != nil {
+		t.Error("expected the connection opened to check the sink to be closed")
+	}
+}
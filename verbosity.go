@@ -0,0 +1,71 @@
+package logging
+
+import "os"
+
+// verbosityOrder lists the levels from least to most verbose in the configured ordering
+func (l *Log) verbosityOrder() []Level {
+	if l.standardLevels {
+		return []Level{LevelNone, LevelError, LevelWarning, LevelInfo, LevelDebug}
+	}
+	return []Level{LevelNone, LevelError, LevelWarning, LevelDebug, LevelInfo}
+}
+
+// AdjustVerbosity moves the log level steps towards more verbose, or less when negative,
+// stopping at the ends of the scale. The change is logged and the new level returned
+func (l *Log) AdjustVerbosity(steps int) Level {
+	order := l.verbosityOrder()
+	l.levels.mu.Lock()
+	level := l.levels.level
+	if level > LEVEL_INFO {
+		// a syslog level steps from its nearest level on the scale
+		level = syslogLevels[Level(level).String()]
+	}
+	current := 0
+	for i, step := range order {
+		if int(step) == level {
+			current = i
+		}
+	}
+	next := current + steps
+	if next < 0 {
+		next = 0
+	}
+	if next >= len(order) {
+		next = len(order) - 1
+	}
+	previous := Level(l.levels.level)
+	l.levels.level = int(order[next])
+	l.levels.mu.Unlock()
+	// written as a notice past a copy of the levels, so the change is logged whatever the new level
+	notice := *l
+	notice.levels = &levels{level: LEVEL_INFO}
+	notice.With(String("from", previous.String()), String("to", order[next].String())).
+		Chain().write("log level changed", NOTICE)
+	return order[next]
+}
+
+// VerbosityOnSignal raises the log level one step whenever the process receives raise and
+// lowers it one step on lower. A nil signal is its default, SIGUSR1 to raise and SIGUSR2 to
+// lower, where the platform has them; otherwise it is not handled. The returned function
+// stops handling the signals
+func (l *Log) VerbosityOnSignal(raise, lower os.Signal) (stop func()) {
+	var sigs []os.Signal
+	if raise == nil && len(verbositySignals) == 2 {
+		raise = verbositySignals[0]
+	}
+	if lower == nil && len(verbositySignals) == 2 {
+		lower = verbositySignals[1]
+	}
+	for _, sig := range []os.Signal{raise, lower} {
+		if sig != nil {
+			sigs = append(sigs, sig)
+		}
+	}
+	return onSignal(sigs, func(sig os.Signal) {
+		if sig == raise {
+			l.AdjustVerbosity(1)
+		} else {
+			l.AdjustVerbosity(-1)
+		}
+	})
+}
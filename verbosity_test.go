@@ -0,0 +1,29 @@
+package logging
+
+import "testing"
+
+func TestAdjustVerbosity(t *testing.T) {
+	c := NewCapture()
+	c.SetLevel(LevelWarning)
+	if level := c.AdjustVerbosity(1); level != LevelDebug {
+		t.Errorf("expected one step up from warning to be debug, got %s", level)
+	}
+	if level := c.AdjustVerbosity(5); level != LevelInfo {
+		t.Errorf("expected the level to stop at the most verbose, got %s", level)
+	}
+	if level := c.AdjustVerbosity(-10); level != LevelNone {
+		t.Errorf("expected the level to stop at none, got %s", level)
+	}
+	entries := c.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected every change to be logged, got %v", entries)
+	}
+	if from, _ := entries[0].Field("from"); from != WARNING || entries[2].Level != NOTICE {
+		t.Errorf("expected the change to be a notice recording the previous level, got %v", entries[0])
+	}
+	standard := NewCapture(WithStandardLevels())
+	standard.SetLevel(LevelInfo)
+	if level := standard.AdjustVerbosity(1); level != LevelDebug {
+		t.Errorf("expected debug to be more verbose than info in the standard ordering, got %s", level)
+	}
+}
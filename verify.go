@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VerifyError identifies the first record of a log file that failed verification
+type VerifyError struct {
+	Record int   // the number of the record, counting from 1
+	Offset int64 // the offset of the record in the file
+	Reason string
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("record %d at offset %d %s", e.Record, e.Offset, e.Reason)
+}
+
+// Verify checks that a log file parses cleanly: every record is well formed and no record
+// is older than the one before it. If any record has a hash chain (see WithHashChain), every
+// record must have one and the chain must be intact, so stripping hashes is detected. The
+// file must end with a complete record. It returns a *VerifyError for the first record that
+// fails; see Log.Verify for a log on another FS or encrypted
+func Verify(path string) error {
+	return verifyFile(osFS{}, path, nil)
+}
+
+// Verify checks the log's file as Verify does, reading it through the log's FS and
+// decrypting its records with the log's key if it is encrypted (see WithEncryption)
+func (l *Log) Verify() error {
+	if l.path == "" {
+		return nil
+	}
+	return verifyFile(l.getFS(), l.path, l.cipher)
+}
+
+// verifyFile is Verify, decrypting each line of the file as a record if c is not nil
+func verifyFile(fs FS, path string, c *recordCipher) error {
+	b, err := readAll(fs, path)
+	if err != nil {
+		return err
+	}
+	var (
+		prevTime time.Time
+		prevHash string
+		chained  bool
+	)
+	offsets, records := recordOffsets(string(b))
+	if c != nil {
+		offsets, records = lineOffsets(string(b))
+		for i, record := range records {
+			if i == len(records)-1 && !strings.HasSuffix(string(b), "\n") {
+				break // reported as incomplete below
+			}
+			if records[i], err = c.decrypt(record); err != nil {
+				return &VerifyError{Record: i + 1, Offset: offsets[i], Reason: "cannot be decrypted: " + err.Error()}
+			}
+		}
+	}
+	for _, record := range records {
+		if _, _, ok := chainSuffix.split(record); ok {
+			chained = true
+			break
+		}
+	}
+	for i, record := range records {
+		fail := func(format string, args ...interface{}) error {
+			return &VerifyError{Record: i + 1, Offset: offsets[i], Reason: fmt.Sprintf(format, args...)}
+		}
+		if i == len(records)-1 && !strings.HasSuffix(string(b), "\n") {
+			return fail("is incomplete")
+		}
+		e, err := ParseEntry(record)
+		if err != nil {
+			return fail("is malformed")
+		}
+		if e.Time.Before(prevTime) {
+			return fail("is out of order: %s is before %s", e.Time.Format(time.RFC3339Nano), prevTime.Format(time.RFC3339Nano))
+		}
+		prevTime = e.Time
+		if !chained {
+			continue
+		}
+		body, hash, ok := chainSuffix.split(record)
+		if !ok {
+			return fail("has no chain hash")
+		}
+		expected := chainHash(prevHash, body)
+		if hash != expected {
+			return fail("failed verification: expected hash %s, got %s", expected, hash)
+		}
+		prevHash = hash
+	}
+	return nil
+}
+
+// lineOffsets splits the content of an encrypted file into its lines, one record each, also
+// returning the offset of each
+func lineOffsets(content string) ([]int64, []string) {
+	offsets, lines := make([]int64, 0), make([]string, 0)
+	var offset int64
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if trimLineEnd(line) != "" {
+			offsets, lines = append(offsets, offset), append(lines, trimLineEnd(line))
+		}
+		offset += int64(len(line))
+	}
+	return offsets, lines
+}
+
+// recordOffsets splits file content into records as splitRecords does, also returning the
+// offset of each. Lines before the first record are returned as a record of their own
+func recordOffsets(content string) ([]int64, []string) {
+	offsets, records := make([]int64, 0), make([]string, 0)
+	var offset int64
+	for _, line := range strings.SplitAfter(strings.TrimSuffix(content, "\n"), "\n") {
+		if len(records) == 0 || isRecordStart(trimLineEnd(line)) {
+			offsets, records = append(offsets, offset), append(records, "")
+		}
+		records[len(records)-1] += line
+		offset += int64(len(line))
+	}
+	for i := range records {
+		records[i] = trimLineEnd(records[i])
+	}
+	if len(records) == 1 && records[0] == "" {
+		return nil, nil
+	}
+	return offsets, records
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	for name, test := range map[string]struct {
+		content string
+		record  int
+		offset  int64
+	}{
+		"valid":     {"[2024-01-02T00:00:00Z] [TEST.INFO] one\n[2024-01-02T00:00:01Z] [TEST.INFO] two\n  continued\n", 0, 0},
+		"empty":     {"", 0, 0},
+		"malformed": {"[2024-01-02T00:00:00Z] [TEST.INFO] one\n[2024-01-02T00:00:01Z broken\n", 2, 39},
+		"leading":   {"garbage\n[2024-01-02T00:00:00Z] [TEST.INFO] one\n", 1, 0},
+		"reordered": {"[2024-01-02T00:00:01Z] [TEST.INFO] one\n[2024-01-02T00:00:00Z] [TEST.INFO] two\n", 2, 39},
+	} {
+		path := filepath.Join(dir, name+".log")
+		os.WriteFile(path, []byte(test.content), 0644)
+		err := Verify(path)
+		if test.record == 0 {
+			if err != nil {
+				t.Errorf("%s: expected the file to verify, got %s", name, err.Error())
+			}
+			continue
+		}
+		var vErr *VerifyError
+		if !errors.As(err, &vErr) || vErr.Record != test.record || vErr.Offset != test.offset {
+			t.Errorf("%s: expected record %d at offset %d to fail, got %v", name, test.record, test.offset, err)
+		}
+	}
+}
+
+func TestVerifyStrippedChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	al, err := NewLog(path, "TEST", LEVEL_INFO, LEVEL_NONE, WithHashChain())
+	if err != nil {
+		t.Fatal(err)
+	}
+	al.Info("second")
+	b, _ := os.ReadFile(path)
+	first, rest, _ := strings.Cut(string(b), "\n")
+	body, _, _ := chainSuffix.split(first)
+	os.WriteFile(path, []byte(body+"\n"+rest), 0644)
+	var vErr *VerifyError
+	if err = Verify(path); !errors.As(err, &vErr) || vErr.Record != 1 || vErr.Reason != "has no chain hash" {
+		t.Errorf("expected the stripped hash to be detected, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package logging
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileHeaderEncoder is an Encoder whose files start with a header, such as W3CEncoder's
+// directives. The header is written whenever the log creates its file, including after a
+// rotation, unless the file is encrypted
+type FileHeaderEncoder interface {
+	Encoder
+	FileHeader(t time.Time) []byte
+}
+
+// DefaultW3CFields are the fields written by a W3CEncoder without Fields
+var DefaultW3CFields = []string{
+	"date", "time", "x-env", "x-level", "c-ip", "cs-username", "cs-method", "cs-uri-stem",
+	"cs-uri-query", "sc-status", "sc-bytes", "time-taken", "cs(User-Agent)", "cs(Referer)", "x-message",
+}
+
+// w3cAttrs are the attributes written by Middleware that W3C fields are taken from
+var w3cAttrs = map[string]string{
+	"cs-username":    "user",
+	"cs-method":      "method",
+	"cs-uri-stem":    "path",
+	"cs-uri-query":   "query",
+	"cs-version":     "proto",
+	"sc-status":      "status",
+	"sc-bytes":       "bytes",
+	"cs(User-Agent)": "user_agent",
+	"cs(Referer)":    "referer",
+}
+
+var w3cEscaper = strings.NewReplacer(" ", "+", "\t", "+", "\r", "+", "\n", "+")
+
+// W3CEncoder formats entries in the W3C extended log file format read by IIS tooling, one
+// space separated line per entry with the columns named by the #Fields directive at the top
+// of each file. The date and time are in UTC and time-taken is in milliseconds; the request
+// fields are taken from the attributes written by Middleware, x-env, x-level and x-message
+// from the entry, and any other field from the attribute or field of the same name. Missing
+// values are written as "-" and spaces in values as "+"
+type W3CEncoder struct {
+	Fields []string // DefaultW3CFields if empty
+}
+
+func (w W3CEncoder) fields() []string {
+	if len(w.Fields) == 0 {
+		return DefaultW3CFields
+	}
+	return w.Fields
+}
+
+// FileHeader returns the directives starting each file
+func (w W3CEncoder) FileHeader(t time.Time) []byte {
+	return []byte(fmt.Sprintf("#Software: github.com/blainemoser/Logging\n#Version: 1.0\n#Date: %s\n#Fields: %s\n",
+		t.UTC().Format("2006-01-02 15:04:05"), strings.Join(w.fields(), " ")))
+}
+
+func (w W3CEncoder) AppendEntry(dst []byte, e Entry) []byte {
+	for i, field := range w.fields() {
+		if i > 0 {
+			dst = append(dst, ' ')
+		}
+		value := w3cValue(e, field)
+		if value == "" {
+			value = "-"
+		}
+		dst = append(dst, w3cEscaper.Replace(value)...)
+	}
+	return dst
+}
+
+func w3cValue(e Entry, field string) string {
+	switch field {
+	case "date":
+		return e.Time.UTC().Format("2006-01-02")
+	case "time":
+		return e.Time.UTC().Format("15:04:05")
+	case "x-env":
+		return e.Env
+	case "x-level":
+		return e.Level
+	case "x-message":
+		return e.Message
+	case "c-ip":
+		addr := attrString(e, "remote_addr")
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			return host
+		}
+		return addr
+	case "time-taken":
+		if latency, ok := e.Field("latency"); ok {
+			if d, ok := latency.(time.Duration); ok {
+				return strconv.FormatInt(d.Milliseconds(), 10)
+			}
+		}
+		return ""
+	}
+	if key, ok := w3cAttrs[field]; ok {
+		return attrString(e, key)
+	}
+	return attrString(e, field)
+}
+
+// attrString returns an entry's attribute or field formatted as text, or "" if it has none
+func attrString(e Entry, key string) string {
+	v, ok := e.Field(key)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// w3cForm is a record written by a W3CEncoder with the default fields
+var w3cForm = regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}( \S+){13}$`)
+
+// parseW3CEntry parses a record written by a W3CEncoder with the default fields, reading "+"
+// back as spaces. The fields other than date, time, x-env, x-level and x-message are read
+// into Fields as strings, named as the attributes they were taken from where there is one
+func parseW3CEntry(record string) (Entry, error) {
+	if !w3cForm.MatchString(record) {
+		return Entry{}, ErrMalformedRecord
+	}
+	values := strings.Split(record, " ")
+	t, err := time.Parse("2006-01-02 15:04:05", values[0]+" "+values[1])
+	if err != nil {
+		return Entry{}, ErrMalformedRecord
+	}
+	e := Entry{Time: t}
+	for i, field := range DefaultW3CFields[2:] {
+		value := values[i+2]
+		if value == "-" {
+			continue
+		}
+		value = strings.ReplaceAll(value, "+", " ")
+		switch field {
+		case "x-env":
+			e.Env = value
+		case "x-level":
+			e.Level = value
+		case "x-message":
+			e.Message = value
+		default:
+			if e.Fields == nil {
+				e.Fields = Fields{}
+			}
+			if key, ok := w3cAttrs[field]; ok {
+				field = key
+			}
+			e.Fields[field] = value
+		}
+	}
+	return e, nil
+}
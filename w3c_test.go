@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestW3CEncoder(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	wl, err := NewLog("/logs/access.log", "web", LEVEL_INFO, LEVEL_NONE, WithFS(NewMemFS()),
+		WithNow(func() time.Time { return now }), WithEncoder(W3CEncoder{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := "#Software: github.com/blainemoser/Logging\n#Version: 1.0\n#Date: 2024-01-02 15:04:05\n" +
+		"#Fields: " + strings.Join(DefaultW3CFields, " ") + "\n"
+	content, _ := wl.readFile("/logs/access.log")
+	if !strings.HasPrefix(string(content), header) {
+		t.Fatalf("expected the file to start with the directives, got %q", content)
+	}
+	if _, err = wl.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	handler := wl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	req := httptest.NewRequest("GET", "/index.html?page=2", nil)
+	req.RemoteAddr = "10.0.0.7:5000"
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11)")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	content, _ = wl.readFile("/logs/access.log")
+	expected := header + "2024-01-02 15:04:05 web INFO 10.0.0.7 - GET /index.html page=2 200 5 0 Mozilla/5.0+(X11) - GET+/index.html+200\n"
+	if string(content) != expected {
+		t.Errorf("expected the rotated file to start with the directives, got %q", content)
+	}
+}
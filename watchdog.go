@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ErrProbeTimeout is reported by the watchdog when a probe of the write path does not finish
+// within the latency budget
+var ErrProbeTimeout = errors.New("write path probe exceeded its latency budget")
+
+const (
+	defaultWatchdogInterval = 30 * time.Second
+	defaultWatchdogBudget   = time.Second
+)
+
+// WatchdogPolicy configures WithWatchdog
+type WatchdogPolicy struct {
+	// Interval is how often the write path is probed (default 30 seconds)
+	Interval time.Duration
+	// Budget is how long a probe may take before it counts as failed (default 1 second)
+	Budget time.Duration
+	// Failures is the number of failed or slow probes in a row that trips the watchdog (default 1)
+	Failures int
+	// Alert, if not nil, receives a CRITICAL entry when the watchdog trips, so it should not
+	// depend on the log it watches
+	Alert Sink
+}
+
+type watchdog struct {
+	policy   WatchdogPolicy
+	failures int
+	tripped  bool
+	probing  atomic.Bool
+}
+
+// watch probes the write path every interval until the log is closed
+func (l *Log) watch() {
+	ticker := time.NewTicker(l.watchdog.policy.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-l.done:
+			return
+		}
+		l.checkWritePath()
+	}
+}
+
+// checkWritePath runs a probe, waiting for it no longer than the budget. A probe still
+// running from an earlier check counts as another failure rather than being started again,
+// so at most one probe is left waiting on a write path that hangs
+func (l *Log) checkWritePath() {
+	w := l.watchdog
+	if !w.probing.CompareAndSwap(false, true) {
+		l.watchdogResult(ErrProbeTimeout)
+		return
+	}
+	done := make(chan error, 1)
+	go func() {
+		defer w.probing.Store(false)
+		done <- l.probe()
+	}()
+	timer := time.NewTimer(w.policy.Budget)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		l.watchdogResult(err)
+	case <-timer.C:
+		l.watchdogResult(ErrProbeTimeout)
+	case <-l.done:
+	}
+}
+
+// probe checks the write path with Healthy, then flushes the log file to disk. It gives up
+// once the log is closed, so that a probe held up behind a stuck write does not reopen the
+// file when the write returns
+func (l *Log) probe() error {
+	if err := l.Healthy(); err != nil {
+		return err
+	}
+	if l.path == "" {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.isClosed() {
+		return ErrClosed
+	}
+	file, err := l.outputFile()
+	if err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// watchdogResult counts a failed probe, tripping the watchdog when enough fail in a row, and
+// resets it when a probe succeeds. It is only called from the watch goroutine
+func (l *Log) watchdogResult(err error) {
+	w := l.watchdog
+	if err == nil {
+		w.failures, w.tripped = 0, false
+		return
+	}
+	if w.failures++; w.failures < w.policy.Failures || w.tripped {
+		return
+	}
+	w.tripped = true
+	err = fmt.Errorf("logging watchdog: %d probes of the write path failed in a row: %w", w.failures, err)
+	l.handleError(err)
+	if w.policy.Alert != nil {
+		if aErr := w.policy.Alert.WriteEntry(l.newEntry(CRITICAL, err.Error())); aErr != nil {
+			l.handleError(aErr)
+		}
+	}
+}
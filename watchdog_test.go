@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// stalledSink accepts entries but its check blocks until released
+type stalledSink struct {
+	release chan struct{}
+}
+
+func (s *stalledSink) WriteEntry(e Entry) error {
+	return nil
+}
+
+func (s *stalledSink) Check() error {
+	<-s.release
+	return nil
+}
+
+func TestWatchdog(t *testing.T) {
+	stalled := &stalledSink{release: make(chan struct{})}
+	alerts := NewRingBuffer(10)
+	wl, err := NewLog("/logs/app.log", "TEST", LEVEL_INFO, LEVEL_NONE, WithFS(NewMemFS()), WithSink(stalled),
+		WithWatchdog(WatchdogPolicy{Interval: 20 * time.Millisecond, Budget: 5 * time.Millisecond, Failures: 2, Alert: alerts}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wl.Close()
+	handled := make(chan error, 10)
+	wl.SetErrorHandler(func(err error) { handled <- err })
+	select {
+	case err = <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the watchdog to trip")
+	}
+	if !errors.Is(err, ErrProbeTimeout) {
+		t.Errorf("expected a probe timeout, got %v", err)
+	}
+	close(stalled.release)
+	time.Sleep(100 * time.Millisecond)
+	if len(handled) != 0 {
+		t.Errorf("expected the watchdog to fire once, got %v", <-handled)
+	}
+	entries := alerts.Entries()
+	if len(entries) != 1 || entries[0].Level != CRITICAL {
+		t.Errorf("expected a CRITICAL alert, got %v", entries)
+	}
+}
@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldsError is implemented by errors carrying their own diagnostic context. WithError merges
+// the fields into the entry
+type FieldsError interface {
+	error
+	Fields() map[string]interface{}
+}
+
+// LogValuer is implemented by errors that describe themselves as attributes. WithError merges
+// the attributes into the entry
+type LogValuer interface {
+	LogValue() []Attr
+}
+
+// WithError returns a child log attaching the error to every entry: its message as "error",
+// its type as "error_type" and, for wrapped errors, the types of the chain as "error_chain".
+// Errors in the chain implementing FieldsError or LogValuer contribute their own attributes;
+// where two define the same key, the outermost wins.
+// With WithErrorExpansion, the message of every wrapped or joined cause is added as "error.1",
+// "error.2" and so on
+func (l *Log) WithError(err error) *Log {
+	if err == nil {
+		return l
+	}
+	attrs := []Attr{Err(err), String("error_type", fmt.Sprintf("%T", err))}
+	if chain := errorChain(err); len(chain) > 1 {
+		attrs = append(attrs, String("error_chain", strings.Join(chain, " > ")))
+	}
+	attrs = append(attrs, errorAttrs(err)...)
+	if l.expandErrors {
+		for i, cause := range errorCauses(err) {
+			attrs = append(attrs, String(fmt.Sprintf("error.%d", i+1), cause.Error()))
+		}
+	}
+	return l.With(attrs...)
+}
+
+// errorCauses returns every error wrapped by err, depth first, following both
+// Unwrap() error and the Unwrap() []error of joined errors
+func errorCauses(err error) []error {
+	var wrapped []error
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		wrapped = u.Unwrap()
+	case interface{ Unwrap() error }:
+		if cause := u.Unwrap(); cause != nil {
+			wrapped = []error{cause}
+		}
+	}
+	causes := make([]error, 0)
+	for _, cause := range wrapped {
+		if cause == nil {
+			continue
+		}
+		causes = append(causes, cause)
+		causes = append(causes, errorCauses(cause)...)
+	}
+	return causes
+}
+
+// errorChain returns the types of the error and every error it wraps
+func errorChain(err error) []string {
+	chain := make([]string, 0)
+	for ; err != nil; err = errors.Unwrap(err) {
+		chain = append(chain, fmt.Sprintf("%T", err))
+	}
+	return chain
+}
+
+// errorAttrs collects the attributes of every FieldsError and LogValuer in the error's causes
+func errorAttrs(err error) []Attr {
+	attrs := make([]Attr, 0)
+	seen := make(map[string]bool)
+	add := func(a Attr) {
+		if seen[a.Key] {
+			return
+		}
+		seen[a.Key] = true
+		attrs = append(attrs, a)
+	}
+	for _, e := range append([]error{err}, errorCauses(err)...) {
+		if v, ok := e.(LogValuer); ok {
+			for _, a := range v.LogValue() {
+				add(a)
+			}
+		}
+		if f, ok := e.(FieldsError); ok {
+			for _, a := range fieldAttrs(f.Fields()) {
+				add(a)
+			}
+		}
+	}
+	return attrs
+}
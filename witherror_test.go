@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWithError(t *testing.T) {
+	c := NewCapture()
+	_, statErr := os.Stat("/does/not/exist")
+	err := fmt.Errorf("loading config: %w", statErr)
+	result, _ := c.WithError(err).Warning("retrying")
+	for _, expected := range []string{
+		"retrying error=\"loading config: stat /does/not/exist: ",
+		"error_type=*fmt.wrapError",
+		"error_chain=\"*fmt.wrapError > *fs.PathError > syscall.Errno\"",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("expected result to contain '%s', got '%s'", expected, result)
+		}
+	}
+	if c.WithError(nil) != c.Log {
+		t.Error("expected a nil error to return the same log")
+	}
+}
+
+// joinedError mirrors the errors.Join implementation
+type joinedError struct {
+	errs []error
+}
+
+func (e *joinedError) Error() string {
+	return "multiple failures"
+}
+
+func (e *joinedError) Unwrap() []error {
+	return e.errs
+}
+
+func TestErrorExpansion(t *testing.T) {
+	c := NewCapture(WithErrorExpansion())
+	err := fmt.Errorf("sync failed: %w", &joinedError{errs: []error{
+		fmt.Errorf("shard 1: %w", os.ErrPermission),
+		os.ErrDeadlineExceeded,
+	}})
+	c.WithError(err).Error("sync")
+	e := c.Entries()[0]
+	expected := map[string]string{
+		"error.1": "multiple failures",
+		"error.2": "shard 1: permission denied",
+		"error.3": "permission denied",
+		"error.4": "i/o timeout",
+	}
+	for key, message := range expected {
+		if v, ok := e.Field(key); !ok || v != message {
+			t.Errorf("expected %s to be '%s', got %v", key, message, v)
+		}
+	}
+	if _, ok := e.Field("error.5"); ok {
+		t.Error("expected only four causes")
+	}
+}
+
+type orderError struct {
+	order string
+	cause error
+}
+
+func (e *orderError) Error() string {
+	return "order " + e.order + " failed"
+}
+
+func (e *orderError) Unwrap() error {
+	return e.cause
+}
+
+func (e *orderError) Fields() map[string]interface{} {
+	return map[string]interface{}{"order": e.order, "retryable": false}
+}
+
+type paymentError struct {
+	code int
+}
+
+func (e paymentError) Error() string {
+	return "payment declined"
+}
+
+func (e paymentError) LogValue() []Attr {
+	return []Attr{Int("code", e.code), String("order", "inner")}
+}
+
+func TestStructuredErrors(t *testing.T) {
+	c := NewCapture()
+	err := fmt.Errorf("checkout: %w", &orderError{order: "A-17", cause: paymentError{code: 51}})
+	c.WithError(err).Error("checkout")
+	e := c.Entries()[0]
+	if v, _ := e.Field("order"); v != "A-17" {
+		t.Errorf("expected the outermost order field to win, got %v", v)
+	}
+	if v, _ := e.Field("retryable"); v != false {
+		t.Errorf("expected retryable to be false, got %v", v)
+	}
+	if v, _ := e.Field("code"); v != int64(51) {
+		t.Errorf("expected code 51 from the wrapped error, got %v", v)
+	}
+}
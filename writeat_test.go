@@ -0,0 +1,23 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWriteAt(t *testing.T) {
+	c := NewCapture(WithSequence(), WithTimePrecision(time.Millisecond))
+	original := time.Date(2022, 11, 5, 8, 30, 0, 250e6, time.FixedZone("SAST", 2*60*60))
+	result, err := c.WriteAt(original, WARNING, "imported")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "[2022-11-05T06:30:00.250Z] [TEST.WARNING] imported seq=2" {
+		t.Errorf("expected the original timestamp in UTC, got %s", result)
+	}
+	c.WriteBatch([]Entry{{Time: original.Add(time.Second), Level: INFO, Message: "batched"}})
+	e := c.Entries()[1]
+	if e.String() != "[2022-11-05T06:30:01.250Z] [TEST.INFO] batched seq=3" {
+		t.Errorf("expected batched entries to keep their timestamp, precision and sequence, got %s", e)
+	}
+}
@@ -0,0 +1,115 @@
+package logging
+
+import (
+	"bytes"
+	"regexp"
+	"sync"
+)
+
+// LevelWriter is an io.Writer turning each line written to it into an entry at its level.
+// A trailing partial line is held until it is completed or the writer is flushed
+type LevelWriter struct {
+	l     *Log
+	level string
+	mu    sync.Mutex
+	buf   []byte
+	// group joins indented continuation lines, such as stack frames, onto the line before
+	group   bool
+	pending []byte
+	rules   []LevelRule
+}
+
+// LevelRule assigns a level to the lines matching a pattern; see ClassifyingWriter
+type LevelRule struct {
+	Pattern *regexp.Regexp
+	Level   string
+}
+
+// WriterLevel returns a writer logging each line written to it at the given level, for
+// wiring the output of libraries and print statements into the log
+func (l *Log) WriterLevel(level string) *LevelWriter {
+	return &LevelWriter{l: l, level: level}
+}
+
+// ClassifyingWriter returns a writer logging each line written to it at the level of the
+// first rule whose pattern matches it, or at the default level if none does, for capturing the
+// output of subprocesses with their own formats:
+//
+//	w := l.ClassifyingWriter(INFO,
+//		LevelRule{regexp.MustCompile(`ERROR|panic`), ERROR},
+//		LevelRule{regexp.MustCompile(`(?i)warn`), WARNING})
+func (l *Log) ClassifyingWriter(defaultLevel string, rules ...LevelRule) *LevelWriter {
+	return &LevelWriter{l: l, level: defaultLevel, rules: rules}
+}
+
+func (w *LevelWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimSuffix(w.buf[:i], []byte{'\r'})
+		w.buf = w.buf[i+1:]
+		if err := w.addLine(line); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush logs any partial or grouped lines held by the writer
+func (w *LevelWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		line := w.buf
+		w.buf = nil
+		if err := w.addLine(line); err != nil {
+			return err
+		}
+	}
+	pending := w.pending
+	w.pending = nil
+	return w.writeLine(pending)
+}
+
+// Close flushes the writer
+func (w *LevelWriter) Close() error {
+	return w.Flush()
+}
+
+// addLine logs a complete line, or holds it when grouping until the next line shows
+// whether it continues
+func (w *LevelWriter) addLine(line []byte) error {
+	if !w.group {
+		return w.writeLine(line)
+	}
+	if w.pending != nil && len(line) > 0 && (line[0] == ' ' || line[0] == '\t') {
+		w.pending = append(append(w.pending, '\n'), line...)
+		return nil
+	}
+	pending := w.pending
+	w.pending = append([]byte(nil), line...)
+	return w.writeLine(pending)
+}
+
+func (w *LevelWriter) writeLine(line []byte) error {
+	if len(line) == 0 {
+		return nil
+	}
+	_, err := w.l.Write(string(line), w.classify(line))
+	return err
+}
+
+// classify returns the level of the first rule matching the line, or the writer's level
+func (w *LevelWriter) classify(line []byte) string {
+	for _, rule := range w.rules {
+		if rule.Pattern.Match(line) {
+			return rule.Level
+		}
+	}
+	return w.level
+}
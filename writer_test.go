@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"fmt"
+	stdlog "log"
+	"regexp"
+	"testing"
+)
+
+func TestWriterLevel(t *testing.T) {
+	c := NewCapture()
+	w := c.WriterLevel(ERROR)
+	fmt.Fprint(w, "first\nsec")
+	fmt.Fprint(w, "ond\r\n\npartial")
+	if len(c.Entries()) != 2 || !c.HasEntry(ERROR, "first") || !c.HasEntry(ERROR, "second") {
+		t.Fatalf("expected two complete lines to be logged, got %v", c.Entries())
+	}
+	w.Flush()
+	if !c.HasEntry(ERROR, "partial") {
+		t.Errorf("expected the partial line to be logged on flush, got %v", c.Entries())
+	}
+	stdlog.New(c.WriterLevel(WARNING), "", 0).Println("legacy")
+	if !c.HasEntry(WARNING, "legacy") {
+		t.Errorf("expected a standard logger to write through the writer, got %v", c.Entries())
+	}
+}
+
+func TestClassifyingWriter(t *testing.T) {
+	c := NewCapture()
+	w := c.ClassifyingWriter(INFO,
+		LevelRule{regexp.MustCompile(`ERROR|panic`), ERROR},
+		LevelRule{regexp.MustCompile(`(?i)warn`), WARNING},
+	)
+	fmt.Fprint(w, "listening on :8080\nWarn: cache cold\npanic: nil map\n")
+	expected := []string{INFO, WARNING, ERROR}
+	for i, e := range c.Entries() {
+		if e.Level != expected[i] {
+			t.Errorf("expected '%s' at %s, got %s", e.Message, expected[i], e.Level)
+		}
+	}
+}